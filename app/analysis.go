@@ -0,0 +1,451 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+// analysisSearchBudget bounds the vectors and minors examined for branch
+// numbers and the MDS check; properties that need more are left unknown
+const analysisSearchBudget = 1 << 18
+
+// binaryField is GF(2), used to analyze matrices without a field representation
+var binaryField = &GF2m{M: 1, Poly: 0x3}
+
+// MatrixAnalysis holds the cryptographic properties of a matrix over GF(2^m).
+// Weights count non-zero field elements; for matrices without a field
+// representation the field is GF(2) and weights count bits.
+type MatrixAnalysis struct {
+	FieldDegree        int    `json:"field_degree"` // Element size m, 1 for binary matrices
+	FieldPolynomial    string `json:"field_polynomial,omitempty"`
+	Rows               int    `json:"rows"` // Dimensions in field elements
+	Columns            int    `json:"columns"`
+	Invertible         bool   `json:"invertible"`
+	DifferentialBranch *int   `json:"differential_branch"` // min wt(x)+wt(Mx) over x != 0, nil if over budget
+	LinearBranch       *int   `json:"linear_branch"`       // Differential branch number of the transpose
+	MDS                *bool  `json:"is_mds"`              // Every square submatrix is non-singular
+	NearMDS            *bool  `json:"is_near_mds"`         // Square, not MDS, both branch numbers equal to the size
+	Involutory         bool   `json:"is_involutory"`       // M·M = I
+	SemiInvolutory     bool   `json:"is_semi_involutory"`  // M^-1 = D1·M·D2 for non-singular diagonal D1, D2
+	Orthogonal         bool   `json:"is_orthogonal"`       // M·M^T = I
+	Circulant          bool   `json:"is_circulant"`        // Every row is the previous one rotated right by one
+}
+
+// AnalyzeRecord analyzes a stored matrix over its field, or over GF(2) if it
+// has no field representation
+func AnalyzeRecord(record *MatrixRecord) (*MatrixAnalysis, error) {
+	binary, err := parseMatrixFromBinary(record.MatrixBinary)
+	if err != nil {
+		return nil, err
+	}
+
+	field, elements := binaryField, FieldMatrix(nil)
+	if record.FieldMatrix != nil && record.FieldPolynomial != nil {
+		if field, err = NewGF2m(*record.FieldPolynomial); err != nil {
+			return nil, err
+		}
+		if elements, err = field.ParseMatrix(record.FieldMatrix); err != nil {
+			return nil, err
+		}
+	} else if elements, err = binaryField.ParseMatrix(binary); err != nil {
+		return nil, err
+	}
+	return AnalyzeMatrix(field, elements), nil
+}
+
+// AnalyzeStoredMatrix analyzes a stored matrix and saves its flags
+func AnalyzeStoredMatrix(store MatrixStore, record *MatrixRecord) (*MatrixAnalysis, error) {
+	analysis, err := AnalyzeRecord(record)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.SaveAnalysis(record.ID, analysis); err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+// analyzeExistingRecords analyzes the stored matrices that have no analysis
+// yet, e.g. those saved before the analysis columns existed
+func (d *Database) analyzeExistingRecords() {
+	rows, err := d.db.Query("SELECT id FROM matrix_records WHERE analyzed_at IS NULL ORDER BY id")
+	if err != nil {
+		log.Printf("❌ [ANALYSIS] Analiz edilmemiş matrisler alınamadı: %v", err)
+		return
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+	if len(ids) == 0 {
+		return
+	}
+
+	log.Printf("🔄 [ANALYSIS] %d matris analiz ediliyor...", len(ids))
+	analyzed := 0
+	for _, id := range ids {
+		record, err := d.GetMatrixByID(id)
+		if err != nil || record == nil {
+			log.Printf("⚠️  [ANALYSIS] Matris %d alınamadı: %v", id, err)
+			continue
+		}
+		if _, err := AnalyzeStoredMatrix(d, record); err != nil {
+			log.Printf("⚠️  [ANALYSIS] Matris %d analiz edilemedi: %v", id, err)
+			continue
+		}
+		analyzed++
+	}
+	log.Printf("✅ [ANALYSIS] %d/%d matris analiz edildi", analyzed, len(ids))
+}
+
+// AnalyzeMatrix computes the properties of elements over field
+func AnalyzeMatrix(field *GF2m, elements FieldMatrix) *MatrixAnalysis {
+	rows, columns := len(elements), len(elements[0])
+	analysis := &MatrixAnalysis{
+		FieldDegree: field.M,
+		Rows:        rows,
+		Columns:     columns,
+	}
+	if field != binaryField {
+		analysis.FieldPolynomial = field.Polynomial()
+	}
+	square := rows == columns
+
+	budget := analysisSearchBudget
+	if mds, ok := field.allMinorsNonSingular(elements, &budget); ok {
+		analysis.MDS = &mds
+	}
+
+	if analysis.MDS != nil && *analysis.MDS {
+		differential, linear := rows+1, columns+1
+		analysis.DifferentialBranch, analysis.LinearBranch = &differential, &linear
+	} else {
+		budget = analysisSearchBudget
+		if branch, ok := field.branchNumber(elements, &budget); ok {
+			analysis.DifferentialBranch = &branch
+		}
+		budget = analysisSearchBudget
+		if branch, ok := field.branchNumber(transposeField(elements), &budget); ok {
+			analysis.LinearBranch = &branch
+		}
+	}
+	if square && analysis.MDS != nil && analysis.DifferentialBranch != nil && analysis.LinearBranch != nil {
+		nearMDS := !*analysis.MDS && *analysis.DifferentialBranch == rows && *analysis.LinearBranch == rows
+		analysis.NearMDS = &nearMDS
+	}
+
+	if !square {
+		return analysis
+	}
+	identity := identityField(rows)
+	analysis.Involutory = equalField(field.multiply(elements, elements), identity)
+	analysis.Orthogonal = equalField(field.multiply(elements, transposeField(elements)), identity)
+	analysis.Circulant = isCirculant(elements)
+
+	if inverse, err := field.inverse(elements); err == nil {
+		analysis.Invertible = true
+		analysis.SemiInvolutory = field.isDiagonalScaling(elements, inverse)
+	}
+	return analysis
+}
+
+// inverse returns the inverse matrix over the field, computed on the binary
+// expansion with calculateMatrixInverse; the inverse of a matrix of
+// multiplication blocks is again one
+func (f *GF2m) inverse(elements FieldMatrix) (FieldMatrix, error) {
+	inverse, err := calculateMatrixInverse(f.Expand(elements))
+	if err != nil {
+		return nil, err
+	}
+	result, ok := f.Compress(inverse)
+	if !ok {
+		return nil, fmt.Errorf("ters matris %s üzerinde değil", f)
+	}
+	return result, nil
+}
+
+// multiply returns a·b over the field
+func (f *GF2m) multiply(a, b FieldMatrix) FieldMatrix {
+	product := make(FieldMatrix, len(a))
+	for i := range a {
+		product[i] = make([]uint32, len(b[0]))
+		for j := range b[0] {
+			var sum uint32
+			for k := range b {
+				sum ^= f.Mul(a[i][k], b[k][j])
+			}
+			product[i][j] = sum
+		}
+	}
+	return product
+}
+
+// applySupport returns the weight of m·x for x with the given values on
+// support and zeros elsewhere
+func (f *GF2m) applySupport(m FieldMatrix, support []int, values []uint32) int {
+	weight := 0
+	for _, row := range m {
+		var y uint32
+		for i, j := range support {
+			y ^= f.Mul(row[j], values[i])
+		}
+		if y != 0 {
+			weight++
+		}
+	}
+	return weight
+}
+
+// branchNumber returns min wt(x) + wt(m·x) over non-zero x. Vectors are
+// enumerated by increasing weight until no heavier x can do better; ok is
+// false if that takes more than budget vectors.
+func (f *GF2m) branchNumber(m FieldMatrix, budget *int) (branch int, ok bool) {
+	columns := len(m[0])
+	branch = columns + len(m) + 1
+	for weight := 1; weight <= columns && weight < branch; weight++ {
+		exhausted := false
+		forEachSubset(columns, weight, func(support []int) bool {
+			values := make([]uint32, weight)
+			for i := range values {
+				values[i] = 1
+			}
+			for {
+				if *budget--; *budget < 0 {
+					exhausted = true
+					return false
+				}
+				if w := weight + f.applySupport(m, support, values); w < branch {
+					branch = w
+				}
+				// Next assignment of non-zero values to the support
+				i := 0
+				for i < weight && values[i] == (1<<uint(f.M))-1 {
+					values[i] = 1
+					i++
+				}
+				if i == weight {
+					return true
+				}
+				values[i]++
+			}
+		})
+		if exhausted {
+			return 0, false
+		}
+	}
+	return branch, true
+}
+
+// allMinorsNonSingular reports whether every square submatrix of m is
+// non-singular, checking small minors first; ok is false if the check needs
+// more than budget minors
+func (f *GF2m) allMinorsNonSingular(m FieldMatrix, budget *int) (mds bool, ok bool) {
+	rows, columns := len(m), len(m[0])
+	size := rows
+	if columns < size {
+		size = columns
+	}
+	for s := 1; s <= size; s++ {
+		nonSingular, exhausted := true, false
+		forEachSubset(rows, s, func(rowSet []int) bool {
+			forEachSubset(columns, s, func(columnSet []int) bool {
+				if *budget--; *budget < 0 {
+					exhausted = true
+					return false
+				}
+				sub := make(FieldMatrix, s)
+				for i, r := range rowSet {
+					sub[i] = make([]uint32, s)
+					for j, c := range columnSet {
+						sub[i][j] = m[r][c]
+					}
+				}
+				nonSingular = f.nonSingular(sub)
+				return nonSingular
+			})
+			return nonSingular && !exhausted
+		})
+		if exhausted {
+			return false, false
+		}
+		if !nonSingular {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+// nonSingular reports whether the square matrix has full rank; m is modified
+func (f *GF2m) nonSingular(m FieldMatrix) bool {
+	n := len(m)
+	for col := 0; col < n; col++ {
+		pivot := -1
+		for r := col; r < n; r++ {
+			if m[r][col] != 0 {
+				pivot = r
+				break
+			}
+		}
+		if pivot < 0 {
+			return false
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		inv := f.Inv(m[col][col])
+		for r := col + 1; r < n; r++ {
+			if m[r][col] == 0 {
+				continue
+			}
+			factor := f.Mul(m[r][col], inv)
+			for c := col; c < n; c++ {
+				m[r][c] ^= f.Mul(factor, m[col][c])
+			}
+		}
+	}
+	return true
+}
+
+// isDiagonalScaling reports whether inverse = D1·m·D2 for non-singular
+// diagonal D1, D2, i.e. inverse[i][j] = d1[i]·m[i][j]·d2[j]. The zero patterns
+// must agree; the ratios are then propagated over the bipartite graph of
+// non-zero entries and checked for consistency.
+func (f *GF2m) isDiagonalScaling(m, inverse FieldMatrix) bool {
+	n := len(m)
+	ratio := make([][]uint32, n)
+	for i := range m {
+		ratio[i] = make([]uint32, n)
+		for j := range m[i] {
+			if (m[i][j] == 0) != (inverse[i][j] == 0) {
+				return false
+			}
+			if m[i][j] != 0 {
+				ratio[i][j] = f.Mul(inverse[i][j], f.Inv(m[i][j]))
+			}
+		}
+	}
+
+	// d1[i]·d2[j] = ratio[i][j]; 0 marks unassigned scalars
+	d1 := make([]uint32, n)
+	d2 := make([]uint32, n)
+	for start := 0; start < n; start++ {
+		if d1[start] != 0 {
+			continue
+		}
+		d1[start] = 1
+		queue := []int{start} // Row indices, columns are stored as n+j
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			if v < n {
+				for j := 0; j < n; j++ {
+					if ratio[v][j] == 0 {
+						continue
+					}
+					want := f.Mul(ratio[v][j], f.Inv(d1[v]))
+					if d2[j] == 0 {
+						d2[j] = want
+						queue = append(queue, n+j)
+					} else if d2[j] != want {
+						return false
+					}
+				}
+			} else {
+				j := v - n
+				for i := 0; i < n; i++ {
+					if ratio[i][j] == 0 {
+						continue
+					}
+					want := f.Mul(ratio[i][j], f.Inv(d2[j]))
+					if d1[i] == 0 {
+						d1[i] = want
+						queue = append(queue, i)
+					} else if d1[i] != want {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// isCirculant reports whether every row is the previous row rotated right by one
+func isCirculant(m FieldMatrix) bool {
+	n := len(m)
+	for i := 1; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if m[i][j] != m[i-1][(j+n-1)%n] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// transposeField returns the transpose of m
+func transposeField(m FieldMatrix) FieldMatrix {
+	t := make(FieldMatrix, len(m[0]))
+	for j := range t {
+		t[j] = make([]uint32, len(m))
+		for i := range m {
+			t[j][i] = m[i][j]
+		}
+	}
+	return t
+}
+
+// identityField returns the n×n identity matrix
+func identityField(n int) FieldMatrix {
+	identity := make(FieldMatrix, n)
+	for i := range identity {
+		identity[i] = make([]uint32, n)
+		identity[i][i] = 1
+	}
+	return identity
+}
+
+// equalField reports whether a and b are equal
+func equalField(a, b FieldMatrix) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// forEachSubset calls visit with every k-element subset of 0..n-1 in
+// lexicographic order until visit returns false
+func forEachSubset(n, k int, visit func(subset []int) bool) {
+	subset := make([]int, k)
+	for i := range subset {
+		subset[i] = i
+	}
+	for {
+		if !visit(subset) {
+			return
+		}
+		i := k - 1
+		for i >= 0 && subset[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		subset[i]++
+		for j := i + 1; j < k; j++ {
+			subset[j] = subset[j-1] + 1
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func boolPtr(v bool) *bool { return &v }
+
+func TestAnalyzeMatrix(t *testing.T) {
+	field, err := NewGF2m("x^4+x+1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		rows [][]string
+		want MatrixAnalysis
+	}{
+		{
+			name: "mds circ(1,1,2)",
+			rows: mdsMatrix,
+			want: MatrixAnalysis{
+				Invertible: true, DifferentialBranch: intPtr(4), LinearBranch: intPtr(4),
+				MDS: boolPtr(true), NearMDS: boolPtr(false), Circulant: true,
+			},
+		},
+		{
+			// The Midori matrix circ(0,1,1,1) is involutory and symmetric
+			name: "midori",
+			rows: [][]string{{"0", "1", "1", "1"}, {"1", "0", "1", "1"}, {"1", "1", "0", "1"}, {"1", "1", "1", "0"}},
+			want: MatrixAnalysis{
+				Invertible: true, DifferentialBranch: intPtr(4), LinearBranch: intPtr(4),
+				MDS: boolPtr(false), NearMDS: boolPtr(true),
+				Involutory: true, SemiInvolutory: true, Orthogonal: true, Circulant: true,
+			},
+		},
+		{
+			// diag(1,2,1) is its own scaled inverse but not involutory
+			name: "diagonal",
+			rows: [][]string{{"1", "0", "0"}, {"0", "2", "0"}, {"0", "0", "1"}},
+			want: MatrixAnalysis{
+				Invertible: true, DifferentialBranch: intPtr(2), LinearBranch: intPtr(2),
+				MDS: boolPtr(false), NearMDS: boolPtr(false), SemiInvolutory: true,
+			},
+		},
+		{
+			// x = (1, 1) is mapped to zero, so a singular matrix can still reach branch number n
+			name: "singular",
+			rows: [][]string{{"1", "1"}, {"2", "2"}},
+			want: MatrixAnalysis{
+				DifferentialBranch: intPtr(2), LinearBranch: intPtr(2),
+				MDS: boolPtr(false), NearMDS: boolPtr(true),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		elements, err := field.ParseMatrix(tt.rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := AnalyzeMatrix(field, elements)
+		want := tt.want
+		want.FieldDegree, want.FieldPolynomial = 4, "x^4+x+1"
+		want.Rows, want.Columns = len(tt.rows), len(tt.rows[0])
+		if !equalAnalysis(got, &want) {
+			t.Errorf("%s: %s, beklenen %s", tt.name, formatAnalysis(got), formatAnalysis(&want))
+		}
+	}
+}
+
+func TestAnalyzeRecord(t *testing.T) {
+	field, err := NewGF2m("x^4+x+1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	elements, err := field.ParseMatrix(mdsMatrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	polynomial := field.Polynomial()
+	binary := field.Expand(elements)
+
+	// Over its field the expansion is MDS; as a bit matrix it is analyzed over GF(2)
+	record := &MatrixRecord{MatrixBinary: matrixToBinary(binary), FieldMatrix: mdsMatrix, FieldPolynomial: &polynomial}
+	analysis, err := AnalyzeRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analysis.FieldDegree != 4 || analysis.Rows != 3 || analysis.MDS == nil || !*analysis.MDS {
+		t.Errorf("alan matrisi: %s", formatAnalysis(analysis))
+	}
+
+	record.FieldMatrix, record.FieldPolynomial = nil, nil
+	analysis, err = AnalyzeRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analysis.FieldDegree != 1 || analysis.FieldPolynomial != "" || analysis.Rows != 12 || !analysis.Invertible {
+		t.Errorf("ikili matris: %s", formatAnalysis(analysis))
+	}
+	if analysis.MDS == nil || *analysis.MDS {
+		t.Errorf("sıfır içeren ikili matris MDS sayıldı: %s", formatAnalysis(analysis))
+	}
+}
+
+func TestAnalysisBudget(t *testing.T) {
+	field, err := NewGF2m("x^4+x+1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	elements, err := field.ParseMatrix(mdsMatrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	budget := 5
+	if _, ok := field.branchNumber(elements, &budget); ok {
+		t.Error("bütçeyi aşan dal sayısı hesabı tamamlandı")
+	}
+	budget = 5
+	if _, ok := field.allMinorsNonSingular(elements, &budget); ok {
+		t.Error("bütçeyi aşan MDS kontrolü tamamlandı")
+	}
+
+	for a := uint32(1); a < 16; a++ {
+		if field.Mul(a, field.Inv(a)) != 1 {
+			t.Fatalf("%x tersi hatalı: %x", a, field.Inv(a))
+		}
+	}
+}
+
+// equalAnalysis compares analyses including the values of optional properties
+func equalAnalysis(a, b *MatrixAnalysis) bool {
+	equalInt := func(x, y *int) bool { return (x == nil) == (y == nil) && (x == nil || *x == *y) }
+	equalBool := func(x, y *bool) bool { return (x == nil) == (y == nil) && (x == nil || *x == *y) }
+	return a.FieldDegree == b.FieldDegree && a.FieldPolynomial == b.FieldPolynomial &&
+		a.Rows == b.Rows && a.Columns == b.Columns && a.Invertible == b.Invertible &&
+		equalInt(a.DifferentialBranch, b.DifferentialBranch) && equalInt(a.LinearBranch, b.LinearBranch) &&
+		equalBool(a.MDS, b.MDS) && equalBool(a.NearMDS, b.NearMDS) &&
+		a.Involutory == b.Involutory && a.SemiInvolutory == b.SemiInvolutory &&
+		a.Orthogonal == b.Orthogonal && a.Circulant == b.Circulant
+}
+
+// formatAnalysis renders an analysis as its JSON form
+func formatAnalysis(a *MatrixAnalysis) string {
+	data, _ := json.Marshal(a)
+	return string(data)
+}
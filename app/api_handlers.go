@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -14,9 +17,44 @@ import (
 
 // SaveMatrixRequest represents the request to save a matrix
 type SaveMatrixRequest struct {
-	Title  string `json:"title"`
-	Group  string `json:"group,omitempty"`
-	Matrix Matrix `json:"matrix"`
+	Title       string     `json:"title"`
+	Group       string     `json:"group,omitempty"`
+	Matrix      Matrix     `json:"matrix"`
+	FieldMatrix [][]string `json:"field_matrix,omitempty"` // Elements over GF(2^m) as hex or polynomials; expanded into matrix
+	Polynomial  string     `json:"polynomial,omitempty"`   // Irreducible polynomial of the field, required with field_matrix
+
+	field    *GF2m
+	elements FieldMatrix
+}
+
+// resolveField parses the field matrix of the request, if any, and replaces
+// Matrix with its binary expansion
+func (req *SaveMatrixRequest) resolveField() error {
+	if len(req.FieldMatrix) == 0 {
+		return nil
+	}
+	if req.Polynomial == "" {
+		return fmt.Errorf("field_matrix için polynomial gerekli")
+	}
+	field, err := NewGF2m(req.Polynomial)
+	if err != nil {
+		return err
+	}
+	elements, err := field.ParseMatrix(req.FieldMatrix)
+	if err != nil {
+		return err
+	}
+	req.field, req.elements = field, elements
+	req.Matrix = field.Expand(elements)
+	return nil
+}
+
+// save stores the matrix of the request, with its field representation if it has one
+func (req *SaveMatrixRequest) save(store MatrixStore) (*MatrixRecord, error) {
+	if req.field != nil {
+		return SaveFieldMatrix(store, req.Title, req.field, req.elements, req.Group)
+	}
+	return SaveMatrix(store, req.Title, req.Matrix, req.Group)
 }
 
 // GetMatricesResponse represents the response for getting matrices
@@ -30,14 +68,18 @@ type GetMatricesResponse struct {
 
 // RecalculateRequest represents the request to recalculate algorithms
 type RecalculateRequest struct {
-	MatrixID   int      `json:"matrix_id"`
-	Algorithms []string `json:"algorithms"` // ["boyar", "paar", "slp"]
+	MatrixID   int                     `json:"matrix_id"`
+	Algorithms []string                `json:"algorithms"`          // Registered solver names, e.g. ["boyar", "paar", "slp"]
+	Params     map[string]SolverParams `json:"params,omitempty"`    // Solver parameters keyed by name, e.g. {"boyar": {"seed": 42}}
+	Transpose  bool                    `json:"transpose,omitempty"` // Also solve M^T with every algorithm and keep the better program
 }
 
 // BulkRecalculateRequest represents the request to recalculate algorithms for multiple matrices
 type BulkRecalculateRequest struct {
-	Algorithms []string `json:"algorithms"` // ["boyar", "paar", "slp"]
-	Limit      int      `json:"limit"`      // Maximum number of matrices to process
+	Algorithms []string                `json:"algorithms"`          // Registered solver names, e.g. ["boyar", "paar", "slp"]
+	Params     map[string]SolverParams `json:"params,omitempty"`    // Solver parameters keyed by name
+	Limit      int                     `json:"limit"`               // Maximum number of matrices to process
+	Transpose  bool                    `json:"transpose,omitempty"` // Also solve M^T with every algorithm and keep the better program
 }
 
 // BulkRecalculateResponse represents the response for bulk recalculation
@@ -45,10 +87,50 @@ type BulkRecalculateResponse struct {
 	ProcessedCount int `json:"processed_count"`
 	TotalCount     int `json:"total_count"`
 	Message        string `json:"message"`
+	JobIDs         []int  `json:"job_ids,omitempty"` // Queued algorithm jobs, see GET /api/jobs/{id}
+}
+
+// VerifyRequest represents the request to verify stored programs
+type VerifyRequest struct {
+	Algorithms []string `json:"algorithms"` // Solvers to verify, empty for every stored program
+}
+
+// VerifyResponse represents the verifier verdicts of a matrix
+type VerifyResponse struct {
+	MatrixID int             `json:"matrix_id"`
+	Valid    bool            `json:"valid"` // Whether every verified program is valid
+	Results  []*Verification `json:"results"`
+}
+
+// DepthSweepRequest represents the request to start a depth sweep
+type DepthSweepRequest struct {
+	Params SolverParams `json:"params,omitempty"` // BoyarSLP parameters of every run, depth_limit is set by the sweep
+}
+
+// DepthSweepResponse represents the response of a started depth sweep
+type DepthSweepResponse struct {
+	MatrixID int    `json:"matrix_id"`
+	MinDepth int    `json:"min_depth"`
+	JobID    int    `json:"job_id"` // Algorithm job running the sweep, see GET /api/jobs/{id}
+	Message  string `json:"message"`
+}
+
+// GeneratorJobRequest represents the request to start a candidate MDS generator job
+type GeneratorJobRequest struct {
+	Family     string   `json:"family"`               // circulant, hadamard, cauchy or toeplitz
+	Polynomial string   `json:"polynomial"`           // Irreducible polynomial of the field, e.g. "x^4+x+1"
+	Dimension  int      `json:"dimension"`            // n of the n×n field matrices
+	MaxResults int      `json:"max_results"`          // New matrices after which the job completes, default 100
+	Algorithms []string `json:"algorithms,omitempty"` // Solvers queued for new matrices, default solvers if empty
+}
+
+// GeneratorResumeRequest represents the optional body of a resume request
+type GeneratorResumeRequest struct {
+	MaxResults int `json:"max_results"` // New limit on the job's new matrices, 0 keeps the current one
 }
 
 // saveMatrixHandler saves a matrix to the database
-func saveMatrixHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) saveMatrixHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var req SaveMatrixRequest
@@ -62,12 +144,17 @@ func saveMatrixHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := req.resolveField(); err != nil {
+		http.Error(w, "Geçersiz alan matrisi: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	if len(req.Matrix) == 0 {
 		http.Error(w, "Matris gerekli", http.StatusBadRequest)
 		return
 	}
 
-	record, err := db.SaveMatrix(req.Title, req.Matrix, req.Group)
+	record, err := req.save(s.store)
 	if err != nil {
 		http.Error(w, "Matris kaydedilemedi: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -77,7 +164,7 @@ func saveMatrixHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // getMatricesHandler retrieves matrices with pagination and filtering
-func getMatricesHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) getMatricesHandler(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
 	w.Header().Set("Content-Type", "application/json")
 	
@@ -96,62 +183,112 @@ func getMatricesHandler(w http.ResponseWriter, r *http.Request) {
 		limit = 10
 	}
 
-	titleFilter := r.URL.Query().Get("title")
+	filter := MatrixFilter{Title: r.URL.Query().Get("title")}
 
 	// Parse range filters
-	var hamXorMin, hamXorMax, boyarXorMin, boyarXorMax, paarXorMin, paarXorMax, slpXorMin, slpXorMax *int
-
 	if val := r.URL.Query().Get("ham_xor_min"); val != "" {
 		if parsed, err := strconv.Atoi(val); err == nil {
-			hamXorMin = &parsed
+			filter.HamXorMin = &parsed
 		}
 	}
 
 	if val := r.URL.Query().Get("ham_xor_max"); val != "" {
 		if parsed, err := strconv.Atoi(val); err == nil {
-			hamXorMax = &parsed
+			filter.HamXorMax = &parsed
 		}
 	}
 
-	if val := r.URL.Query().Get("boyar_xor_min"); val != "" {
+	// <solver>_xor_min and <solver>_xor_max filter every solver with columns
+	filter.SolverXor = make(map[string]XorRange)
+	for _, info := range persistedSolvers() {
+		var bounds XorRange
+		if val := r.URL.Query().Get(info.Name + "_xor_min"); val != "" {
+			if parsed, err := strconv.Atoi(val); err == nil {
+				bounds.Min = &parsed
+			}
+		}
+		if val := r.URL.Query().Get(info.Name + "_xor_max"); val != "" {
+			if parsed, err := strconv.Atoi(val); err == nil {
+				bounds.Max = &parsed
+			}
+		}
+		if bounds.Min != nil || bounds.Max != nil {
+			filter.SolverXor[info.Name] = bounds
+		}
+	}
+
+	if val := r.URL.Query().Get("field_degree"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			filter.FieldDegree = &parsed
+		}
+	}
+
+	if val := r.URL.Query().Get("field_polynomial"); val != "" {
+		if parsed, err := ParseGF2Polynomial(val); err == nil {
+			filter.FieldPolynomial = FormatGF2Polynomial(parsed)
+		}
+	}
+
+	if val := r.URL.Query().Get("differential_branch_min"); val != "" {
 		if parsed, err := strconv.Atoi(val); err == nil {
-			boyarXorMin = &parsed
+			filter.DifferentialBranchMin = &parsed
 		}
 	}
 
-	if val := r.URL.Query().Get("boyar_xor_max"); val != "" {
+	if val := r.URL.Query().Get("linear_branch_min"); val != "" {
 		if parsed, err := strconv.Atoi(val); err == nil {
-			boyarXorMax = &parsed
+			filter.LinearBranchMin = &parsed
+		}
+	}
+
+	// Analysis flags, e.g. is_mds=true&is_involutory=false
+	for _, column := range analysisFlagColumns {
+		if val := r.URL.Query().Get(column); val != "" {
+			if parsed, err := strconv.ParseBool(val); err == nil {
+				if filter.Flags == nil {
+					filter.Flags = make(map[string]bool)
+				}
+				filter.Flags[column] = parsed
+			}
 		}
 	}
 
-	if val := r.URL.Query().Get("paar_xor_min"); val != "" {
+	if val := r.URL.Query().Get("gxor_min"); val != "" {
 		if parsed, err := strconv.Atoi(val); err == nil {
-			paarXorMin = &parsed
+			filter.GXorMin = &parsed
 		}
 	}
 
-	if val := r.URL.Query().Get("paar_xor_max"); val != "" {
+	if val := r.URL.Query().Get("gxor_max"); val != "" {
 		if parsed, err := strconv.Atoi(val); err == nil {
-			paarXorMax = &parsed
+			filter.GXorMax = &parsed
 		}
 	}
 
-	if val := r.URL.Query().Get("slp_xor_min"); val != "" {
+	if val := r.URL.Query().Get("sxor_min"); val != "" {
 		if parsed, err := strconv.Atoi(val); err == nil {
-			slpXorMin = &parsed
+			filter.SXorMin = &parsed
 		}
 	}
 
-	if val := r.URL.Query().Get("slp_xor_max"); val != "" {
+	if val := r.URL.Query().Get("sxor_max"); val != "" {
 		if parsed, err := strconv.Atoi(val); err == nil {
-			slpXorMax = &parsed
+			filter.SXorMax = &parsed
 		}
 	}
 
-	log.Printf("📊 [API] GetMatrices request: page=%d, limit=%d, title_filter='%s'", page, limit, titleFilter)
+	if val := r.URL.Query().Get("optimal_proven"); val != "" {
+		if parsed, err := strconv.ParseBool(val); err == nil {
+			filter.OptimalProven = &parsed
+		}
+	}
+
+	// group_by=canonical lists one matrix per row/column permutation class
+	filter.GroupCanonical = r.URL.Query().Get("group_by") == "canonical"
+
+	log.Printf("📊 [API] GetMatrices request: page=%d, limit=%d, title_filter='%s'", page, limit, filter.Title)
 
-	matrices, total, err := db.GetMatrices(page, limit, titleFilter, hamXorMin, hamXorMax, boyarXorMin, boyarXorMax, paarXorMin, paarXorMax, slpXorMin, slpXorMax)
+	matrices, total, err := s.store.GetMatrices(page, limit, filter)
 	if err != nil {
 		log.Printf("❌ [API] GetMatrices error: %v", err)
 		http.Error(w, "Matrisler alınamadı: "+err.Error(), http.StatusInternalServerError)
@@ -175,7 +312,7 @@ func getMatricesHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // getMatrixHandler retrieves a single matrix by ID
-func getMatrixHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) getMatrixHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	vars := mux.Vars(r)
@@ -187,7 +324,7 @@ func getMatrixHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	record, err := db.GetMatrixByID(id)
+	record, err := s.store.GetMatrixByID(id)
 	if err != nil {
 		http.Error(w, "Matris alınamadı: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -202,7 +339,7 @@ func getMatrixHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // recalculateHandler recalculates algorithms for a specific matrix
-func recalculateHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) recalculateHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var req RecalculateRequest
@@ -216,13 +353,15 @@ func recalculateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Default algorithms if not specified
-	if len(req.Algorithms) == 0 {
-		req.Algorithms = []string{"boyar", "paar", "slp"}
+	// Default algorithms if not specified, reject unknown ones
+	algorithms, err := normalizeAlgorithms(req.Algorithms)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// Get matrix from database
-	record, err := db.GetMatrixByID(req.MatrixID)
+	record, err := s.store.GetMatrixByID(req.MatrixID)
 	if err != nil {
 		http.Error(w, "Matris alınamadı: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -233,57 +372,21 @@ func recalculateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse matrix from binary string
-	matrix, err := parseMatrixFromBinary(record.MatrixBinary)
-	if err != nil {
-		http.Error(w, "Matris parse edilemedi: "+err.Error(), http.StatusInternalServerError)
-		return
+	params := req.Params
+	if req.Transpose {
+		params = withTranspose(params, algorithms)
 	}
 
-	// Run algorithms in background
-	go func() {
-		log.Printf("Matris %d için algoritma hesaplama başlatıldı", req.MatrixID)
-
-		var boyarResult, paarResult, slpResult *AlgResult
-
-		// Run requested algorithms
-		for _, algorithm := range req.Algorithms {
-			switch strings.ToLower(algorithm) {
-			case "boyar":
-				boyar := NewBoyarSLP(10)
-				if result, err := boyar.Solve(matrix); err == nil {
-					boyarResult = &result
-				} else {
-					log.Printf("Boyar algoritması hatası (ID %d): %v", req.MatrixID, err)
-				}
-			case "paar":
-				paar := NewPaarAlgorithm()
-				if result, err := paar.Solve(matrix); err == nil {
-					paarResult = &result
-				} else {
-					log.Printf("Paar algoritması hatası (ID %d): %v", req.MatrixID, err)
-				}
-			case "slp":
-				slp := NewSLPHeuristic()
-				if result, err := slp.Solve(matrix); err == nil {
-					slpResult = &result
-				} else {
-					log.Printf("SLP algoritması hatası (ID %d): %v", req.MatrixID, err)
-				}
-			}
-		}
-
-		// Update database with results
-		err = db.UpdateMatrixResults(req.MatrixID, boyarResult, paarResult, slpResult)
-		if err != nil {
-			log.Printf("Algoritma sonuçları güncellenemedi (ID %d): %v", req.MatrixID, err)
-		} else {
-			log.Printf("Matris %d algoritmaları tamamlandı", req.MatrixID)
-		}
-	}()
+	// Queue the algorithms ahead of imports
+	job := &AlgorithmJob{MatrixID: req.MatrixID, Source: JobSourceRecalculate, Algorithms: algorithms, Params: params, Priority: jobPriorityUser}
+	if err := EnqueueAlgorithmJob(s.store, job); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Matris %d için algoritma işi %d kuyruğa eklendi", req.MatrixID, job.ID)
 
 	// Return updated record
-	updatedRecord, err := db.GetMatrixByID(req.MatrixID)
+	updatedRecord, err := s.store.GetMatrixByID(req.MatrixID)
 	if err != nil {
 		http.Error(w, "Güncellenmiş matris alınamadı: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -293,7 +396,7 @@ func recalculateHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // processAndSaveMatrixHandler processes a matrix with all algorithms and saves to database
-func processAndSaveMatrixHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) processAndSaveMatrixHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var req SaveMatrixRequest
@@ -307,48 +410,35 @@ func processAndSaveMatrixHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := req.resolveField(); err != nil {
+		http.Error(w, "Geçersiz alan matrisi: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	if len(req.Matrix) == 0 {
 		http.Error(w, "Matris gerekli", http.StatusBadRequest)
 		return
 	}
 
 	// Save matrix first
-	record, err := db.SaveMatrix(req.Title, req.Matrix, req.Group)
+	record, err := req.save(s.store)
 	if err != nil {
 		http.Error(w, "Matris kaydedilemedi: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Run all algorithms
-	var boyarResult, paarResult, slpResult *AlgResult
-
-	// Boyar algorithm
-	boyar := NewBoyarSLP(10)
-	if result, err := boyar.Solve(req.Matrix); err == nil {
-		boyarResult = &result
-	}
-
-	// Paar algorithm
-	paar := NewPaarAlgorithm()
-	if result, err := paar.Solve(req.Matrix); err == nil {
-		paarResult = &result
-	}
-
-	// SLP algorithm
-	slp := NewSLPHeuristic()
-	if result, err := slp.Solve(req.Matrix); err == nil {
-		slpResult = &result
-	}
+	// Run all default algorithms
+	results, _ := runSolvers(r.Context(), DefaultAlgorithms(), nil, req.Matrix)
 
 	// Update database with results
-	err = db.UpdateMatrixResults(record.ID, boyarResult, paarResult, slpResult)
+	err = UpdateMatrixResults(s.store, record.ID, results)
 	if err != nil {
 		http.Error(w, "Sonuçlar güncellenemedi: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Return updated record
-	updatedRecord, err := db.GetMatrixByID(record.ID)
+	updatedRecord, err := s.store.GetMatrixByID(record.ID)
 	if err != nil {
 		http.Error(w, "Güncellenmiş matris alınamadı: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -358,7 +448,7 @@ func processAndSaveMatrixHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // bulkRecalculateHandler recalculates algorithms for matrices without algorithm results
-func bulkRecalculateHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) bulkRecalculateHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var req BulkRecalculateRequest
@@ -367,9 +457,11 @@ func bulkRecalculateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Default algorithms if not specified
-	if len(req.Algorithms) == 0 {
-		req.Algorithms = []string{"boyar", "paar", "slp"}
+	// Default algorithms if not specified, reject unknown ones
+	algorithms, err := normalizeAlgorithms(req.Algorithms)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// Default limit if not specified
@@ -378,7 +470,7 @@ func bulkRecalculateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get matrices without algorithm results
-	matrices, err := db.GetMatricesWithoutAlgorithms(req.Limit)
+	matrices, err := s.store.GetMatricesWithoutAlgorithms(req.Limit)
 	if err != nil {
 		http.Error(w, "Matrisler alınamadı: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -394,77 +486,43 @@ func bulkRecalculateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Process matrices in background
-	go func() {
-		for i, matrix := range matrices {
-			log.Printf("Toplu hesaplama: Matris %d/%d (ID: %d) işleniyor...", i+1, len(matrices), matrix.ID)
-			
-			// Parse matrix from binary string
-			matrixData, err := parseMatrixFromBinary(matrix.MatrixBinary)
-			if err != nil {
-				log.Printf("Matris parse hatası (ID %d): %v", matrix.ID, err)
-				continue
-			}
-
-			// Recalculate Ham XOR
-			newHamXor := calculateHammingXOR(matrixData)
-			
-			// Update Ham XOR in database
-			_, err = db.db.Exec("UPDATE matrix_records SET ham_xor_count = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2", newHamXor, matrix.ID)
-			if err != nil {
-				log.Printf("Ham XOR güncellenemedi (ID %d): %v", matrix.ID, err)
-			}
+	params := req.Params
+	if req.Transpose {
+		params = withTranspose(params, algorithms)
+	}
 
-			var boyarResult, paarResult, slpResult *AlgResult
-
-			// Run requested algorithms
-			for _, algorithm := range req.Algorithms {
-				switch strings.ToLower(algorithm) {
-				case "boyar":
-					boyar := NewBoyarSLP(10)
-					if result, err := boyar.Solve(matrixData); err == nil {
-						boyarResult = &result
-					} else {
-						log.Printf("Boyar algoritması hatası (ID %d): %v", matrix.ID, err)
-					}
-				case "paar":
-					paar := NewPaarAlgorithm()
-					if result, err := paar.Solve(matrixData); err == nil {
-						paarResult = &result
-					} else {
-						log.Printf("Paar algoritması hatası (ID %d): %v", matrix.ID, err)
-					}
-				case "slp":
-					slp := NewSLPHeuristic()
-					if result, err := slp.Solve(matrixData); err == nil {
-						slpResult = &result
-					} else {
-						log.Printf("SLP algoritması hatası (ID %d): %v", matrix.ID, err)
-					}
-				}
-			}
+	// Queue one job per matrix; Ham XOR is cheap and recalculated right away
+	var jobIDs []int
+	for _, matrix := range matrices {
+		matrixData, err := parseMatrixFromBinary(matrix.MatrixBinary)
+		if err != nil {
+			log.Printf("Matris parse hatası (ID %d): %v", matrix.ID, err)
+			continue
+		}
+		if err := s.store.UpdateHamXorCount(matrix.ID, calculateHammingXOR(matrixData)); err != nil {
+			log.Printf("Ham XOR güncellenemedi (ID %d): %v", matrix.ID, err)
+		}
 
-			// Update database with results
-			err = db.UpdateMatrixResults(matrix.ID, boyarResult, paarResult, slpResult)
-			if err != nil {
-				log.Printf("Algoritma sonuçları güncellenemedi (ID %d): %v", matrix.ID, err)
-			} else {
-				log.Printf("Matris %d algoritmaları tamamlandı", matrix.ID)
-			}
+		job := &AlgorithmJob{MatrixID: matrix.ID, Source: JobSourceBulk, Algorithms: algorithms, Params: params}
+		if err := EnqueueAlgorithmJob(s.store, job); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
-		log.Printf("Toplu algoritma hesaplama tamamlandı: %d matris işlendi", len(matrices))
-	}()
+		jobIDs = append(jobIDs, job.ID)
+	}
+	log.Printf("Toplu algoritma hesaplama: %d matris için iş kuyruğa eklendi", len(jobIDs))
 
 	response := BulkRecalculateResponse{
 		ProcessedCount: 0,
 		TotalCount:     len(matrices),
-		Message:        fmt.Sprintf("%d matris için algoritma hesaplama başlatıldı", len(matrices)),
+		Message:        fmt.Sprintf("%d matris için algoritma hesaplama kuyruğa eklendi", len(jobIDs)),
+		JobIDs:         jobIDs,
 	}
 	json.NewEncoder(w).Encode(response)
 }
 
 // calculateInverseHandler calculates and saves the inverse of a matrix
-func calculateInverseHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) calculateInverseHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	vars := mux.Vars(r)
@@ -477,11 +535,596 @@ func calculateInverseHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Calculate and save inverse matrix
-	inverseRecord, err := db.SaveMatrixInverse(id)
+	inverseRecord, err := SaveMatrixInverse(s.store, id)
 	if err != nil {
 		http.Error(w, "Ters matris hesaplanamadı: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	json.NewEncoder(w).Encode(inverseRecord)
-} 
\ No newline at end of file
+}
+
+// verifyMatrixHandler re-executes the stored programs of a matrix and stores the verdicts
+func (s *Server) verifyMatrixHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Geçersiz ID formatı", http.StatusBadRequest)
+		return
+	}
+
+	// The body is optional; without it every stored program is verified
+	var req VerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Geçersiz JSON formatı", http.StatusBadRequest)
+		return
+	}
+
+	record, err := s.store.GetMatrixByID(id)
+	if err != nil {
+		http.Error(w, "Matris alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if record == nil {
+		http.Error(w, "Matris bulunamadı", http.StatusNotFound)
+		return
+	}
+
+	verifications, err := VerifyStoredResults(record, req.Algorithms)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := VerifyResponse{MatrixID: id, Valid: true, Results: verifications}
+	verdicts := make(map[int]bool)
+	for _, v := range verifications {
+		if v.RunID != 0 {
+			verdicts[v.RunID] = v.Valid
+		}
+		if !v.Valid {
+			response.Valid = false
+			log.Printf("Matris %d %s programı doğrulanamadı: %v", id, v.Algorithm, v.Errors)
+		}
+	}
+
+	if err := s.store.UpdateVerification(id, verdicts); err != nil {
+		http.Error(w, "Doğrulama sonucu kaydedilemedi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// codegenHandler renders a stored program as source code with a test harness
+func (s *Server) codegenHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Geçersiz ID formatı", http.StatusBadRequest)
+		return
+	}
+
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		http.Error(w, "lang parametresi gerekli ("+strings.Join(CodegenLanguages, ", ")+")", http.StatusBadRequest)
+		return
+	}
+	algorithm := r.URL.Query().Get("algorithm")
+	if algorithm == "" {
+		algorithm = "best"
+	}
+
+	record, err := s.store.GetMatrixByID(id)
+	if err != nil {
+		http.Error(w, "Matris alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if record == nil {
+		http.Error(w, "Matris bulunamadı", http.StatusNotFound)
+		return
+	}
+
+	code, err := GenerateCode(record, lang, algorithm)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(code)
+}
+
+// bitsliceHandler renders a stored program as a register-pressure scheduled bitsliced routine
+func (s *Server) bitsliceHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Geçersiz ID formatı", http.StatusBadRequest)
+		return
+	}
+
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		http.Error(w, "lang parametresi gerekli (c, go)", http.StatusBadRequest)
+		return
+	}
+	lanes := 64
+	if value := r.URL.Query().Get("lanes"); value != "" {
+		lanes, err = strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "Geçersiz lanes değeri", http.StatusBadRequest)
+			return
+		}
+	}
+	algorithm := r.URL.Query().Get("algorithm")
+	if algorithm == "" {
+		algorithm = "best"
+	}
+
+	record, err := s.store.GetMatrixByID(id)
+	if err != nil {
+		http.Error(w, "Matris alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if record == nil {
+		http.Error(w, "Matris bulunamadı", http.StatusNotFound)
+		return
+	}
+
+	code, err := GenerateBitsliced(record, lang, lanes, algorithm)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(code)
+}
+
+// depthSweepHandler starts a background BoyarSLP depth sweep of a matrix
+func (s *Server) depthSweepHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Geçersiz ID formatı", http.StatusBadRequest)
+		return
+	}
+
+	// The body is optional; without it every run uses the default parameters
+	var req DepthSweepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Geçersiz JSON formatı", http.StatusBadRequest)
+		return
+	}
+
+	record, err := s.store.GetMatrixByID(id)
+	if err != nil {
+		http.Error(w, "Matris alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if record == nil {
+		http.Error(w, "Matris bulunamadı", http.StatusNotFound)
+		return
+	}
+
+	matrix, err := parseMatrixFromBinary(record.MatrixBinary)
+	if err != nil {
+		http.Error(w, "Matris parse edilemedi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rows, _, err := parseBinaryRows(matrix)
+	if err != nil {
+		http.Error(w, "Matris parse edilemedi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Reject parameters every run of the sweep would fail on
+	if _, err := NewSolver(paretoAlgorithm, sweepParams(req.Params, maxBoyarDepthLimit)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	job, err := StartDepthSweep(s.store, record, req.Params)
+	if errors.Is(err, errDepthSweepRunning) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(DepthSweepResponse{
+		MatrixID: id,
+		MinDepth: minimumDepth(rows),
+		JobID:    job.ID,
+		Message:  "Derinlik taraması kuyruğa eklendi",
+	})
+}
+
+// paretoHandler returns the stored (depth, XOR) Pareto front of a matrix
+func (s *Server) paretoHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Geçersiz ID formatı", http.StatusBadRequest)
+		return
+	}
+
+	record, err := s.store.GetMatrixByID(id)
+	if err != nil {
+		http.Error(w, "Matris alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if record == nil {
+		http.Error(w, "Matris bulunamadı", http.StatusNotFound)
+		return
+	}
+
+	matrix, err := parseMatrixFromBinary(record.MatrixBinary)
+	if err != nil {
+		http.Error(w, "Matris parse edilemedi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rows, _, err := parseBinaryRows(matrix)
+	if err != nil {
+		http.Error(w, "Matris parse edilemedi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	points, err := s.store.GetParetoFront(record)
+	if err != nil {
+		http.Error(w, "Pareto noktaları alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	running, err := depthSweepRunning(s.store, id)
+	if err != nil {
+		http.Error(w, "Algoritma işleri alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(ParetoFront{
+		MatrixID: id,
+		MinDepth: minimumDepth(rows),
+		Running:  running,
+		Points:   points,
+	})
+}
+
+// analyzeMatrixHandler recomputes the analysis flags of a matrix, stores them
+// and returns the full analysis
+func (s *Server) analyzeMatrixHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Geçersiz ID formatı", http.StatusBadRequest)
+		return
+	}
+
+	record, err := s.store.GetMatrixByID(id)
+	if err != nil {
+		http.Error(w, "Matris alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if record == nil {
+		http.Error(w, "Matris bulunamadı", http.StatusNotFound)
+		return
+	}
+
+	analysis, err := AnalyzeStoredMatrix(s.store, record)
+	if err != nil {
+		http.Error(w, "Matris analiz edilemedi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(analysis)
+}
+
+
+// algorithmRunsHandler lists the stored runs of a matrix, newest first;
+// ?algorithm=paar keeps only the runs of one algorithm
+func (s *Server) algorithmRunsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Geçersiz ID formatı", http.StatusBadRequest)
+		return
+	}
+
+	algorithm := r.URL.Query().Get("algorithm")
+	if algorithm != "" {
+		info, ok := GetSolverInfo(algorithm)
+		if !ok {
+			http.Error(w, "Desteklenmeyen algoritma: "+algorithm, http.StatusBadRequest)
+			return
+		}
+		algorithm = info.Name
+	}
+
+	record, err := s.store.GetMatrixByID(id)
+	if err != nil {
+		http.Error(w, "Matris alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if record == nil {
+		http.Error(w, "Matris bulunamadı", http.StatusNotFound)
+		return
+	}
+
+	runs, err := s.store.GetAlgorithmRuns(record, algorithm)
+	if err != nil {
+		http.Error(w, "Çalıştırmalar alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(runs)
+}
+
+// sxorHandler recomputes the s-XOR program of a stored matrix from its
+// stored programs and returns its d-XOR, g-XOR and s-XOR counts
+func (s *Server) sxorHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Geçersiz ID formatı", http.StatusBadRequest)
+		return
+	}
+
+	record, err := s.store.GetMatrixByID(id)
+	if err != nil {
+		http.Error(w, "Matris alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if record == nil {
+		http.Error(w, "Matris bulunamadı", http.StatusNotFound)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), inPlaceConvertBudget)
+	defer cancel()
+	record, err = UpdateInPlaceResult(ctx, s.store, id)
+	if err != nil {
+		http.Error(w, "s-XOR hesaplanamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(newXorMetrics(record))
+}
+
+// generatorFamiliesHandler lists the construction families of the generator
+func generatorFamiliesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(constructionFamilies)
+}
+
+// createGeneratorJobHandler stores a generator job and starts it in the background
+func (s *Server) createGeneratorJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req GeneratorJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Geçersiz JSON formatı", http.StatusBadRequest)
+		return
+	}
+
+	job, err := newGeneratorJob(req.Family, req.Polynomial, req.Dimension, req.MaxResults, req.Algorithms)
+	if err != nil {
+		http.Error(w, "Geçersiz üretici işi: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.store.CreateGeneratorJob(job); err != nil {
+		http.Error(w, "Üretici işi kaydedilemedi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := job.snapshot()
+	if err := StartGeneratorJob(s.store, job); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// getGeneratorJobsHandler lists generator jobs, optionally filtered by status
+func (s *Server) getGeneratorJobsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	jobs, err := s.store.GetGeneratorJobs(r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, "Üretici işleri alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(jobs)
+}
+
+// generatorJobFromRequest loads the job named by the route, writing the error response if it fails
+func (s *Server) generatorJobFromRequest(w http.ResponseWriter, r *http.Request) *GeneratorJob {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Geçersiz ID formatı", http.StatusBadRequest)
+		return nil
+	}
+
+	job, err := s.store.GetGeneratorJob(id)
+	if err != nil {
+		http.Error(w, "Üretici işi alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return nil
+	}
+	if job == nil {
+		http.Error(w, "Üretici işi bulunamadı", http.StatusNotFound)
+		return nil
+	}
+	return job
+}
+
+// getGeneratorJobHandler returns the stored progress of a generator job
+func (s *Server) getGeneratorJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	job := s.generatorJobFromRequest(w, r)
+	if job == nil {
+		return
+	}
+
+	json.NewEncoder(w).Encode(job)
+}
+
+// pauseGeneratorJobHandler stops a running generator job; its cursor is kept for resuming
+func (s *Server) pauseGeneratorJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	job := s.generatorJobFromRequest(w, r)
+	if job == nil {
+		return
+	}
+	if !PauseGeneratorJob(job.ID) {
+		http.Error(w, "Üretici işi çalışmıyor", http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// resumeGeneratorJobHandler continues a paused, failed or completed generator
+// job from its cursor, optionally with a new max_results
+func (s *Server) resumeGeneratorJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// The body is optional; without it the job keeps its limit
+	var req GeneratorResumeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Geçersiz JSON formatı", http.StatusBadRequest)
+		return
+	}
+
+	job := s.generatorJobFromRequest(w, r)
+	if job == nil {
+		return
+	}
+	if generatorJobRunning(job.ID) {
+		http.Error(w, "Üretici işi zaten çalışıyor", http.StatusConflict)
+		return
+	}
+	if job.Cursor == nil {
+		http.Error(w, "Üretici işinin incelenecek adayı kalmadı", http.StatusConflict)
+		return
+	}
+	if req.MaxResults > 0 {
+		job.MaxResults = req.MaxResults
+	}
+
+	job.Status, job.Error = GeneratorRunning, nil
+	if err := s.store.SaveGeneratorProgress(job); err != nil {
+		http.Error(w, "Üretici işi kaydedilemedi: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := job.snapshot()
+	if err := StartGeneratorJob(s.store, job); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// getAlgorithmJobsHandler lists the algorithm jobs newest first, filtered by
+// the status and matrix_id query parameters
+func (s *Server) getAlgorithmJobsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	matrixID, _ := strconv.Atoi(r.URL.Query().Get("matrix_id"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 || limit > 1000 {
+		limit = 100
+	}
+
+	jobs, err := s.store.GetAlgorithmJobs(r.URL.Query().Get("status"), matrixID, limit)
+	if err != nil {
+		http.Error(w, "Algoritma işleri alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(jobs)
+}
+
+// algorithmJobFromRequest loads the job named by the route, writing the error response if it fails
+func (s *Server) algorithmJobFromRequest(w http.ResponseWriter, r *http.Request) *AlgorithmJob {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Geçersiz ID formatı", http.StatusBadRequest)
+		return nil
+	}
+
+	job, err := s.store.GetAlgorithmJob(id)
+	if err != nil {
+		http.Error(w, "Algoritma işi alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return nil
+	}
+	if job == nil {
+		http.Error(w, "Algoritma işi bulunamadı", http.StatusNotFound)
+		return nil
+	}
+	return job
+}
+
+// getAlgorithmJobHandler returns the state of an algorithm job
+func (s *Server) getAlgorithmJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	job := s.algorithmJobFromRequest(w, r)
+	if job == nil {
+		return
+	}
+
+	json.NewEncoder(w).Encode(job)
+}
+
+// retryAlgorithmJobHandler queues a dead-lettered algorithm job again with fresh attempts
+func (s *Server) retryAlgorithmJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	job := s.algorithmJobFromRequest(w, r)
+	if job == nil {
+		return
+	}
+	retried, err := RetryAlgorithmJob(s.store, job.ID)
+	if err != nil {
+		http.Error(w, "Algoritma işi kuyruğa alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !retried {
+		http.Error(w, "Yalnızca dead durumundaki işler yeniden denenebilir", http.StatusConflict)
+		return
+	}
+
+	job, err = s.store.GetAlgorithmJob(job.ID)
+	if err != nil {
+		http.Error(w, "Algoritma işi alınamadı: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BitsliceLanes lists the supported word sizes of bitsliced routines
+var BitsliceLanes = []int{32, 64}
+
+// bitsliceTestRounds is the number of random words per input the generated
+// harnesses feed through the routine; every round checks all lanes
+const bitsliceTestRounds = 64
+
+// GenerateBitsliced renders the chosen stored program of record as a
+// bitsliced routine in lang ("c" or "go") over lanes-bit words: bit b of every
+// input and output word belongs to the b-th independent vector. Gates are
+// scheduled to keep few temporaries live and temporaries are reused once
+// their value is dead, so the routine declares exactly PeakLive of them.
+func GenerateBitsliced(record *MatrixRecord, lang string, lanes int, algorithm string) (*GeneratedCode, error) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lanes != 32 && lanes != 64 {
+		return nil, fmt.Errorf("desteklenmeyen lane genişliği: %d (32 veya 64 olmalı)", lanes)
+	}
+
+	unit, err := newCodegenUnit(record, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	unit.Program = unit.Program.Schedule()
+
+	var files []GeneratedFile
+	switch lang {
+	case "c":
+		files = generateBitslicedC(unit, lanes)
+	case "go":
+		files = generateBitslicedGo(unit, lanes)
+	default:
+		return nil, fmt.Errorf("bitsliced kod için desteklenmeyen dil: %s (desteklenenler: c, go)", lang)
+	}
+
+	return &GeneratedCode{
+		MatrixID:  record.ID,
+		Algorithm: unit.Algorithm,
+		Language:  lang,
+		XorCount:  unit.Program.XorCount(),
+		Depth:     unit.Program.Depth(),
+		Lanes:     lanes,
+		PeakLive:  unit.Program.PeakLive(),
+		Files:     files,
+	}, nil
+}
+
+// bitslicedStatement is one line of a bitsliced routine body
+type bitslicedStatement struct {
+	Dest    string // "r3" or "y[5]"
+	A, B    string // Operands; B is empty for a plain store
+	Comment string
+}
+
+// bitslicedBody returns the routine body: outputs that are inputs or zero
+// first, then every gate into its temporary followed by the outputs it
+// computes. dead lists the temporaries written by gates nothing reads.
+func (u *codegenUnit) bitslicedBody() (body []bitslicedStatement, temporaries int, dead []string) {
+	p := u.Program
+	registers, temporaries := p.AllocateRegisters()
+	last := p.lastUses()
+
+	operand := func(s int) string {
+		if s < p.NumInputs {
+			return fmt.Sprintf("x[%d]", s)
+		}
+		return fmt.Sprintf("r%d", registers[s-p.NumInputs])
+	}
+
+	stores := make(map[int][]int) // Outputs of each gate signal
+	deadSeen := make(map[string]bool)
+	for i, s := range p.Outputs {
+		switch {
+		case s < 0:
+			body = append(body, bitslicedStatement{Dest: fmt.Sprintf("y[%d]", i), A: "0"})
+		case s < p.NumInputs:
+			body = append(body, bitslicedStatement{Dest: fmt.Sprintf("y[%d]", i), A: operand(s)})
+		default:
+			stores[s] = append(stores[s], i)
+		}
+	}
+
+	for k, gate := range p.Gates {
+		signal := p.NumInputs + k
+		dest := fmt.Sprintf("r%d", registers[k])
+		body = append(body, bitslicedStatement{
+			Dest:    dest,
+			A:       operand(gate.A),
+			B:       operand(gate.B),
+			Comment: fmt.Sprintf("%s, depth %d", p.SignalName(signal), gate.Depth),
+		})
+		for _, i := range stores[signal] {
+			body = append(body, bitslicedStatement{Dest: fmt.Sprintf("y[%d]", i), A: dest})
+		}
+		if last[k] < 0 && len(stores[signal]) == 0 && !deadSeen[dest] {
+			deadSeen[dest] = true
+			dead = append(dead, dest)
+		}
+	}
+	return body, temporaries, dead
+}
+
+// bitslicedSummary extends the summary with the register pressure
+func (u *codegenUnit) bitslicedSummary(lanes, temporaries int) []string {
+	return append(u.summary(),
+		fmt.Sprintf("Bitsliced over %d-bit lanes: bit b of every word is an independent vector.", lanes),
+		fmt.Sprintf("Peak live temporaries: %d (gates scheduled for register pressure).", temporaries))
+}
+
+// registerList returns "r0, r1, ..." for n temporaries
+func registerList(n int) string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("r%d", i)
+	}
+	return strings.Join(names, ", ")
+}
+
+func generateBitslicedC(u *codegenUnit, lanes int) []GeneratedFile {
+	base := fmt.Sprintf("xor_opt_%d_bs%d", u.ID, lanes)
+	word := fmt.Sprintf("uint%d_t", lanes)
+	m, n := len(u.Rows), u.NumInputs
+	body, temporaries, _ := u.bitslicedBody()
+
+	var code strings.Builder
+	code.WriteString("/*\n")
+	for _, line := range u.bitslicedSummary(lanes, temporaries) {
+		fmt.Fprintf(&code, " * %s\n", line)
+	}
+	code.WriteString(" */\n#include <stdint.h>\n\n")
+	fmt.Fprintf(&code, "void %s(const %s x[%d], %s y[%d])\n{\n", base, word, n, word, m)
+	if temporaries > 0 {
+		fmt.Fprintf(&code, "    %s %s;\n\n", word, registerList(temporaries))
+	}
+	for _, st := range body {
+		switch {
+		case st.B != "":
+			fmt.Fprintf(&code, "    %s = %s ^ %s; /* %s */\n", st.Dest, st.A, st.B, st.Comment)
+		default:
+			fmt.Fprintf(&code, "    %s = %s;\n", st.Dest, st.A)
+		}
+	}
+	code.WriteString("}\n")
+
+	var harness strings.Builder
+	fmt.Fprintf(&harness, "/* Test harness for %s.c: compares every lane with naive matrix-vector multiplication */\n", base)
+	harness.WriteString("#include <stdio.h>\n\n")
+	fmt.Fprintf(&harness, "#include \"%s.c\"\n\n", base)
+	fmt.Fprintf(&harness, "static const uint8_t matrix[%d][%d] = {\n%s};\n\n", m, n, u.matrixLiteral("{", "}", "    "))
+	harness.WriteString("static uint64_t next_random(uint64_t *state)\n{\n")
+	harness.WriteString("    *state ^= *state << 13;\n    *state ^= *state >> 7;\n    *state ^= *state << 17;\n    return *state;\n}\n\n")
+	harness.WriteString("int main(void)\n{\n    uint64_t state = 1;\n")
+	fmt.Fprintf(&harness, "    %s x[%d], y[%d];\n", word, n, m)
+	fmt.Fprintf(&harness, "    for (int v = 0; v < %d; v++) {\n", bitsliceTestRounds)
+	fmt.Fprintf(&harness, "        for (int j = 0; j < %d; j++)\n            x[j] = (%s)next_random(&state);\n", n, word)
+	fmt.Fprintf(&harness, "        %s(x, y);\n", base)
+	fmt.Fprintf(&harness, "        for (int lane = 0; lane < %d; lane++) {\n", lanes)
+	fmt.Fprintf(&harness, "            for (int i = 0; i < %d; i++) {\n", m)
+	harness.WriteString("                unsigned want = 0;\n")
+	fmt.Fprintf(&harness, "                for (int j = 0; j < %d; j++)\n", n)
+	harness.WriteString("                    want ^= matrix[i][j] & (unsigned)(x[j] >> lane) & 1u;\n")
+	harness.WriteString("                if (((unsigned)(y[i] >> lane) & 1u) != want) {\n")
+	harness.WriteString("                    printf(\"FAIL: round %d, lane %d, output %d\\n\", v, lane, i);\n")
+	harness.WriteString("                    return 1;\n                }\n            }\n        }\n    }\n")
+	fmt.Fprintf(&harness, "    printf(\"OK: %d vectors\\n\");\n    return 0;\n}\n", bitsliceTestRounds*lanes)
+
+	return []GeneratedFile{
+		{Name: base + ".c", Content: code.String()},
+		{Name: base + "_test.c", Content: harness.String()},
+	}
+}
+
+func generateBitslicedGo(u *codegenUnit, lanes int) []GeneratedFile {
+	base := fmt.Sprintf("xor_opt_%d_bs%d", u.ID, lanes)
+	function := fmt.Sprintf("XorOpt%dBs%d", u.ID, lanes)
+	word := fmt.Sprintf("uint%d", lanes)
+	m, n := len(u.Rows), u.NumInputs
+	body, temporaries, dead := u.bitslicedBody()
+
+	var code strings.Builder
+	for _, line := range u.bitslicedSummary(lanes, temporaries) {
+		fmt.Fprintf(&code, "// %s\n", line)
+	}
+	code.WriteString("package xoropt\n\n")
+	fmt.Fprintf(&code, "// %s multiplies %d input vectors, one per lane, by the matrix\n", function, lanes)
+	fmt.Fprintf(&code, "func %s(x *[%d]%s, y *[%d]%s) {\n", function, n, word, m, word)
+	if temporaries > 0 {
+		fmt.Fprintf(&code, "\tvar %s %s\n\n", registerList(temporaries), word)
+	}
+	for _, st := range body {
+		switch {
+		case st.B != "":
+			fmt.Fprintf(&code, "\t%s = %s ^ %s // %s\n", st.Dest, st.A, st.B, st.Comment)
+		default:
+			fmt.Fprintf(&code, "\t%s = %s\n", st.Dest, st.A)
+		}
+	}
+	for _, name := range dead {
+		fmt.Fprintf(&code, "\t_ = %s\n", name)
+	}
+	code.WriteString("}\n")
+
+	var harness strings.Builder
+	harness.WriteString("package xoropt\n\nimport (\n\t\"math/rand\"\n\t\"testing\"\n)\n\n")
+	fmt.Fprintf(&harness, "var matrix%dBs%d = [%d][%d]%s{\n%s}\n\n", u.ID, lanes, m, n, word, u.matrixLiteral("{", "}", "\t"))
+	fmt.Fprintf(&harness, "// Test%s compares every lane of %s with naive matrix-vector multiplication\n", function, function)
+	fmt.Fprintf(&harness, "func Test%s(t *testing.T) {\n", function)
+	harness.WriteString("\trng := rand.New(rand.NewSource(1))\n")
+	fmt.Fprintf(&harness, "\tfor v := 0; v < %d; v++ {\n", bitsliceTestRounds)
+	fmt.Fprintf(&harness, "\t\tvar x [%d]%s\n\t\tvar y [%d]%s\n", n, word, m, word)
+	fmt.Fprintf(&harness, "\t\tfor j := range x {\n\t\t\tx[j] = %s(rng.Uint64())\n\t\t}\n", word)
+	fmt.Fprintf(&harness, "\t\t%s(&x, &y)\n", function)
+	fmt.Fprintf(&harness, "\t\tfor lane := 0; lane < %d; lane++ {\n", lanes)
+	fmt.Fprintf(&harness, "\t\t\tfor i, row := range matrix%dBs%d {\n", u.ID, lanes)
+	fmt.Fprintf(&harness, "\t\t\t\tvar want %s\n", word)
+	harness.WriteString("\t\t\t\tfor j, bit := range row {\n\t\t\t\t\twant ^= bit & (x[j] >> lane)\n\t\t\t\t}\n")
+	harness.WriteString("\t\t\t\tif got := (y[i] >> lane) & 1; got != want&1 {\n")
+	harness.WriteString("\t\t\t\t\tt.Fatalf(\"round %d lane %d: y[%d] = %d, want %d\", v, lane, i, got, want&1)\n")
+	harness.WriteString("\t\t\t\t}\n\t\t\t}\n\t\t}\n\t}\n}\n")
+
+	return []GeneratedFile{
+		{Name: base + ".go", Content: gofmtSource(code.String())},
+		{Name: base + "_test.go", Content: gofmtSource(harness.String())},
+	}
+}
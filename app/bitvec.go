@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// BitVector is a fixed-width bit set stored in 64-bit words.
+// Bit i lives in word i/64 at position i%64. All vectors that are combined
+// with each other must have the same number of words.
+type BitVector []uint64
+
+// bitVectorWords returns the number of words needed for n bits
+func bitVectorWords(n int) int {
+	return (n + 63) / 64
+}
+
+// NewBitVector returns an all-zero vector able to hold n bits
+func NewBitVector(n int) BitVector {
+	return make(BitVector, bitVectorWords(n))
+}
+
+// UnitBitVector returns a vector of width n with only bit i set
+func UnitBitVector(n, i int) BitVector {
+	v := NewBitVector(n)
+	v.Set(i)
+	return v
+}
+
+// Set sets bit i
+func (v BitVector) Set(i int) {
+	v[i/64] |= 1 << uint(i%64)
+}
+
+// Clear clears bit i
+func (v BitVector) Clear(i int) {
+	v[i/64] &^= 1 << uint(i%64)
+}
+
+// Test reports whether bit i is set
+func (v BitVector) Test(i int) bool {
+	return v[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Clone returns a copy of the vector
+func (v BitVector) Clone() BitVector {
+	c := make(BitVector, len(v))
+	copy(c, v)
+	return c
+}
+
+// IsZero reports whether no bit is set
+func (v BitVector) IsZero() bool {
+	for _, w := range v {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal reports whether both vectors hold the same bits
+func (v BitVector) Equal(o BitVector) bool {
+	if len(v) != len(o) {
+		return false
+	}
+	for i := range v {
+		if v[i] != o[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// PopCount returns the number of set bits
+func (v BitVector) PopCount() int {
+	count := 0
+	for _, w := range v {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Xor returns v ^ o as a new vector
+func (v BitVector) Xor(o BitVector) BitVector {
+	r := make(BitVector, len(v))
+	for i := range v {
+		r[i] = v[i] ^ o[i]
+	}
+	return r
+}
+
+// And returns v & o as a new vector
+func (v BitVector) And(o BitVector) BitVector {
+	r := make(BitVector, len(v))
+	for i := range v {
+		r[i] = v[i] & o[i]
+	}
+	return r
+}
+
+// AndNot returns v &^ o as a new vector
+func (v BitVector) AndNot(o BitVector) BitVector {
+	r := make(BitVector, len(v))
+	for i := range v {
+		r[i] = v[i] &^ o[i]
+	}
+	return r
+}
+
+// XorInto stores a ^ b in v without allocating
+func (v BitVector) XorInto(a, b BitVector) {
+	for i := range v {
+		v[i] = a[i] ^ b[i]
+	}
+}
+
+// XorEquals reports whether a ^ b == v without allocating
+func (v BitVector) XorEquals(a, b BitVector) bool {
+	for i := range v {
+		if a[i]^b[i] != v[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// AndPopCount returns the number of bits set in both v and o without allocating
+func (v BitVector) AndPopCount(o BitVector) int {
+	count := 0
+	for i := range v {
+		count += bits.OnesCount64(v[i] & o[i])
+	}
+	return count
+}
+
+// Key returns a string usable as a map key for the vector
+func (v BitVector) Key() string {
+	var sb strings.Builder
+	for _, w := range v {
+		fmt.Fprintf(&sb, "%016x", w)
+	}
+	return sb.String()
+}
+
+// parseBinaryRows converts a binary matrix into one bit vector per row, where
+// bit j of row i is the matrix entry in column j. It returns the number of
+// columns and rejects ragged rows and entries other than 0 and 1.
+func parseBinaryRows(matrix Matrix) ([]BitVector, int, error) {
+	if len(matrix) == 0 {
+		return nil, 0, fmt.Errorf("matris boş")
+	}
+
+	numCols := len(matrix[0])
+	if numCols == 0 {
+		return nil, 0, fmt.Errorf("matris satırı boş")
+	}
+
+	rows := make([]BitVector, len(matrix))
+	for i, row := range matrix {
+		if len(row) != numCols {
+			return nil, 0, fmt.Errorf("satır %d uzunluğu %d, beklenen %d", i, len(row), numCols)
+		}
+		rows[i] = NewBitVector(numCols)
+		for j, cell := range row {
+			switch strings.TrimSpace(cell) {
+			case "1":
+				rows[i].Set(j)
+			case "0":
+			default:
+				return nil, 0, fmt.Errorf("geçersiz matris değeri (%d,%d): %q", i, j, cell)
+			}
+		}
+	}
+
+	return rows, numCols, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// wideRow returns a matrix row of the given width with ones at bits
+func wideRow(width int, bits ...int) []string {
+	row := make([]string, width)
+	for j := range row {
+		row[j] = "0"
+	}
+	for _, bit := range bits {
+		row[bit] = "1"
+	}
+	return row
+}
+
+func TestBitVectorAcrossWords(t *testing.T) {
+	for _, width := range []int{1, 64, 65, 128, 129} {
+		if got, want := len(NewBitVector(width)), (width+63)/64; got != want {
+			t.Errorf("NewBitVector(%d) %d kelime, beklenen %d", width, got, want)
+		}
+	}
+
+	v := NewBitVector(128)
+	for _, bit := range []int{0, 63, 64, 127} {
+		v.Set(bit)
+	}
+	for bit := 0; bit < 128; bit++ {
+		want := bit == 0 || bit == 63 || bit == 64 || bit == 127
+		if v.Test(bit) != want {
+			t.Errorf("Test(%d) = %v", bit, !want)
+		}
+	}
+	if v.PopCount() != 4 {
+		t.Errorf("PopCount = %d, beklenen 4", v.PopCount())
+	}
+
+	v.Clear(64)
+	if v.Test(64) || v.PopCount() != 3 {
+		t.Errorf("Clear(64) sonrası %v", v)
+	}
+
+	u := UnitBitVector(128, 127)
+	if got := v.Xor(u); got.Test(127) || got.PopCount() != 2 {
+		t.Errorf("Xor yüksek kelimeyi temizlemedi: %v", got)
+	}
+	if got := v.And(u); !got.Equal(u) {
+		t.Errorf("And = %v, beklenen %v", got, u)
+	}
+	if got := v.AndNot(u); got.Test(127) || !got.Test(63) {
+		t.Errorf("AndNot = %v", got)
+	}
+	if v.AndPopCount(u) != 1 {
+		t.Errorf("AndPopCount = %d, beklenen 1", v.AndPopCount(u))
+	}
+
+	sum := NewBitVector(128)
+	sum.XorInto(v, u)
+	if !sum.Equal(v.Xor(u)) || !sum.XorEquals(v, u) {
+		t.Errorf("XorInto/XorEquals Xor ile uyuşmuyor: %v", sum)
+	}
+	if v.Clone().Equal(u) || !v.Clone().Equal(v) {
+		t.Errorf("Clone/Equal hatalı")
+	}
+	if v.Key() == u.Key() || v.Key() != v.Clone().Key() {
+		t.Errorf("Key iki kelimeyi ayırt etmiyor: %s %s", v.Key(), u.Key())
+	}
+	if !NewBitVector(128).IsZero() || v.IsZero() {
+		t.Errorf("IsZero hatalı")
+	}
+}
+
+func TestParseBinaryRowsWide(t *testing.T) {
+	rows, width, err := parseBinaryRows(Matrix{wideRow(65, 0, 64), wideRow(65, 63)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if width != 65 || len(rows) != 2 || len(rows[0]) != 2 {
+		t.Fatalf("genişlik %d, %d satır, %d kelime", width, len(rows), len(rows[0]))
+	}
+	if !rows[0].Test(0) || !rows[0].Test(64) || rows[0].PopCount() != 2 || !rows[1].Test(63) {
+		t.Errorf("satırlar yanlış okundu: %v", rows)
+	}
+
+	if _, _, err := parseBinaryRows(Matrix{wideRow(65), wideRow(64)}); err == nil {
+		t.Error("farklı uzunlukta satırlar kabul edildi")
+	}
+	bad := wideRow(65)
+	bad[64] = "2"
+	if _, _, err := parseBinaryRows(Matrix{bad}); err == nil {
+		t.Error("geçersiz değer kabul edildi")
+	}
+}
+
+func TestSolversWideMatrices(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix Matrix
+		xor    int // Optimal XOR count
+	}{
+		{
+			// y2 = x63 + x64 is shared by y1; x64 lives in the second word
+			name:   "65 kolon",
+			matrix: Matrix{wideRow(65, 0, 64), wideRow(65, 0, 63, 64), wideRow(65, 63, 64)},
+			xor:    3,
+		},
+		{
+			// Two copies of the 65-column pattern in the high and the low word
+			name: "128 kolon",
+			matrix: Matrix{
+				wideRow(128, 0, 127), wideRow(128, 0, 64, 127), wideRow(128, 64, 127),
+				wideRow(128, 1, 126), wideRow(128, 1, 65, 126), wideRow(128, 65, 126),
+			},
+			xor: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		rows, width, err := parseBinaryRows(tt.matrix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"boyar", "paar", "slp"} {
+			result, err := runSolver(context.Background(), name, nil, tt.matrix)
+			if err != nil {
+				t.Fatalf("%s %s: %v", tt.name, name, err)
+			}
+			if result.XorCount != tt.xor {
+				t.Errorf("%s %s: %d XOR, beklenen %d", tt.name, name, result.XorCount, tt.xor)
+			}
+			if result.Program.NumInputs != width {
+				t.Fatalf("%s %s: program %d girişli", tt.name, name, result.Program.NumInputs)
+			}
+			values := result.Program.Evaluate()
+			for i, row := range rows {
+				if s := result.Program.Outputs[i]; s < 0 || !values[s].Equal(row) {
+					t.Errorf("%s %s: y%d hesaplanmadı (%v)", tt.name, name, i, result.Program.Lines())
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,546 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+)
+
+// canonicalSearchBudget bounds the search tree nodes canonicalize visits.
+// When it runs out the smallest form found so far is used: it is still a
+// permutation of the matrix, so equal canonical hashes always mean equivalent
+// matrices, but some equivalent matrices may then get different hashes.
+const canonicalSearchBudget = 4096
+
+// maxStoredAutomorphisms bounds the automorphisms kept for pruning
+const maxStoredAutomorphisms = 64
+
+// CanonicalForm is the representative of a matrix under row and column
+// permutations: Matrix[i][j] = original[Rows[i]][Columns[j]]. Row permutations
+// reorder the outputs and column permutations relabel the inputs, so
+// equivalent matrices have the same XOR costs.
+type CanonicalForm struct {
+	Matrix  Matrix
+	Rows    []int // Original row at each canonical row
+	Columns []int // Original column at each canonical column
+	Exact   bool  // False if the search budget ran out before the search finished
+}
+
+// Hash returns the canonical_hash of the form
+func (c *CanonicalForm) Hash() string {
+	return calculateMatrixHash(c.Matrix)
+}
+
+// calculateCanonicalHash returns the hash shared by all row and column permutations of matrix
+func calculateCanonicalHash(matrix Matrix) (string, error) {
+	canonical, err := canonicalize(matrix)
+	if err != nil {
+		return "", err
+	}
+	return canonical.Hash(), nil
+}
+
+// bipartiteGraph is a binary matrix as a graph: vertices 0..rows-1 are the
+// rows, rows..rows+columns-1 the columns, and every 1 entry is an edge
+type bipartiteGraph struct {
+	rows, columns int
+	adjacent      [][]int
+	bits          [][]byte // bits[i][j] is 1 if entry (i, j) is set
+}
+
+// canonicalize returns the canonical form of a binary matrix: the smallest
+// permuted matrix, read row by row, among the leaves of an
+// individualization-refinement search. Vertex colors are refined by the
+// colors of their neighbors; branches whose vertices are in the same orbit
+// of the automorphisms found so far are pruned.
+func canonicalize(matrix Matrix) (*CanonicalForm, error) {
+	if len(matrix) == 0 || len(matrix[0]) == 0 {
+		return nil, fmt.Errorf("matris boş")
+	}
+	g := &bipartiteGraph{rows: len(matrix), columns: len(matrix[0])}
+	g.adjacent = make([][]int, g.rows+g.columns)
+	g.bits = make([][]byte, g.rows)
+	for i, row := range matrix {
+		if len(row) != g.columns {
+			return nil, fmt.Errorf("satır %d uzunluğu farklı: %d != %d", i, len(row), g.columns)
+		}
+		g.bits[i] = make([]byte, g.columns)
+		for j, bit := range row {
+			switch bit {
+			case "1":
+				g.bits[i][j] = 1
+				g.adjacent[i] = append(g.adjacent[i], g.rows+j)
+				g.adjacent[g.rows+j] = append(g.adjacent[g.rows+j], i)
+			case "0":
+			default:
+				return nil, fmt.Errorf("geçersiz matris elemanı: %s", bit)
+			}
+		}
+	}
+
+	// Colors are the start positions of the cells of an ordered partition;
+	// rows and columns start in separate cells and never mix
+	colors := make([]int, g.rows+g.columns)
+	for v := g.rows; v < len(colors); v++ {
+		colors[v] = g.rows
+	}
+
+	s := &canonicalSearch{graph: g, budget: canonicalSearchBudget}
+	s.search(g.refine(colors), nil)
+
+	form := &CanonicalForm{
+		Matrix:  make(Matrix, g.rows),
+		Rows:    make([]int, g.rows),
+		Columns: make([]int, g.columns),
+		Exact:   s.budget >= 0,
+	}
+	for v, position := range s.bestColors {
+		if v < g.rows {
+			form.Rows[position] = v
+		} else {
+			form.Columns[position-g.rows] = v - g.rows
+		}
+	}
+	for i, r := range form.Rows {
+		form.Matrix[i] = make([]string, g.columns)
+		for j, c := range form.Columns {
+			form.Matrix[i][j] = matrix[r][c]
+		}
+	}
+	return form, nil
+}
+
+// refine splits cells by the multiset of neighbor colors until the partition
+// is equitable. Splits are ordered by signature, so the result does not depend
+// on vertex labels.
+func (g *bipartiteGraph) refine(colors []int) []int {
+	n := len(colors)
+	colors = append([]int(nil), colors...)
+	order := make([]int, n)
+	signatures := make([][]int, n)
+	cells := countCells(colors)
+	for {
+		for v := range order {
+			order[v] = v
+			signature := signatures[v][:0]
+			for _, u := range g.adjacent[v] {
+				signature = append(signature, colors[u])
+			}
+			sort.Ints(signature)
+			signatures[v] = signature
+		}
+		sort.Slice(order, func(a, b int) bool {
+			u, v := order[a], order[b]
+			if colors[u] != colors[v] {
+				return colors[u] < colors[v]
+			}
+			return compareInts(signatures[u], signatures[v]) < 0
+		})
+
+		refined := make([]int, n)
+		for k, v := range order {
+			if k > 0 {
+				prev := order[k-1]
+				if colors[prev] == colors[v] && compareInts(signatures[prev], signatures[v]) == 0 {
+					refined[v] = refined[prev]
+					continue
+				}
+			}
+			refined[v] = k
+		}
+		colors = refined
+
+		count := countCells(colors)
+		if count == cells {
+			return colors
+		}
+		cells = count
+	}
+}
+
+// canonicalSearch holds the state of one canonicalize search
+type canonicalSearch struct {
+	graph         *bipartiteGraph
+	budget        int
+	best          []byte // Smallest certificate found
+	bestColors    []int
+	bestPath      []int
+	first         []byte // Certificate of the first leaf
+	firstColors   []int
+	firstPath     []int
+	automorphisms [][]int // Vertex permutations found by comparing leaves
+}
+
+// search explores the subtree of a refined partition reached by
+// individualizing the vertices of path in order. It returns the depth to
+// jump back to when a leaf matched the first or best leaf, -1 otherwise.
+func (s *canonicalSearch) search(colors []int, path []int) int {
+	// The first path is always followed to a leaf so there is a form to return
+	if s.budget--; s.budget < 0 && s.first != nil {
+		return -1
+	}
+
+	cell := targetCell(colors)
+	if cell == nil {
+		return s.leaf(colors, path)
+	}
+
+	var explored []int
+	for _, v := range cell {
+		if s.budget < 0 && s.first != nil {
+			return -1
+		}
+		if s.sameOrbit(v, explored, path) {
+			continue
+		}
+		explored = append(explored, v)
+
+		individualized := append([]int(nil), colors...)
+		for _, u := range cell {
+			if u != v {
+				individualized[u] = colors[v] + 1
+			}
+		}
+		if jump := s.search(s.graph.refine(individualized), append(path[:len(path):len(path)], v)); jump >= 0 && jump < len(path) {
+			return jump
+		}
+	}
+	return -1
+}
+
+// leaf compares the certificate of a discrete partition with the first and
+// best leaves. A match is an automorphism mapping the subtree below the node
+// where the paths part onto the matched leaf's subtree, so the search jumps
+// back to that node.
+func (s *canonicalSearch) leaf(colors []int, path []int) int {
+	certificate := s.graph.certificate(colors)
+	if s.first == nil {
+		s.first, s.firstColors, s.firstPath = certificate, colors, path
+		s.best, s.bestColors, s.bestPath = certificate, colors, path
+		return -1
+	}
+
+	order := bytes.Compare(certificate, s.best)
+	switch {
+	case bytes.Equal(certificate, s.first):
+		s.addAutomorphism(colors, s.firstColors)
+		return commonPrefix(path, s.firstPath)
+	case order == 0:
+		s.addAutomorphism(colors, s.bestColors)
+		return commonPrefix(path, s.bestPath)
+	case order < 0:
+		s.best, s.bestColors, s.bestPath = certificate, colors, path
+	}
+	return -1
+}
+
+// commonPrefix returns the number of leading vertices a and b share
+func commonPrefix(a, b []int) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// addAutomorphism stores the permutation mapping each vertex of one leaf to
+// the vertex at the same position of another leaf with the same certificate
+func (s *canonicalSearch) addAutomorphism(colors, other []int) {
+	if len(s.automorphisms) >= maxStoredAutomorphisms {
+		return
+	}
+	at := make([]int, len(other))
+	for v, position := range other {
+		at[position] = v
+	}
+	gamma := make([]int, len(colors))
+	identity := true
+	for v, position := range colors {
+		gamma[v] = at[position]
+		identity = identity && gamma[v] == v
+	}
+	if !identity {
+		s.automorphisms = append(s.automorphisms, gamma)
+	}
+}
+
+// sameOrbit reports whether v is in the orbit of an explored vertex under the
+// automorphisms found so far that fix every vertex of path. Their subtrees
+// are images of each other and hold the same certificates.
+func (s *canonicalSearch) sameOrbit(v int, explored, path []int) bool {
+	if len(explored) == 0 || len(s.automorphisms) == 0 {
+		return false
+	}
+	parent := make(map[int]int)
+	var find func(int) int
+	find = func(x int) int {
+		p, ok := parent[x]
+		if !ok || p == x {
+			return x
+		}
+		root := find(p)
+		parent[x] = root
+		return root
+	}
+
+	for _, gamma := range s.automorphisms {
+		fixes := true
+		for _, p := range path {
+			if gamma[p] != p {
+				fixes = false
+				break
+			}
+		}
+		if !fixes {
+			continue
+		}
+		for x, y := range gamma {
+			if rx, ry := find(x), find(y); rx != ry {
+				parent[rx] = ry
+			}
+		}
+	}
+
+	root := find(v)
+	for _, u := range explored {
+		if find(u) == root {
+			return true
+		}
+	}
+	return false
+}
+
+// certificate returns the matrix permuted by a discrete partition, one byte per entry
+func (g *bipartiteGraph) certificate(colors []int) []byte {
+	rowAt := make([]int, g.rows)
+	columnAt := make([]int, g.columns)
+	for v, position := range colors {
+		if v < g.rows {
+			rowAt[position] = v
+		} else {
+			columnAt[position-g.rows] = v - g.rows
+		}
+	}
+	certificate := make([]byte, 0, g.rows*g.columns)
+	for _, r := range rowAt {
+		for _, c := range columnAt {
+			certificate = append(certificate, g.bits[r][c])
+		}
+	}
+	return certificate
+}
+
+// targetCell returns the vertices of the first cell with more than one
+// vertex in vertex order, or nil if the partition is discrete
+func targetCell(colors []int) []int {
+	sizes := make([]int, len(colors))
+	for _, c := range colors {
+		sizes[c]++
+	}
+	target := -1
+	for c, size := range sizes {
+		if size > 1 {
+			target = c
+			break
+		}
+	}
+	if target < 0 {
+		return nil
+	}
+	var cell []int
+	for v, c := range colors {
+		if c == target {
+			cell = append(cell, v)
+		}
+	}
+	return cell
+}
+
+// countCells returns the number of distinct colors
+func countCells(colors []int) int {
+	seen := make([]bool, len(colors))
+	count := 0
+	for _, c := range colors {
+		if !seen[c] {
+			seen[c] = true
+			count++
+		}
+	}
+	return count
+}
+
+// compareInts compares two int slices lexicographically
+func compareInts(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// remapProgram returns the program of an equivalent matrix: program computes
+// the matrix whose canonical form is from, the result computes the matrix
+// whose canonical form is to. Both forms must have the same canonical matrix.
+func remapProgram(program *Program, from, to *CanonicalForm) *Program {
+	// Input from.Columns[j] of the source is input to.Columns[j] of the target
+	input := make([]int, program.NumInputs)
+	for j, c := range from.Columns {
+		input[c] = to.Columns[j]
+	}
+	signal := func(s int) int {
+		if s >= 0 && s < program.NumInputs {
+			return input[s]
+		}
+		return s
+	}
+
+	remapped := &Program{NumInputs: program.NumInputs, Gates: make([]Gate, len(program.Gates)), Outputs: make([]int, len(program.Outputs))}
+	for k, gate := range program.Gates {
+		remapped.Gates[k] = Gate{A: signal(gate.A), B: signal(gate.B), Depth: gate.Depth}
+	}
+	// Output from.Rows[i] of the source is output to.Rows[i] of the target
+	for i, r := range from.Rows {
+		remapped.Outputs[to.Rows[i]] = signal(program.Outputs[r])
+	}
+	return remapped
+}
+
+// reuseEquivalentResults returns results for the named solvers taken from
+// stored matrices with the same canonical hash as the matrix with the given
+// ID. Programs are remapped to the matrix and verified; solvers without a
+// usable stored program are left out.
+func reuseEquivalentResults(store MatrixStore, id int, matrix Matrix, algorithms []string) map[string]*AlgResult {
+	reused := make(map[string]*AlgResult)
+	record, err := store.GetMatrixByID(id)
+	if err != nil || record == nil || record.CanonicalHash == nil {
+		return reused
+	}
+	equivalents, err := store.GetEquivalentMatrices(record)
+	if err != nil {
+		log.Printf("⚠️  [CANONICAL] Matris %d için eşdeğer matrisler alınamadı: %v", id, err)
+		return reused
+	}
+	if len(equivalents) == 0 {
+		return reused
+	}
+
+	target, err := canonicalize(matrix)
+	if err != nil {
+		return reused
+	}
+	for _, equivalent := range equivalents {
+		source, err := parseMatrixFromBinary(equivalent.MatrixBinary)
+		if err != nil {
+			continue
+		}
+		from, err := canonicalize(source)
+		if err != nil || !equalMatrix(from.Matrix, target.Matrix) {
+			continue
+		}
+
+		for _, name := range algorithms {
+			info, ok := GetSolverInfo(name)
+			if !ok || !info.Persisted {
+				continue
+			}
+			stored := equivalent.Result(info.Name)
+			if stored == nil || stored.XorCount == nil || stored.Program == nil {
+				continue
+			}
+			if best := reused[info.Name]; best != nil && best.XorCount <= *stored.XorCount {
+				continue
+			}
+
+			remapped := remapProgram(stored.Program, from, target)
+			if verification := VerifyProgram(matrix, remapped); !verification.Valid {
+				log.Printf("⚠️  [CANONICAL] Matris %d %s programı matris %d için geçersiz: %v",
+					equivalent.ID, info.Name, id, verification.Errors)
+				continue
+			}
+			// The run keeps the depth limit and transpose option of the stored
+			// one, so it is compared with runs made under the same parameters
+			result := &AlgResult{
+				XorCount:   remapped.XorCount(),
+				Program:    remapped,
+				Seed:       stored.Seed,
+				Status:     StatusCompleted,
+				DepthLimit: stored.DepthLimit,
+				PeakLive:   remapped.Schedule().PeakLive(),
+				Parameters: SolverParams{"reused_from": equivalent.ID},
+			}
+			if stored.Transpose {
+				result.Parameters["transpose"] = true
+			}
+			if info.HasDepth {
+				result.Depth = remapped.Depth()
+			}
+			if stored.Status != nil {
+				result.Status = *stored.Status
+			}
+			if stored.Origin != nil {
+				result.Origin = *stored.Origin
+			}
+			reused[info.Name] = result
+		}
+	}
+	return reused
+}
+
+// equalMatrix reports whether a and b have the same entries
+func equalMatrix(a, b Matrix) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// fillCanonicalHashes computes the canonical hash of stored matrices that
+// have none yet, e.g. those saved before the column existed
+func (d *Database) fillCanonicalHashes() {
+	rows, err := d.db.Query("SELECT id, matrix_binary FROM matrix_records WHERE canonical_hash IS NULL ORDER BY id")
+	if err != nil {
+		log.Printf("❌ [CANONICAL] Kanonik hash'i olmayan matrisler alınamadı: %v", err)
+		return
+	}
+	hashes := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var binary string
+		if err := rows.Scan(&id, &binary); err != nil {
+			continue
+		}
+		matrix, err := parseMatrixFromBinary(binary)
+		if err != nil {
+			continue
+		}
+		if hash, err := calculateCanonicalHash(matrix); err == nil {
+			hashes[id] = hash
+		}
+	}
+	rows.Close()
+	if len(hashes) == 0 {
+		return
+	}
+
+	for id, hash := range hashes {
+		if _, err := d.db.Exec("UPDATE matrix_records SET canonical_hash = $1 WHERE id = $2", hash, id); err != nil {
+			log.Printf("⚠️  [CANONICAL] Matris %d kanonik hash'i kaydedilemedi: %v", id, err)
+		}
+	}
+	log.Printf("✅ [CANONICAL] %d matris için kanonik hash hesaplandı", len(hashes))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// permuteMatrix returns m with its rows and columns randomly permuted
+func permuteMatrix(m Matrix, rng *rand.Rand) Matrix {
+	rows, columns := rng.Perm(len(m)), rng.Perm(len(m[0]))
+	permuted := make(Matrix, len(m))
+	for i, r := range rows {
+		permuted[i] = make([]string, len(columns))
+		for j, c := range columns {
+			permuted[i][j] = m[r][c]
+		}
+	}
+	return permuted
+}
+
+// canonicalMatrices returns small matrices with many automorphisms
+func canonicalMatrices(t *testing.T) map[string]Matrix {
+	field, err := NewGF2m("x^4+x+1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	elements, err := field.ParseMatrix(mdsMatrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity := make(Matrix, 6)
+	for i := range identity {
+		identity[i] = wideRow(6, i)
+	}
+	return map[string]Matrix{
+		"verify":   verifyMatrix,
+		"identity": identity,
+		"mds":      field.Expand(elements),
+		"circulant": {
+			wideRow(5, 0, 1, 3), wideRow(5, 1, 2, 4), wideRow(5, 2, 3, 0),
+			wideRow(5, 3, 4, 1), wideRow(5, 4, 0, 2),
+		},
+	}
+}
+
+func TestCanonicalizePermutationInvariant(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	hashes := make(map[string]string)
+	for name, matrix := range canonicalMatrices(t) {
+		canonical, err := canonicalize(matrix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !canonical.Exact {
+			t.Errorf("%s: arama bütçesi aşıldı", name)
+		}
+		if other, ok := hashes[canonical.Hash()]; ok {
+			t.Errorf("%s ve %s aynı kanonik hash'e sahip", name, other)
+		}
+		hashes[canonical.Hash()] = name
+
+		for round := 0; round < 5; round++ {
+			permuted := permuteMatrix(matrix, rng)
+			form, err := canonicalize(permuted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalMatrix(form.Matrix, canonical.Matrix) {
+				t.Errorf("%s: permütasyon %d farklı kanonik biçim verdi", name, round)
+			}
+			// The form must be the permuted matrix read through Rows and Columns
+			for i, r := range form.Rows {
+				for j, c := range form.Columns {
+					if form.Matrix[i][j] != permuted[r][c] {
+						t.Fatalf("%s: kanonik biçim (%d, %d) permütasyonla uyuşmuyor", name, i, j)
+					}
+				}
+			}
+		}
+	}
+
+	if _, err := canonicalize(Matrix{}); err == nil {
+		t.Error("boş matris kabul edildi")
+	}
+}
+
+func TestRemapProgram(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for name, matrix := range canonicalMatrices(t) {
+		from, err := canonicalize(matrix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, algorithm := range []string{"paar", "slp"} {
+			result, err := runSolver(context.Background(), algorithm, nil, matrix)
+			if err != nil {
+				t.Fatalf("%s %s: %v", name, algorithm, err)
+			}
+			permuted := permuteMatrix(matrix, rng)
+			to, err := canonicalize(permuted)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			remapped := remapProgram(result.Program, from, to)
+			v := VerifyProgram(permuted, remapped)
+			if !v.Valid {
+				t.Errorf("%s %s: eşlenen program doğrulanamadı: %v", name, algorithm, v.Errors)
+				continue
+			}
+			if remapped.XorCount() != result.XorCount || remapped.Depth() != result.Program.Depth() {
+				t.Errorf("%s %s: eşleme maliyeti değiştirdi: %d/%d, beklenen %d/%d", name, algorithm,
+					remapped.XorCount(), remapped.Depth(), result.XorCount, result.Program.Depth())
+			}
+		}
+	}
+}
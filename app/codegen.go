@@ -0,0 +1,355 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// codegenTestVectors is the number of random input vectors the generated
+// harnesses check against naive matrix-vector multiplication
+const codegenTestVectors = 1000
+
+// CodegenLanguages lists the languages GenerateCode emits
+var CodegenLanguages = []string{"c", "go", "python", "verilog", "vhdl"}
+
+// GeneratedFile is one source file of a code generation result
+type GeneratedFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// GeneratedCode is a stored program rendered as source code with its test harness
+type GeneratedCode struct {
+	MatrixID  int             `json:"matrix_id"`
+	Algorithm string          `json:"algorithm"`
+	Language  string          `json:"language"`
+	XorCount  int             `json:"xor_count"`
+	Depth     int             `json:"depth"`
+	Lanes     int             `json:"lanes,omitempty"`     // Word size of bitsliced routines
+	PeakLive  int             `json:"peak_live,omitempty"` // Temporaries live at once in bitsliced routines
+	Files     []GeneratedFile `json:"files"`
+}
+
+// codegenUnit holds everything the language generators need
+type codegenUnit struct {
+	ID        int
+	Title     string
+	Algorithm string
+	Rows      []BitVector
+	NumInputs int
+	Program   *Program
+}
+
+// selectStoredProgram returns the stored program of the named solver. "best"
+// (or an empty name) picks the valid stored program with the fewest XOR
+// gates, preferring the smaller depth on ties.
+func selectStoredProgram(record *MatrixRecord, matrix Matrix, algorithm string) (string, *Program, error) {
+	algorithm = strings.ToLower(strings.TrimSpace(algorithm))
+	if algorithm != "" && algorithm != "best" {
+		info, ok := GetSolverInfo(algorithm)
+		if !ok || !info.Persisted {
+			return "", nil, fmt.Errorf("desteklenmeyen algoritma: %s", algorithm)
+		}
+		program := record.StoredProgram(info.Name)
+		if program == nil {
+			return "", nil, fmt.Errorf("%s için kayıtlı program yok", info.Name)
+		}
+		if v := VerifyProgram(matrix, program); !v.Valid {
+			return "", nil, fmt.Errorf("%s programı doğrulanamadı: %s", info.Name, strings.Join(v.Errors, "; "))
+		}
+		return info.Name, program, nil
+	}
+
+	var bestName string
+	var best *Program
+	for _, info := range persistedSolvers() {
+		program := record.StoredProgram(info.Name)
+		if program == nil || !VerifyProgram(matrix, program).Valid {
+			continue
+		}
+		if best == nil || program.XorCount() < best.XorCount() ||
+			(program.XorCount() == best.XorCount() && program.Depth() < best.Depth()) {
+			bestName, best = info.Name, program
+		}
+	}
+	if best == nil {
+		return "", nil, fmt.Errorf("doğrulanmış kayıtlı program yok")
+	}
+	return bestName, best, nil
+}
+
+// GenerateCode renders the chosen stored program of record as a function in
+// lang together with a harness that checks it against naive matrix-vector
+// multiplication on random inputs
+func GenerateCode(record *MatrixRecord, lang, algorithm string) (*GeneratedCode, error) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+
+	unit, err := newCodegenUnit(record, algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []GeneratedFile
+	switch lang {
+	case "c":
+		files = generateC(unit)
+	case "go":
+		files = generateGo(unit)
+	case "python":
+		files = generatePython(unit)
+	case "verilog":
+		files = generateVerilog(unit)
+	case "vhdl":
+		files = generateVHDL(unit)
+	default:
+		return nil, fmt.Errorf("desteklenmeyen dil: %s (desteklenenler: %s)", lang, strings.Join(CodegenLanguages, ", "))
+	}
+
+	return &GeneratedCode{
+		MatrixID:  record.ID,
+		Algorithm: unit.Algorithm,
+		Language:  lang,
+		XorCount:  unit.Program.XorCount(),
+		Depth:     unit.Program.Depth(),
+		Files:     files,
+	}, nil
+}
+
+// newCodegenUnit parses the matrix of record and selects the program to render
+func newCodegenUnit(record *MatrixRecord, algorithm string) (*codegenUnit, error) {
+	matrix, err := parseMatrixFromBinary(record.MatrixBinary)
+	if err != nil {
+		return nil, fmt.Errorf("matris parse edilemedi: %v", err)
+	}
+	rows, numInputs, err := parseBinaryRows(matrix)
+	if err != nil {
+		return nil, fmt.Errorf("matris parse edilemedi: %v", err)
+	}
+
+	name, program, err := selectStoredProgram(record, matrix, algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &codegenUnit{
+		ID:        record.ID,
+		Title:     record.Title,
+		Algorithm: name,
+		Rows:      rows,
+		NumInputs: numInputs,
+		Program:   program,
+	}, nil
+}
+
+// summary returns the comment lines describing the generated function
+func (u *codegenUnit) summary() []string {
+	title := strings.Join(strings.Fields(u.Title), " ")
+	title = strings.ReplaceAll(title, "*/", "* /")
+	if title == "" {
+		title = fmt.Sprintf("matrix %d", u.ID)
+	}
+	return []string{
+		fmt.Sprintf("%s (%dx%d)", title, len(u.Rows), u.NumInputs),
+		fmt.Sprintf("Algorithm: %s, %d XOR, depth %d", u.Algorithm, u.Program.XorCount(), u.Program.Depth()),
+		"Generated by xor-opt. Input bit j is matrix column j, output bit i is row i.",
+	}
+}
+
+// signal returns the expression of signal s, inputs are rendered with input
+func (u *codegenUnit) signal(s int, input string) string {
+	if s < u.NumInputs {
+		return fmt.Sprintf("%s[%d]", input, s)
+	}
+	return fmt.Sprintf("t%d", s-u.NumInputs)
+}
+
+// usedGates reports which gates are read by another gate or an output
+func (u *codegenUnit) usedGates() []bool {
+	used := make([]bool, len(u.Program.Gates))
+	mark := func(s int) {
+		if s >= u.NumInputs {
+			used[s-u.NumInputs] = true
+		}
+	}
+	for _, gate := range u.Program.Gates {
+		mark(gate.A)
+		mark(gate.B)
+	}
+	for _, s := range u.Program.Outputs {
+		mark(s)
+	}
+	return used
+}
+
+// matrixLiteral renders the matrix rows as nested 0/1 lists
+func (u *codegenUnit) matrixLiteral(open, close, indent string) string {
+	var b strings.Builder
+	for _, row := range u.Rows {
+		bits := make([]string, u.NumInputs)
+		for j := range bits {
+			bits[j] = "0"
+			if row.Test(j) {
+				bits[j] = "1"
+			}
+		}
+		fmt.Fprintf(&b, "%s%s%s%s,\n", indent, open, strings.Join(bits, ", "), close)
+	}
+	return b.String()
+}
+
+func generateC(u *codegenUnit) []GeneratedFile {
+	base := fmt.Sprintf("xor_opt_%d", u.ID)
+	m, n := len(u.Rows), u.NumInputs
+
+	var code strings.Builder
+	code.WriteString("/*\n")
+	for _, line := range u.summary() {
+		fmt.Fprintf(&code, " * %s\n", line)
+	}
+	code.WriteString(" */\n#include <stdint.h>\n\n")
+	fmt.Fprintf(&code, "#define %s_INPUTS %d\n", strings.ToUpper(base), n)
+	fmt.Fprintf(&code, "#define %s_OUTPUTS %d\n\n", strings.ToUpper(base), m)
+	fmt.Fprintf(&code, "void %s(const uint8_t x[%d], uint8_t y[%d])\n{\n", base, n, m)
+	for k, gate := range u.Program.Gates {
+		fmt.Fprintf(&code, "    uint8_t t%d = %s ^ %s; /* depth %d */\n", k, u.signal(gate.A, "x"), u.signal(gate.B, "x"), gate.Depth)
+	}
+	for i, s := range u.Program.Outputs {
+		if s < 0 {
+			fmt.Fprintf(&code, "    y[%d] = 0;\n", i)
+		} else {
+			fmt.Fprintf(&code, "    y[%d] = %s;\n", i, u.signal(s, "x"))
+		}
+	}
+	code.WriteString("}\n")
+
+	var harness strings.Builder
+	fmt.Fprintf(&harness, "/* Test harness for %s.c: compares it with naive matrix-vector multiplication */\n", base)
+	harness.WriteString("#include <stdio.h>\n#include <stdlib.h>\n\n")
+	fmt.Fprintf(&harness, "#include \"%s.c\"\n\n", base)
+	fmt.Fprintf(&harness, "static const uint8_t matrix[%d][%d] = {\n%s};\n\n", m, n, u.matrixLiteral("{", "}", "    "))
+	harness.WriteString("int main(void)\n{\n")
+	fmt.Fprintf(&harness, "    uint8_t x[%d], y[%d];\n", n, m)
+	harness.WriteString("    srand(1);\n")
+	fmt.Fprintf(&harness, "    for (int v = 0; v < %d; v++) {\n", codegenTestVectors)
+	fmt.Fprintf(&harness, "        for (int j = 0; j < %d; j++)\n            x[j] = rand() & 1;\n", n)
+	fmt.Fprintf(&harness, "        %s(x, y);\n", base)
+	fmt.Fprintf(&harness, "        for (int i = 0; i < %d; i++) {\n", m)
+	harness.WriteString("            uint8_t want = 0;\n")
+	fmt.Fprintf(&harness, "            for (int j = 0; j < %d; j++)\n                want ^= matrix[i][j] & x[j];\n", n)
+	harness.WriteString("            if (y[i] != want) {\n")
+	harness.WriteString("                printf(\"FAIL: vector %d, output %d\\n\", v, i);\n")
+	harness.WriteString("                return 1;\n            }\n        }\n    }\n")
+	fmt.Fprintf(&harness, "    printf(\"OK: %d vectors\\n\");\n    return 0;\n}\n", codegenTestVectors)
+
+	return []GeneratedFile{
+		{Name: base + ".c", Content: code.String()},
+		{Name: base + "_test.c", Content: harness.String()},
+	}
+}
+
+func generateGo(u *codegenUnit) []GeneratedFile {
+	base := fmt.Sprintf("xor_opt_%d", u.ID)
+	function := fmt.Sprintf("XorOpt%d", u.ID)
+	m, n := len(u.Rows), u.NumInputs
+
+	var code strings.Builder
+	for _, line := range u.summary() {
+		fmt.Fprintf(&code, "// %s\n", line)
+	}
+	code.WriteString("package xoropt\n\n")
+	fmt.Fprintf(&code, "// %s multiplies the input bits x by the matrix\n", function)
+	fmt.Fprintf(&code, "func %s(x [%d]byte) (y [%d]byte) {\n", function, n, m)
+	used := u.usedGates()
+	for k, gate := range u.Program.Gates {
+		fmt.Fprintf(&code, "\tt%d := %s ^ %s // depth %d\n", k, u.signal(gate.A, "x"), u.signal(gate.B, "x"), gate.Depth)
+		if !used[k] {
+			fmt.Fprintf(&code, "\t_ = t%d\n", k)
+		}
+	}
+	for i, s := range u.Program.Outputs {
+		if s >= 0 {
+			fmt.Fprintf(&code, "\ty[%d] = %s\n", i, u.signal(s, "x"))
+		}
+	}
+	code.WriteString("\treturn y\n}\n")
+
+	var harness strings.Builder
+	harness.WriteString("package xoropt\n\nimport (\n\t\"math/rand\"\n\t\"testing\"\n)\n\n")
+	fmt.Fprintf(&harness, "var matrix%d = [%d][%d]byte{\n%s}\n\n", u.ID, m, n, u.matrixLiteral("{", "}", "\t"))
+	fmt.Fprintf(&harness, "// Test%s compares %s with naive matrix-vector multiplication\n", function, function)
+	fmt.Fprintf(&harness, "func Test%s(t *testing.T) {\n", function)
+	harness.WriteString("\trng := rand.New(rand.NewSource(1))\n")
+	fmt.Fprintf(&harness, "\tfor v := 0; v < %d; v++ {\n", codegenTestVectors)
+	fmt.Fprintf(&harness, "\t\tvar x [%d]byte\n", n)
+	harness.WriteString("\t\tfor j := range x {\n\t\t\tx[j] = byte(rng.Intn(2))\n\t\t}\n")
+	fmt.Fprintf(&harness, "\t\ty := %s(x)\n", function)
+	fmt.Fprintf(&harness, "\t\tfor i, row := range matrix%d {\n", u.ID)
+	harness.WriteString("\t\t\tvar want byte\n")
+	harness.WriteString("\t\t\tfor j, bit := range row {\n\t\t\t\twant ^= bit & x[j]\n\t\t\t}\n")
+	harness.WriteString("\t\t\tif y[i] != want {\n")
+	harness.WriteString("\t\t\t\tt.Fatalf(\"vector %d: y[%d] = %d, want %d\", v, i, y[i], want)\n")
+	harness.WriteString("\t\t\t}\n\t\t}\n\t}\n}\n")
+
+	return []GeneratedFile{
+		{Name: base + ".go", Content: gofmtSource(code.String())},
+		{Name: base + "_test.go", Content: gofmtSource(harness.String())},
+	}
+}
+
+// gofmtSource aligns the generated Go source the way gofmt would
+func gofmtSource(src string) string {
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return src
+	}
+	return string(formatted)
+}
+
+func generatePython(u *codegenUnit) []GeneratedFile {
+	base := fmt.Sprintf("xor_opt_%d", u.ID)
+	n := u.NumInputs
+
+	var code strings.Builder
+	for _, line := range u.summary() {
+		fmt.Fprintf(&code, "# %s\n", line)
+	}
+	fmt.Fprintf(&code, "\n\ndef %s(x):\n", base)
+	fmt.Fprintf(&code, "    \"\"\"Multiply the %d input bits x by the matrix and return the output bits.\"\"\"\n", n)
+	for k, gate := range u.Program.Gates {
+		fmt.Fprintf(&code, "    t%d = %s ^ %s  # depth %d\n", k, u.signal(gate.A, "x"), u.signal(gate.B, "x"), gate.Depth)
+	}
+	outputs := make([]string, len(u.Program.Outputs))
+	for i, s := range u.Program.Outputs {
+		outputs[i] = "0"
+		if s >= 0 {
+			outputs[i] = u.signal(s, "x")
+		}
+	}
+	code.WriteString("    return [\n")
+	for _, output := range outputs {
+		fmt.Fprintf(&code, "        %s,\n", output)
+	}
+	code.WriteString("    ]\n")
+
+	var harness strings.Builder
+	fmt.Fprintf(&harness, "# Test harness for %s.py: compares it with naive matrix-vector multiplication\n", base)
+	fmt.Fprintf(&harness, "import random\nimport sys\n\nfrom %s import %s\n\n", base, base)
+	fmt.Fprintf(&harness, "MATRIX = [\n%s]\n\n\n", u.matrixLiteral("[", "]", "    "))
+	harness.WriteString("def naive(x):\n")
+	harness.WriteString("    y = []\n    for row in MATRIX:\n        bit = 0\n")
+	harness.WriteString("        for m, b in zip(row, x):\n            bit ^= m & b\n")
+	harness.WriteString("        y.append(bit)\n    return y\n\n\n")
+	harness.WriteString("def main():\n    rng = random.Random(1)\n")
+	fmt.Fprintf(&harness, "    for v in range(%d):\n", codegenTestVectors)
+	fmt.Fprintf(&harness, "        x = [rng.getrandbits(1) for _ in range(%d)]\n", n)
+	fmt.Fprintf(&harness, "        if %s(x) != naive(x):\n", base)
+	harness.WriteString("            print(\"FAIL: vector %d\" % v)\n            return 1\n")
+	fmt.Fprintf(&harness, "    print(\"OK: %d vectors\")\n    return 0\n\n\n", codegenTestVectors)
+	harness.WriteString("if __name__ == \"__main__\":\n    sys.exit(main())\n")
+
+	return []GeneratedFile{
+		{Name: base + ".py", Content: code.String()},
+		{Name: "test_" + base + ".py", Content: harness.String()},
+	}
+}
@@ -19,26 +19,33 @@ type Config struct {
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
+	Driver   string `json:"driver"` // DriverPostgres (default), DriverSQLite or DriverMemory
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
 	User     string `json:"user"`
 	Password string `json:"password"`
 	DBName   string `json:"dbname"`
 	SSLMode  string `json:"sslmode"`
+	Path     string `json:"path"` // SQLite database file
 }
 
+// defaultSQLitePath is the SQLite file used when database.path is empty
+const defaultSQLitePath = "./xor_opt.db"
+
 // ImportConfig holds auto import configuration
 type ImportConfig struct {
-	Enabled         bool     `json:"enabled"`
-	DataDirectory   string   `json:"data_directory"`
-	FileExtensions  []string `json:"file_extensions"`
-	MaxFileSize     int64    `json:"max_file_size_mb"`
-	ProcessOnStart  bool     `json:"process_on_start"`
-	WatchDirectory  bool     `json:"watch_directory"`
-	SkipExisting    bool     `json:"skip_existing"`
-	BatchSize       int      `json:"batch_size"`
-	AutoCalculate   bool     `json:"auto_calculate"`
-	Algorithms      []string `json:"algorithms"`
+	Enabled              bool     `json:"enabled"`
+	DataDirectory        string   `json:"data_directory"`
+	FileExtensions       []string `json:"file_extensions"`
+	MaxFileSize          int64    `json:"max_file_size_mb"`
+	ProcessOnStart       bool     `json:"process_on_start"`
+	WatchDirectory       bool     `json:"watch_directory"`
+	SkipExisting         bool     `json:"skip_existing"`
+	BatchSize            int      `json:"batch_size"`
+	AutoCalculate        bool     `json:"auto_calculate"`
+	Algorithms           []string `json:"algorithms"`
+	SolverTimeoutSeconds int      `json:"solver_timeout_seconds"` // Deadline of one solver run in background jobs, 0 for none
+	BoyarDepthLimit      int      `json:"boyar_depth_limit"`      // Boyar SLP depth bound of runs that do not request one
 }
 
 // ServerConfig holds server configuration
@@ -53,12 +60,14 @@ type ServerConfig struct {
 // Default configuration
 var defaultConfig = Config{
 	Database: DatabaseConfig{
+		Driver:   DriverPostgres,
 		Host:     "localhost",
 		Port:     5432,
 		User:     "postgres",
 		Password: "password",
 		DBName:   "xor_optimization",
 		SSLMode:  "disable",
+		Path:     defaultSQLitePath,
 	},
 	Import: ImportConfig{
 		Enabled:         true,
@@ -70,7 +79,9 @@ var defaultConfig = Config{
 		SkipExisting:    true,
 		BatchSize:       10,
 		AutoCalculate:   true,
-		Algorithms:      []string{"boyar", "paar", "slp"},
+		// Algorithms defaults to the registered default solvers, see applyAlgorithmDefaults
+		SolverTimeoutSeconds: 600,
+		BoyarDepthLimit:      defaultBoyarDepthLimit,
 	},
 	Server: ServerConfig{
 		Port:         ":3000",
@@ -88,6 +99,7 @@ func LoadConfig(configPath string) (*Config, error) {
 	// Check if config file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		log.Printf("Config dosyası bulunamadı (%s), varsayılan config oluşturuluyor...", configPath)
+		config.Import.Algorithms = DefaultAlgorithms()
 		
 		// Create config file with default values
 		if err := SaveConfig(&config, configPath); err != nil {
@@ -109,10 +121,32 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("config dosyası parse edilemedi: %v", err)
 	}
 
+	if err := applyAlgorithmDefaults(&config); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Config dosyası yüklendi: %s", configPath)
 	return &config, nil
 }
 
+// applyAlgorithmDefaults fills import algorithms from the solver registry,
+// rejects algorithm names that are not registered and checks the Boyar depth limit
+func applyAlgorithmDefaults(config *Config) error {
+	algorithms, err := normalizeAlgorithms(config.Import.Algorithms)
+	if err != nil {
+		return fmt.Errorf("config import.algorithms geçersiz: %v", err)
+	}
+	config.Import.Algorithms = algorithms
+
+	if config.Import.BoyarDepthLimit == 0 {
+		config.Import.BoyarDepthLimit = defaultBoyarDepthLimit
+	}
+	if config.Import.BoyarDepthLimit < 1 || config.Import.BoyarDepthLimit > maxBoyarDepthLimit {
+		return fmt.Errorf("config import.boyar_depth_limit 1 ile %d arasında olmalı: %d", maxBoyarDepthLimit, config.Import.BoyarDepthLimit)
+	}
+	return nil
+}
+
 // SaveConfig saves configuration to file
 func SaveConfig(config *Config, configPath string) error {
 	// Create directory if it doesn't exist
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"crypto/md5"
 	"database/sql"
 	"encoding/hex"
@@ -10,39 +11,163 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 )
 
 // MatrixRecord represents a matrix record in the database
 type MatrixRecord struct {
-	ID                  int       `json:"id"`
-	Title               string    `json:"title"`
-	Group               string    `json:"group"`
-	MatrixBinary        string    `json:"matrix_binary"`
-	MatrixHex           string    `json:"matrix_hex"`
-	HamXorCount         int       `json:"ham_xor_count"`
-	BoyarXorCount       *int      `json:"boyar_xor_count,omitempty"`
-	BoyarDepth          *int      `json:"boyar_depth,omitempty"`
-	BoyarProgram        *string   `json:"boyar_program,omitempty"`
-	PaarXorCount        *int      `json:"paar_xor_count,omitempty"`
-	PaarProgram         *string   `json:"paar_program,omitempty"`
-	SlpXorCount         *int      `json:"slp_xor_count,omitempty"`
-	SlpProgram          *string   `json:"slp_program,omitempty"`
-	SmallestXor         *int      `json:"smallest_xor,omitempty"`
-	MatrixHash          string    `json:"matrix_hash"`
-	InverseMatrixID     *int      `json:"inverse_matrix_id,omitempty"`
-	InverseMatrixHash   *string   `json:"inverse_matrix_hash,omitempty"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
-}
-
-// Database represents the PostgreSQL database
+	ID                 int                      `json:"id"`
+	Title              string                   `json:"title"`
+	Group              string                   `json:"group"`
+	MatrixBinary       string                   `json:"matrix_binary"`
+	MatrixHex          string                   `json:"matrix_hex"`
+	HamXorCount        int                      `json:"ham_xor_count"`
+	Results            map[string]*SolverResult `json:"-"`                      // Keyed by solver name, see MarshalJSON
+	SmallestXor        *int                     `json:"smallest_xor,omitempty"` // XOR count of the best run
+	BestRunID          *int                     `json:"best_run_id,omitempty"`  // algorithm_runs row with the fewest XORs of any algorithm
+	MatrixHash         string                   `json:"matrix_hash"`
+	InverseMatrixID    *int                     `json:"inverse_matrix_id,omitempty"`
+	InverseMatrixHash  *string                  `json:"inverse_matrix_hash,omitempty"`
+	CanonicalHash      *string                  `json:"canonical_hash,omitempty"`   // Hash of the canonical form under row and column permutations
+	EquivalentCount    *int                     `json:"equivalent_count,omitempty"` // Stored matrices with this canonical hash, set by grouped listings
+	OptimalXor         *int                     `json:"optimal_xor,omitempty"`      // Minimum XOR count, set only once the exact search proved it
+	XorLowerBound      *int                     `json:"xor_lower_bound,omitempty"`  // Largest lower bound on the XOR count the exact search proved
+	SXorCount          *int                     `json:"sxor_count,omitempty"`       // Best in-place (s-XOR) count, nil for non-square or singular matrices
+	SXorProgram        *InPlaceProgram          `json:"sxor_program,omitempty"`
+	SXorSource         *string                  `json:"sxor_source,omitempty"`         // SourceInPlaceSolver or the solver whose program was converted
+	FieldMatrix        [][]string               `json:"field_matrix,omitempty"`        // Hex elements over GF(2^m), nil for plain binary matrices
+	FieldPolynomial    *string                  `json:"field_polynomial,omitempty"`    // Reduction polynomial, e.g. "x^4+x+1"
+	FieldDegree        *int                     `json:"field_degree,omitempty"`        // Element size m in bits
+	DifferentialBranch *int                     `json:"differential_branch,omitempty"` // Properties from AnalyzeMatrix, nil until analyzed or if over budget
+	LinearBranch       *int                     `json:"linear_branch,omitempty"`
+	IsMDS              *bool                    `json:"is_mds,omitempty"`
+	IsNearMDS          *bool                    `json:"is_near_mds,omitempty"`
+	IsInvolutory       *bool                    `json:"is_involutory,omitempty"`
+	IsSemiInvolutory   *bool                    `json:"is_semi_involutory,omitempty"`
+	IsOrthogonal       *bool                    `json:"is_orthogonal,omitempty"`
+	IsCirculant        *bool                    `json:"is_circulant,omitempty"`
+	AnalyzedAt         *time.Time               `json:"analyzed_at,omitempty"`
+	CreatedAt          time.Time                `json:"created_at"`
+	UpdatedAt          time.Time                `json:"updated_at"`
+	DepthResults       []*DepthResult           `json:"depth_results,omitempty"` // Best result per depth limit, loaded by GetMatrixByID
+}
+
+// DepthResult is the best run a solver with depth made on a matrix under one
+// depth limit and transpose option
+type DepthResult struct {
+	RunID      int       `json:"run_id"`
+	Algorithm  string    `json:"algorithm"`
+	DepthLimit int       `json:"depth_limit"`
+	Transpose  bool      `json:"transpose,omitempty"`
+	XorCount   int       `json:"xor_count"`
+	Depth      int       `json:"depth"`
+	Program    *Program  `json:"program,omitempty"`
+	Seed       *int64    `json:"seed,omitempty"`
+	Status     string    `json:"status"`
+	UpdatedAt  time.Time `json:"updated_at"` // Creation time of the run
+}
+
+// SolverResult is the stored result of one persisted solver: its best run
+// under the depth limit and transpose option it was last run with
+type SolverResult struct {
+	RunID      int      `json:"run_id"`
+	XorCount   *int     `json:"xor_count,omitempty"`
+	Depth      *int     `json:"depth,omitempty"`
+	Program    *Program `json:"program,omitempty"`
+	Seed       *int64   `json:"seed,omitempty"`        // Randomized solvers only
+	Status     *string  `json:"status,omitempty"`      // StatusCompleted, StatusTimedOut or StatusAborted
+	Origin     *string  `json:"origin,omitempty"`      // OriginDirect or OriginTranspose
+	Verified   *bool    `json:"verified,omitempty"`    // Verifier verdict, nil until verified
+	DepthLimit int      `json:"depth_limit,omitempty"` // Depth limit of the run, 0 if unknown or not applicable
+	Transpose  bool     `json:"transpose,omitempty"`   // Whether the run used the transpose option
+}
+
+// AlgorithmRun is one solver run on a matrix; algorithm_runs keeps every run
+type AlgorithmRun struct {
+	ID            int          `json:"id"`
+	MatrixID      int          `json:"matrix_id"`
+	Algorithm     string       `json:"algorithm"`
+	Parameters    SolverParams `json:"parameters,omitempty"` // Effective solver parameters
+	DepthLimit    int          `json:"depth_limit,omitempty"`
+	Transpose     bool         `json:"transpose,omitempty"`
+	XorCount      *int         `json:"xor_count,omitempty"` // Size reached for aborted runs
+	Depth         *int         `json:"depth,omitempty"`
+	Program       *Program     `json:"program,omitempty"`
+	DurationMs    *int64       `json:"duration_ms,omitempty"`
+	Seed          *int64       `json:"seed,omitempty"`
+	SolverVersion string       `json:"solver_version"` // SolverInfo.Version, "legacy" for results moved from matrix_records
+	Status        string       `json:"status"`
+	Origin        *string      `json:"origin,omitempty"`
+	Verified      *bool        `json:"verified,omitempty"`
+	Best          bool         `json:"best"` // Whether the run is the solver's result on the matrix
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// Result returns the stored result of the named solver, or nil if there is none
+func (r *MatrixRecord) Result(name string) *SolverResult {
+	return r.Results[name]
+}
+
+// StoredProgram returns the stored program of the named solver, or nil if there is none
+func (r *MatrixRecord) StoredProgram(name string) *Program {
+	if result := r.Results[name]; result != nil {
+		return result.Program
+	}
+	return nil
+}
+
+// MarshalJSON flattens Results into <solver>_xor_count, <solver>_depth,
+// <solver>_program, ... keys next to the other fields of the record
+func (r MatrixRecord) MarshalJSON() ([]byte, error) {
+	type plainRecord MatrixRecord
+	data, err := json.Marshal(plainRecord(r))
+	if err != nil || len(r.Results) == 0 {
+		return data, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, result := range r.Results {
+		if result == nil {
+			continue
+		}
+		resultData, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		var resultFields map[string]json.RawMessage
+		if err := json.Unmarshal(resultData, &resultFields); err != nil {
+			return nil, err
+		}
+		for key, value := range resultFields {
+			fields[name+"_"+key] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// Storage drivers; DriverSQLite is the pure-Go modernc.org/sqlite driver and
+// DriverMemory the MemoryStore, which keeps nothing across restarts
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// Database is the SQL MatrixStore, on PostgreSQL or on an embedded SQLite
+// file. Both run the same queries; the schema comes from the migrations of
+// the driver.
 type Database struct {
-	db *sql.DB
+	db     *sql.DB
+	driver string
 }
 
 // NewDatabase creates a new database connection
@@ -66,10 +191,28 @@ func NewDatabase(connectionString string) (*Database, error) {
 	log.Printf("🔗 [DB] Database connection established with optimized pool settings for 4-core 16GB server")
 	log.Printf("🔗 [DB] Max open connections: 100, Max idle connections: 40")
 
-	database := &Database{db: db}
+	database := &Database{db: db, driver: DriverPostgres}
 	return database, nil
 }
 
+// NewSQLiteDatabase opens (or creates) the SQLite database file at path. WAL
+// lets readers run next to the single writer, and writers wait for the lock
+// instead of failing.
+func NewSQLiteDatabase(path string) (*Database, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_txlock=immediate"
+	db, err := sql.Open(DriverSQLite, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	log.Printf("🔗 [DB] SQLite veritabanı açıldı: %s", path)
+	return &Database{db: db, driver: DriverSQLite}, nil
+}
+
 // matrixToHex converts a binary matrix to hex representation
 func matrixToHex(matrix Matrix) string {
 	var hexStrings []string
@@ -124,199 +267,1184 @@ func calculateHammingXOR(matrix Matrix) int {
 	return count
 }
 
-// SaveMatrix saves a matrix to the database
-func (d *Database) SaveMatrix(title string, matrix Matrix, group string) (*MatrixRecord, error) {
+// SaveMatrix saves a matrix to store; a matrix already stored under its hash
+// is returned as is
+func SaveMatrix(store MatrixStore, title string, matrix Matrix, group string) (*MatrixRecord, error) {
 	matrixHash := calculateMatrixHash(matrix)
 	
 	// Check if matrix already exists
-	existing, err := d.GetMatrixByHash(matrixHash)
+	existing, err := store.GetMatrixByHash(matrixHash)
 	if err == nil && existing != nil {
 		return existing, nil
 	}
 
-	matrixBinary := matrixToBinary(matrix)
-	matrixHex := matrixToHex(matrix)
-	hamXorCount := calculateHammingXOR(matrix)
+	canonicalHash, err := calculateCanonicalHash(matrix)
+	if err != nil {
+		return nil, err
+	}
+	id, err := store.InsertMatrix(&MatrixRecord{
+		Title:         title,
+		Group:         group,
+		MatrixBinary:  matrixToBinary(matrix),
+		MatrixHex:     matrixToHex(matrix),
+		HamXorCount:   calculateHammingXOR(matrix),
+		MatrixHash:    matrixHash,
+		CanonicalHash: &canonicalHash,
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	if elements, err := binaryField.ParseMatrix(matrix); err == nil {
+		analyzeNewMatrix(store, id, binaryField, elements)
+	}
+
+	return store.GetMatrixByID(id)
+}
+
+// InsertMatrix stores a new matrix from the title, group, representation and
+// hash fields of record and returns its ID
+func (d *Database) InsertMatrix(record *MatrixRecord) (int, error) {
 	query := `
-	INSERT INTO matrix_records (title, group_name, matrix_binary, matrix_hex, ham_xor_count, matrix_hash)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	INSERT INTO matrix_records (title, group_name, matrix_binary, matrix_hex, ham_xor_count, matrix_hash, canonical_hash)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
 	RETURNING id
 	`
 
 	var id int
-	err = d.db.QueryRow(query, title, group, matrixBinary, matrixHex, hamXorCount, matrixHash).Scan(&id)
+	err := d.db.QueryRow(query, record.Title, record.Group, record.MatrixBinary, record.MatrixHex, record.HamXorCount,
+		record.MatrixHash, record.CanonicalHash).Scan(&id)
+	return id, err
+}
+
+// SaveFieldMatrix saves a matrix over GF(2^m) as its binary expansion together
+// with the field matrix, the polynomial and the element size. An existing
+// binary matrix without a field representation gets this one.
+func SaveFieldMatrix(store MatrixStore, title string, field *GF2m, elements FieldMatrix, group string) (*MatrixRecord, error) {
+	record, err := SaveMatrix(store, title, field.Expand(elements), group)
 	if err != nil {
 		return nil, err
 	}
+	if err := setFieldRepresentation(store, record.ID, field, elements); err != nil {
+		return nil, err
+	}
+	return store.GetMatrixByID(record.ID)
+}
 
-	return d.GetMatrixByID(id)
+// setFieldRepresentation stores the GF(2^m) form of a matrix unless it already
+// has one, and analyzes the matrix again over the field
+func setFieldRepresentation(store MatrixStore, id int, field *GF2m, elements FieldMatrix) error {
+	stored, err := store.SetFieldRepresentation(id, field, elements)
+	if err != nil {
+		return err
+	}
+	if stored {
+		analyzeNewMatrix(store, id, field, elements)
+	}
+	return nil
 }
 
-// UpdateMatrixResults updates the algorithm results for a matrix
-func (d *Database) UpdateMatrixResults(id int, boyarResult, paarResult, slpResult *AlgResult) error {
-	// Calculate smallest XOR value
-	var smallestXor *int
-	var xorValues []int
-	
-	if boyarResult != nil {
-		xorValues = append(xorValues, boyarResult.XorCount)
+// SetFieldRepresentation stores the GF(2^m) form of a matrix and reports
+// whether it did; a matrix that already has one keeps it
+func (d *Database) SetFieldRepresentation(id int, field *GF2m, elements FieldMatrix) (bool, error) {
+	query := `
+	UPDATE matrix_records
+	SET field_matrix = $1, field_polynomial = $2, field_degree = $3, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $4 AND field_matrix IS NULL
+	`
+	result, err := d.db.Exec(query, field.Format(elements), field.Polynomial(), field.M, id)
+	if err != nil {
+		return false, err
 	}
-	if paarResult != nil {
-		xorValues = append(xorValues, paarResult.XorCount)
+	updated, _ := result.RowsAffected()
+	return updated > 0, nil
+}
+
+// analyzeNewMatrix analyzes a matrix that was just stored; failures are only
+// logged since the analysis can be repeated through the API
+func analyzeNewMatrix(store MatrixStore, id int, field *GF2m, elements FieldMatrix) {
+	if err := store.SaveAnalysis(id, AnalyzeMatrix(field, elements)); err != nil {
+		log.Printf("⚠️  [ANALYSIS] Matris %d analizi kaydedilemedi: %v", id, err)
 	}
-	if slpResult != nil {
-		xorValues = append(xorValues, slpResult.XorCount)
+}
+
+// SaveAnalysis stores the analysis flags of a matrix
+func (d *Database) SaveAnalysis(id int, analysis *MatrixAnalysis) error {
+	query := `
+	UPDATE matrix_records
+	SET differential_branch = $1, linear_branch = $2, is_mds = $3, is_near_mds = $4, is_involutory = $5,
+	    is_semi_involutory = $6, is_orthogonal = $7, is_circulant = $8, analyzed_at = CURRENT_TIMESTAMP
+	WHERE id = $9
+	`
+	_, err := d.db.Exec(query, analysis.DifferentialBranch, analysis.LinearBranch, analysis.MDS, analysis.NearMDS,
+		analysis.Involutory, analysis.SemiInvolutory, analysis.Orthogonal, analysis.Circulant, id)
+	return err
+}
+
+// analysisFlagColumns are the boolean analysis columns GetMatrices can filter on
+var analysisFlagColumns = []string{"is_mds", "is_near_mds", "is_involutory", "is_semi_involutory", "is_orthogonal", "is_circulant"}
+
+// analysisColumns holds the nullable analysis columns of a scanned row
+type analysisColumns struct {
+	differentialBranch, linearBranch         sql.NullInt64
+	mds, nearMDS, involutory, semiInvolutory sql.NullBool
+	orthogonal, circulant                    sql.NullBool
+	analyzedAt                               sql.NullTime
+}
+
+// apply copies the valid analysis columns into record
+func (a *analysisColumns) apply(record *MatrixRecord) {
+	if a.differentialBranch.Valid {
+		val := int(a.differentialBranch.Int64)
+		record.DifferentialBranch = &val
+	}
+	if a.linearBranch.Valid {
+		val := int(a.linearBranch.Int64)
+		record.LinearBranch = &val
+	}
+	for _, flag := range []struct {
+		value  sql.NullBool
+		target **bool
+	}{
+		{a.mds, &record.IsMDS},
+		{a.nearMDS, &record.IsNearMDS},
+		{a.involutory, &record.IsInvolutory},
+		{a.semiInvolutory, &record.IsSemiInvolutory},
+		{a.orthogonal, &record.IsOrthogonal},
+		{a.circulant, &record.IsCirculant},
+	} {
+		if flag.value.Valid {
+			val := flag.value.Bool
+			*flag.target = &val
+		}
 	}
-	
-	if len(xorValues) > 0 {
-		min := xorValues[0]
-		for _, val := range xorValues {
-			if val < min {
-				min = val
+	if a.analyzedAt.Valid {
+		record.AnalyzedAt = &a.analyzedAt.Time
+	}
+}
+
+// UpdateMatrixResults records the algorithm results for a matrix. results is
+// keyed by solver name; every result is appended to algorithm_runs and
+// becomes a candidate for the solver's result (see SaveAlgorithmRun).
+// Algorithms missing from results keep their stored results.
+func UpdateMatrixResults(store MatrixStore, id int, results map[string]*AlgResult) error {
+	names := make([]string, 0, len(results))
+	for name, result := range results {
+		if result != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := store.SaveAlgorithmRun(id, name, results[name], true); err != nil {
+			return err
+		}
+	}
+
+	if result := results["exact"]; result != nil && result.Status != StatusAborted {
+		if err := store.SaveOptimalResult(id, result); err != nil {
+			return err
+		}
+	}
+
+	// The s-XOR count depends on the stored programs; a failure is only logged
+	ctx, cancel := context.WithTimeout(context.Background(), inPlaceConvertBudget)
+	defer cancel()
+	if _, err := UpdateInPlaceResult(ctx, store, id); err != nil {
+		log.Printf("⚠️  [SXOR] Matris %d için s-XOR hesaplanamadı: %v", id, err)
+	}
+	return nil
+}
+
+// runKey is the part of a run's parameters its result is compared under:
+// runs with another depth limit or transpose option solve a different problem
+type runKey struct {
+	DepthLimit int
+	Transpose  bool
+}
+
+// resultRunKey returns the runKey of result
+func resultRunKey(result *AlgResult) runKey {
+	return runKey{DepthLimit: result.DepthLimit, Transpose: result.Parameters.Bool("transpose", false)}
+}
+
+// bestRunOrder orders runs best first: fewest XORs, then least depth, the
+// latest of equal runs; aborted runs only when there is nothing else
+const bestRunOrder = "status = 'aborted', xor_count IS NULL, xor_count, depth, id DESC"
+
+// SaveAlgorithmRun appends a solver result to algorithm_runs and returns the
+// run ID. Aborted runs keep the size they reached but no program.
+//
+// With asResult the solver's result on the matrix becomes its best run with
+// the depth limit and transpose option of this one. Otherwise (depth sweeps)
+// the result only changes if it already had those parameters, so a sweep
+// never replaces the result of a regular run. best_run_id is refreshed in
+// the same transaction.
+func (d *Database) SaveAlgorithmRun(matrixID int, algorithm string, result *AlgResult, asResult bool) (int, error) {
+	info, ok := GetSolverInfo(algorithm)
+	if !ok {
+		return 0, fmt.Errorf("desteklenmeyen algoritma: %s", algorithm)
+	}
+
+	parameters := result.Parameters
+	if parameters == nil {
+		parameters = SolverParams{}
+	}
+	parametersJson, err := json.Marshal(parameters)
+	if err != nil {
+		return 0, err
+	}
+	var depth *int
+	var program, origin *string
+	if result.Status != StatusAborted {
+		if info.HasDepth {
+			depth = &result.Depth
+		}
+		programJson, err := json.Marshal(result.Program)
+		if err != nil {
+			return 0, err
+		}
+		programStr := string(programJson)
+		program = &programStr
+	}
+	if result.Origin != "" {
+		origin = &result.Origin
+	}
+	key := resultRunKey(result)
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Runs of one matrix are selected one at a time; SQLite transactions
+	// already hold the write lock (_txlock=immediate)
+	if d.driver == DriverPostgres {
+		if _, err := tx.Exec("SELECT id FROM matrix_records WHERE id = $1 FOR UPDATE", matrixID); err != nil {
+			return 0, err
+		}
+	}
+
+	var runID int
+	err = tx.QueryRow(`
+	INSERT INTO algorithm_runs (matrix_id, algorithm, parameters, depth_limit, transpose, xor_count, depth, program,
+	                            duration_ms, seed, solver_version, status, origin)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	RETURNING id
+	`, matrixID, info.Name, string(parametersJson), key.DepthLimit, key.Transpose, result.XorCount, depth, program,
+		result.DurationMs, result.Seed, info.Version, result.Status, origin).Scan(&runID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !asResult {
+		var current runKey
+		err := tx.QueryRow(`
+		SELECT depth_limit, transpose FROM algorithm_runs
+		WHERE matrix_id = $1 AND algorithm = $2 AND best
+		`, matrixID, info.Name).Scan(&current.DepthLimit, &current.Transpose)
+		if err != nil && err != sql.ErrNoRows {
+			return 0, err
+		}
+		asResult = err == sql.ErrNoRows || current == key
+	}
+	if asResult {
+		if err := selectResultRun(tx, matrixID, info.Name, key); err != nil {
+			return 0, err
+		}
+	}
+	if err := refreshBestRun(tx, matrixID); err != nil {
+		return 0, err
+	}
+	return runID, tx.Commit()
+}
+
+// selectResultRun marks the best run of algorithm with the given key as the
+// solver's result on the matrix and unmarks its other runs
+func selectResultRun(tx *sql.Tx, matrixID int, algorithm string, key runKey) error {
+	_, err := tx.Exec(`
+	UPDATE algorithm_runs
+	SET best = (id = (
+		SELECT id FROM algorithm_runs
+		WHERE matrix_id = $1 AND algorithm = $2 AND depth_limit = $3 AND transpose = $4
+		ORDER BY `+bestRunOrder+`
+		LIMIT 1
+	))
+	WHERE matrix_id = $1 AND algorithm = $2
+	`, matrixID, algorithm, key.DepthLimit, key.Transpose)
+	return err
+}
+
+// refreshBestRun points best_run_id at the run with the fewest XORs of any
+// algorithm and parameters; the earliest of equal runs keeps the pointer
+func refreshBestRun(tx *sql.Tx, matrixID int) error {
+	_, err := tx.Exec(`
+	UPDATE matrix_records
+	SET best_run_id = (
+		SELECT id FROM algorithm_runs
+		WHERE matrix_id = $1 AND status <> 'aborted' AND xor_count IS NOT NULL
+		ORDER BY xor_count, id
+		LIMIT 1
+	), updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	`, matrixID)
+	return err
+}
+
+// GetAlgorithmRuns returns the runs of record, newest first, optionally only
+// those of one algorithm
+func (d *Database) GetAlgorithmRuns(record *MatrixRecord, algorithm string) ([]*AlgorithmRun, error) {
+	query := `
+	SELECT id, matrix_id, algorithm, parameters, depth_limit, transpose, xor_count, depth, program,
+	       duration_ms, seed, solver_version, status, origin, verified, best, created_at
+	FROM algorithm_runs
+	WHERE matrix_id = $1 AND ($2 = '' OR algorithm = $2)
+	ORDER BY id DESC
+	`
+	rows, err := d.db.Query(query, record.ID, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []*AlgorithmRun{}
+	for rows.Next() {
+		var run AlgorithmRun
+		var parameters, program, origin sql.NullString
+		var xorCount, depth, durationMs, seed sql.NullInt64
+		var verified sql.NullBool
+		if err := rows.Scan(&run.ID, &run.MatrixID, &run.Algorithm, &parameters, &run.DepthLimit, &run.Transpose,
+			&xorCount, &depth, &program, &durationMs, &seed, &run.SolverVersion, &run.Status, &origin, &verified,
+			&run.Best, &run.CreatedAt); err != nil {
+			return nil, err
+		}
+		if parameters.Valid {
+			if err := json.Unmarshal([]byte(parameters.String), &run.Parameters); err != nil {
+				log.Printf("⚠️ Matris %d çalıştırma %d parametreleri okunamadı: %v", record.ID, run.ID, err)
 			}
 		}
-		smallestXor = &min
+		if xorCount.Valid {
+			val := int(xorCount.Int64)
+			run.XorCount = &val
+		}
+		if depth.Valid {
+			val := int(depth.Int64)
+			run.Depth = &val
+		}
+		run.Program = scanProgram(program, record, fmt.Sprintf("%s (çalıştırma %d)", run.Algorithm, run.ID))
+		if durationMs.Valid {
+			run.DurationMs = &durationMs.Int64
+		}
+		if seed.Valid {
+			run.Seed = &seed.Int64
+		}
+		if origin.Valid {
+			run.Origin = &origin.String
+		}
+		if verified.Valid {
+			run.Verified = &verified.Bool
+		}
+		runs = append(runs, &run)
+	}
+	return runs, rows.Err()
+}
+
+// SaveOptimalResult stores what an exact search proved: the lower bound only
+// grows, and optimal_xor is set only once the bound meets the XOR count. The
+// program of an interrupted search is an upper bound and stays with the
+// solver results.
+func (d *Database) SaveOptimalResult(matrixID int, result *AlgResult) error {
+	var optimal *int
+	if result.Status == StatusCompleted && result.LowerBound == result.XorCount {
+		optimal = &result.XorCount
+	}
+	_, err := d.db.Exec(`
+	UPDATE matrix_records
+	SET xor_lower_bound = CASE WHEN xor_lower_bound IS NULL OR xor_lower_bound < $1 THEN $1 ELSE xor_lower_bound END,
+	    optimal_xor = COALESCE($2, optimal_xor)
+	WHERE id = $3
+	`, result.LowerBound, optimal, matrixID)
+	return err
+}
+
+// UpdateInPlaceResult recomputes the best s-XOR program of a matrix from its
+// stored programs (BestInPlace) and stores it. The stored s-XOR program is
+// kept if it is still shorter, so a conversion cut short by ctx never makes
+// the count worse. Non-square and singular matrices have no s-XOR count;
+// their columns are cleared.
+func UpdateInPlaceResult(ctx context.Context, store MatrixStore, id int) (*MatrixRecord, error) {
+	record, err := store.GetMatrixByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("matris bulunamadı: %d", id)
+	}
+	matrix, err := parseMatrixFromBinary(record.MatrixBinary)
+	if err != nil {
+		return nil, fmt.Errorf("matris parse edilemedi: %v", err)
+	}
+
+	stored, storedSource := record.SXorProgram, record.SXorSource
+	record.SXorCount, record.SXorProgram, record.SXorSource = nil, nil, nil
+	program, source, err := BestInPlace(ctx, matrix, record)
+	if err != nil {
+		log.Printf("⚠️  [SXOR] Matris %d için s-XOR tanımsız: %v", id, err)
+	} else {
+		if stored != nil && storedSource != nil && stored.XorCount() < program.XorCount() {
+			program, source = stored, *storedSource
+		}
+		count := program.XorCount()
+		record.SXorCount, record.SXorProgram, record.SXorSource = &count, program, &source
 	}
 
+	if err := store.SaveInPlaceResult(id, program, source); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// SaveInPlaceResult stores the best s-XOR program of a matrix and the source
+// it came from; a nil program clears the s-XOR columns
+func (d *Database) SaveInPlaceResult(id int, program *InPlaceProgram, source string) error {
+	var count *int
+	var programStr, sourceStr *string
+	if program != nil {
+		programJson, err := json.Marshal(program)
+		if err != nil {
+			return err
+		}
+		xorCount, str := program.XorCount(), string(programJson)
+		count, programStr, sourceStr = &xorCount, &str, &source
+	}
+
+	_, err := d.db.Exec(`
+	UPDATE matrix_records
+	SET sxor_count = $1, sxor_program = $2, sxor_source = $3
+	WHERE id = $4
+	`, count, programStr, sourceStr, id)
+	return err
+}
+
+// GetDepthResults returns the best run of record per solver with depth, depth
+// limit and transpose option, ordered by algorithm and depth limit
+func (d *Database) GetDepthResults(record *MatrixRecord) ([]*DepthResult, error) {
 	query := `
-	UPDATE matrix_records 
-	SET boyar_xor_count = $1, boyar_depth = $2, boyar_program = $3,
-	    paar_xor_count = $4, paar_program = $5,
-	    slp_xor_count = $6, slp_program = $7,
-	    smallest_xor = $8,
-	    updated_at = CURRENT_TIMESTAMP
-	WHERE id = $9
+	SELECT id, algorithm, depth_limit, transpose, xor_count, depth, program, seed, status, created_at
+	FROM algorithm_runs
+	WHERE matrix_id = $1 AND status <> 'aborted' AND xor_count IS NOT NULL AND depth IS NOT NULL
+	ORDER BY algorithm, depth_limit, transpose, ` + bestRunOrder
+	rows, err := d.db.Query(query, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*DepthResult
+	for rows.Next() {
+		var result DepthResult
+		var program sql.NullString
+		var seed sql.NullInt64
+		if err := rows.Scan(&result.RunID, &result.Algorithm, &result.DepthLimit, &result.Transpose, &result.XorCount,
+			&result.Depth, &program, &seed, &result.Status, &result.UpdatedAt); err != nil {
+			return nil, err
+		}
+		// Rows of one key come best first
+		if last := len(results) - 1; last >= 0 && results[last].Algorithm == result.Algorithm &&
+			results[last].DepthLimit == result.DepthLimit && results[last].Transpose == result.Transpose {
+			continue
+		}
+		result.Program = scanProgram(program, record, fmt.Sprintf("%s (derinlik sınırı %d)", result.Algorithm, result.DepthLimit))
+		if seed.Valid {
+			result.Seed = &seed.Int64
+		}
+		results = append(results, &result)
+	}
+	return results, rows.Err()
+}
+
+// RefreshParetoFront recomputes the Pareto front of record from its stored
+// depth sweep results and replaces the stored front
+func RefreshParetoFront(store MatrixStore, record *MatrixRecord) error {
+	results, err := store.GetDepthResults(record)
+	if err != nil {
+		return err
+	}
+	var sweep []*DepthResult
+	for _, result := range results {
+		if result.Algorithm == paretoAlgorithm {
+			sweep = append(sweep, result)
+		}
+	}
+	return store.ReplaceParetoFront(record.ID, paretoFront(sweep))
+}
+
+// ReplaceParetoFront replaces the stored Pareto front of a matrix with points
+func (d *Database) ReplaceParetoFront(matrixID int, points []*ParetoPoint) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM pareto_points WHERE matrix_id = $1", matrixID); err != nil {
+		return err
+	}
+	for _, point := range points {
+		programJson, err := json.Marshal(point.Program)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+		INSERT INTO pareto_points (matrix_id, depth, xor_count, depth_limit, program, seed, status, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, matrixID, point.Depth, point.XorCount, point.DepthLimit, string(programJson), point.Seed, point.Status, point.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetParetoFront returns the stored Pareto front of record ordered by depth
+func (d *Database) GetParetoFront(record *MatrixRecord) ([]*ParetoPoint, error) {
+	query := `
+	SELECT depth, xor_count, depth_limit, program, seed, status, updated_at
+	FROM pareto_points WHERE matrix_id = $1
+	ORDER BY depth
 	`
+	rows, err := d.db.Query(query, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	var boyarXor, boyarDepth *int
-	var boyarProgram *string
-	if boyarResult != nil {
-		boyarXor = &boyarResult.XorCount
-		boyarDepth = &boyarResult.Depth
-		programJson, _ := json.Marshal(boyarResult.Program)
-		programStr := string(programJson)
-		boyarProgram = &programStr
+	points := []*ParetoPoint{}
+	for rows.Next() {
+		var point ParetoPoint
+		var program sql.NullString
+		var seed sql.NullInt64
+		var status sql.NullString
+		if err := rows.Scan(&point.Depth, &point.XorCount, &point.DepthLimit, &program, &seed, &status, &point.UpdatedAt); err != nil {
+			return nil, err
+		}
+		point.Program = scanProgram(program, record, fmt.Sprintf("pareto (derinlik %d)", point.Depth))
+		if seed.Valid {
+			point.Seed = &seed.Int64
+		}
+		if status.Valid {
+			point.Status = status.String
+		}
+		points = append(points, &point)
 	}
+	return points, rows.Err()
+}
 
-	var paarXor *int
-	var paarProgram *string
-	if paarResult != nil {
-		paarXor = &paarResult.XorCount
-		programJson, _ := json.Marshal(paarResult.Program)
-		programStr := string(programJson)
-		paarProgram = &programStr
+// generatorJobColumns is the column list scanned by scanGeneratorJob
+const generatorJobColumns = `id, family, field_polynomial, field_degree, dimension, algorithms, max_results,
+	       next_candidate, examined, candidates_total, found, inserted, duplicates, status, error, created_at, updated_at`
+
+// CreateGeneratorJob stores a new generator job and sets its ID
+func (d *Database) CreateGeneratorJob(job *GeneratorJob) error {
+	algorithmsJson, err := json.Marshal(job.Algorithms)
+	if err != nil {
+		return err
+	}
+	cursorJson, err := json.Marshal(job.Cursor)
+	if err != nil {
+		return err
 	}
 
-	var slpXor *int
-	var slpProgram *string
-	if slpResult != nil {
-		slpXor = &slpResult.XorCount
-		programJson, _ := json.Marshal(slpResult.Program)
-		programStr := string(programJson)
-		slpProgram = &programStr
+	query := `
+	INSERT INTO generator_jobs (family, field_polynomial, field_degree, dimension, algorithms, max_results,
+	                            next_candidate, candidates_total, status)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING id, created_at, updated_at
+	`
+	return d.db.QueryRow(query, job.Family, job.Polynomial, job.FieldDegree, job.Dimension, string(algorithmsJson),
+		job.MaxResults, string(cursorJson), job.CandidatesTotal, job.Status).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+}
+
+// SaveGeneratorProgress stores the cursor, counters, limit and status of a job
+func (d *Database) SaveGeneratorProgress(job *GeneratorJob) error {
+	var cursor interface{}
+	if job.Cursor != nil {
+		cursorJson, err := json.Marshal(job.Cursor)
+		if err != nil {
+			return err
+		}
+		cursor = string(cursorJson)
 	}
 
-	_, err := d.db.Exec(query, boyarXor, boyarDepth, boyarProgram, paarXor, paarProgram, slpXor, slpProgram, smallestXor, id)
+	query := `
+	UPDATE generator_jobs
+	SET next_candidate = $1, examined = $2, found = $3, inserted = $4, duplicates = $5,
+	    max_results = $6, status = $7, error = $8, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $9
+	`
+	_, err := d.db.Exec(query, cursor, job.Examined, job.Found, job.Inserted, job.Duplicates,
+		job.MaxResults, job.Status, job.Error, job.ID)
 	return err
 }
 
-// GetMatrixByID retrieves a matrix by its ID
-func (d *Database) GetMatrixByID(id int) (*MatrixRecord, error) {
+// GetGeneratorJob retrieves a generator job by ID, or nil if it does not exist
+func (d *Database) GetGeneratorJob(id int) (*GeneratorJob, error) {
+	row := d.db.QueryRow("SELECT "+generatorJobColumns+" FROM generator_jobs WHERE id = $1", id)
+	job, err := scanGeneratorJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// GetGeneratorJobs returns the generator jobs with the given status, or all of
+// them if status is empty, newest first
+func (d *Database) GetGeneratorJobs(status string) ([]*GeneratorJob, error) {
+	query := "SELECT " + generatorJobColumns + " FROM generator_jobs WHERE $1 = '' OR status = $1 ORDER BY id DESC"
+	rows, err := d.db.Query(query, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*GeneratorJob{}
+	for rows.Next() {
+		job, err := scanGeneratorJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// scanGeneratorJob scans a generator_jobs row selected with generatorJobColumns
+func scanGeneratorJob(scanner interface{ Scan(...interface{}) error }) (*GeneratorJob, error) {
+	var job GeneratorJob
+	var algorithms, cursor, jobError sql.NullString
+	err := scanner.Scan(&job.ID, &job.Family, &job.Polynomial, &job.FieldDegree, &job.Dimension, &algorithms,
+		&job.MaxResults, &cursor, &job.Examined, &job.CandidatesTotal, &job.Found, &job.Inserted, &job.Duplicates,
+		&job.Status, &jobError, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if algorithms.Valid {
+		if err := json.Unmarshal([]byte(algorithms.String), &job.Algorithms); err != nil {
+			return nil, fmt.Errorf("üretici işi %d algoritmaları okunamadı: %v", job.ID, err)
+		}
+	}
+	if cursor.Valid {
+		if err := json.Unmarshal([]byte(cursor.String), &job.Cursor); err != nil {
+			return nil, fmt.Errorf("üretici işi %d konumu okunamadı: %v", job.ID, err)
+		}
+	}
+	if jobError.Valid {
+		job.Error = &jobError.String
+	}
+	return &job, nil
+}
+
+// algorithmJobColumns is the column list scanned by scanAlgorithmJob
+const algorithmJobColumns = `id, matrix_id, source, algorithms, params, reuse_equivalents, timeout_ms, priority, status,
+	       attempts, max_attempts, run_after, lease_owner, lease_expires_at, heartbeat_at, last_error,
+	       created_at, updated_at, finished_at`
+
+// EnqueueAlgorithmJob stores job as queued and sets its ID
+func (d *Database) EnqueueAlgorithmJob(job *AlgorithmJob) error {
+	algorithms, params, err := marshalAlgorithmJob(job)
+	if err != nil {
+		return err
+	}
+
 	query := `
-	SELECT id, title, group_name, matrix_binary, matrix_hex, ham_xor_count, smallest_xor,
-	       boyar_xor_count, boyar_depth, boyar_program,
-	       paar_xor_count, paar_program, slp_xor_count, slp_program,
-	       matrix_hash, inverse_matrix_id, inverse_matrix_hash, created_at, updated_at
-	FROM matrix_records WHERE id = $1
+	INSERT INTO algorithm_jobs (matrix_id, source, algorithms, params, reuse_equivalents, timeout_ms, priority,
+	                            status, max_attempts, run_after)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING id, created_at, updated_at
 	`
-	
-	row := d.db.QueryRow(query, id)
-	return d.scanMatrixRecord(row)
+	job.Status, job.RunAfter = AlgorithmJobQueued, time.Now().UTC()
+	return d.db.QueryRow(query, job.MatrixID, job.Source, algorithms, params, job.ReuseEquivalents,
+		job.Timeout.Milliseconds(), job.Priority, job.Status, job.MaxAttempts, job.RunAfter).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt)
 }
 
-// GetMatrixByHash retrieves a matrix by its hash
-func (d *Database) GetMatrixByHash(hash string) (*MatrixRecord, error) {
+// LeaseAlgorithmJob marks the next due queued job running under owner until
+// the lease expires and returns it, or nil if no job is due. Workers of every
+// instance lease concurrently: PostgreSQL skips the rows other transactions
+// hold, SQLite runs the statement under its write lock.
+func (d *Database) LeaseAlgorithmJob(owner string, lease time.Duration) (*AlgorithmJob, error) {
+	skipLocked := ""
+	if d.driver == DriverPostgres {
+		skipLocked = "FOR UPDATE SKIP LOCKED"
+	}
+	query := fmt.Sprintf(`
+	UPDATE algorithm_jobs
+	SET status = $1, attempts = attempts + 1, lease_owner = $2, lease_expires_at = $3, heartbeat_at = $4,
+	    updated_at = CURRENT_TIMESTAMP
+	WHERE id = (
+		SELECT id FROM algorithm_jobs
+		WHERE status = $5 AND run_after <= $4
+		ORDER BY priority DESC, run_after, id
+		LIMIT 1
+		%s
+	)
+	RETURNING %s
+	`, skipLocked, algorithmJobColumns)
+
+	now := time.Now().UTC()
+	job, err := scanAlgorithmJob(d.db.QueryRow(query, AlgorithmJobRunning, owner, now.Add(lease), now, AlgorithmJobQueued))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// RenewAlgorithmJobLease extends the lease of a running job; false means the
+// lease was lost. The attempt number fences leases of the same owner.
+func (d *Database) RenewAlgorithmJobLease(job *AlgorithmJob, lease time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	expires := now.Add(lease)
 	query := `
-	SELECT id, title, group_name, matrix_binary, matrix_hex, ham_xor_count, smallest_xor,
-	       boyar_xor_count, boyar_depth, boyar_program,
-	       paar_xor_count, paar_program, slp_xor_count, slp_program,
-	       matrix_hash, inverse_matrix_id, inverse_matrix_hash, created_at, updated_at
-	FROM matrix_records WHERE matrix_hash = $1
+	UPDATE algorithm_jobs
+	SET lease_expires_at = $1, heartbeat_at = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $3 AND status = $4 AND lease_owner = $5 AND attempts = $6
 	`
-	
+	result, err := d.db.Exec(query, expires, now, job.ID, AlgorithmJobRunning, job.LeaseOwner, job.Attempts)
+	if err != nil {
+		return false, err
+	}
+	renewed, err := result.RowsAffected()
+	if renewed > 0 {
+		job.LeaseExpiresAt, job.HeartbeatAt = &expires, &now
+	}
+	return renewed > 0, err
+}
+
+// FinishAlgorithmJob releases the lease of a running job and stores its
+// status, algorithms, run_after, last_error and finished_at; false means the
+// lease was lost and nothing was stored
+func (d *Database) FinishAlgorithmJob(job *AlgorithmJob) (bool, error) {
+	algorithms, _, err := marshalAlgorithmJob(job)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+	UPDATE algorithm_jobs
+	SET status = $1, algorithms = $2, run_after = $3, last_error = $4, finished_at = $5,
+	    lease_owner = NULL, lease_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $6 AND status = $7 AND lease_owner = $8 AND attempts = $9
+	`
+	result, err := d.db.Exec(query, job.Status, algorithms, job.RunAfter, job.LastError, job.FinishedAt,
+		job.ID, AlgorithmJobRunning, job.LeaseOwner, job.Attempts)
+	if err != nil {
+		return false, err
+	}
+	finished, err := result.RowsAffected()
+	if finished > 0 {
+		job.LeaseOwner, job.LeaseExpiresAt = nil, nil
+	}
+	return finished > 0, err
+}
+
+// ReleaseAlgorithmJob queues a running job again at once with its
+// algorithms, without counting the interrupted attempt; false means the lease
+// was lost and nothing was stored
+func (d *Database) ReleaseAlgorithmJob(job *AlgorithmJob) (bool, error) {
+	algorithms, _, err := marshalAlgorithmJob(job)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now().UTC()
+	query := `
+	UPDATE algorithm_jobs
+	SET status = $1, algorithms = $2, attempts = attempts - 1, run_after = $3,
+	    lease_owner = NULL, lease_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $4 AND status = $5 AND lease_owner = $6 AND attempts = $7
+	`
+	result, err := d.db.Exec(query, AlgorithmJobQueued, algorithms, now,
+		job.ID, AlgorithmJobRunning, job.LeaseOwner, job.Attempts)
+	if err != nil {
+		return false, err
+	}
+	released, err := result.RowsAffected()
+	if released > 0 {
+		job.Status, job.Attempts, job.RunAfter = AlgorithmJobQueued, job.Attempts-1, now
+		job.LeaseOwner, job.LeaseExpiresAt = nil, nil
+	}
+	return released > 0, err
+}
+
+// RequeueExpiredAlgorithmJobs queues the running jobs whose lease expired
+// again, or dead-letters those that used up their attempts, and returns the
+// number queued again
+func (d *Database) RequeueExpiredAlgorithmJobs() (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	message := "iş kirası doldu, worker yanıt vermedi"
+	_, err = tx.Exec(`
+	UPDATE algorithm_jobs
+	SET status = $1, last_error = $2, finished_at = $3, lease_owner = NULL, lease_expires_at = NULL,
+	    updated_at = CURRENT_TIMESTAMP
+	WHERE status = $4 AND lease_expires_at < $3 AND attempts >= max_attempts
+	`, AlgorithmJobDead, message, now, AlgorithmJobRunning)
+	if err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(`
+	UPDATE algorithm_jobs
+	SET status = $1, last_error = $2, run_after = $3, lease_owner = NULL, lease_expires_at = NULL,
+	    updated_at = CURRENT_TIMESTAMP
+	WHERE status = $4 AND lease_expires_at < $3
+	`, AlgorithmJobQueued, message, now, AlgorithmJobRunning)
+	if err != nil {
+		return 0, err
+	}
+	requeued, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(requeued), tx.Commit()
+}
+
+// RetryAlgorithmJob queues a dead job again with fresh attempts and no error;
+// false means it does not exist or is not dead
+func (d *Database) RetryAlgorithmJob(id int) (bool, error) {
+	query := `
+	UPDATE algorithm_jobs
+	SET status = $1, attempts = 0, run_after = $2, last_error = NULL, finished_at = NULL,
+	    updated_at = CURRENT_TIMESTAMP
+	WHERE id = $3 AND status = $4
+	`
+	result, err := d.db.Exec(query, AlgorithmJobQueued, time.Now().UTC(), id, AlgorithmJobDead)
+	if err != nil {
+		return false, err
+	}
+	retried, err := result.RowsAffected()
+	return retried > 0, err
+}
+
+// GetAlgorithmJob retrieves an algorithm job by ID, or nil if it does not exist
+func (d *Database) GetAlgorithmJob(id int) (*AlgorithmJob, error) {
+	row := d.db.QueryRow("SELECT "+algorithmJobColumns+" FROM algorithm_jobs WHERE id = $1", id)
+	job, err := scanAlgorithmJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// GetAlgorithmJobs returns at most limit algorithm jobs, newest first,
+// filtered by status and matrix unless these are empty or 0
+func (d *Database) GetAlgorithmJobs(status string, matrixID int, limit int) ([]*AlgorithmJob, error) {
+	query := "SELECT " + algorithmJobColumns + ` FROM algorithm_jobs
+	WHERE ($1 = '' OR status = $1) AND ($2 = 0 OR matrix_id = $2)
+	ORDER BY id DESC LIMIT $3`
+	rows, err := d.db.Query(query, status, matrixID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*AlgorithmJob{}
+	for rows.Next() {
+		job, err := scanAlgorithmJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// marshalAlgorithmJob encodes the algorithms and params columns of a job,
+// NULL when they are empty
+func marshalAlgorithmJob(job *AlgorithmJob) (interface{}, interface{}, error) {
+	var algorithms, params interface{}
+	if len(job.Algorithms) > 0 {
+		algorithmsJson, err := json.Marshal(job.Algorithms)
+		if err != nil {
+			return nil, nil, err
+		}
+		algorithms = string(algorithmsJson)
+	}
+	if len(job.Params) > 0 {
+		paramsJson, err := json.Marshal(job.Params)
+		if err != nil {
+			return nil, nil, err
+		}
+		params = string(paramsJson)
+	}
+	return algorithms, params, nil
+}
+
+// scanAlgorithmJob scans an algorithm_jobs row selected with algorithmJobColumns
+func scanAlgorithmJob(scanner interface{ Scan(...interface{}) error }) (*AlgorithmJob, error) {
+	var job AlgorithmJob
+	var algorithms, params, leaseOwner, lastError sql.NullString
+	var leaseExpiresAt, heartbeatAt, finishedAt sql.NullTime
+	var timeoutMs int64
+	err := scanner.Scan(&job.ID, &job.MatrixID, &job.Source, &algorithms, &params, &job.ReuseEquivalents, &timeoutMs,
+		&job.Priority, &job.Status, &job.Attempts, &job.MaxAttempts, &job.RunAfter, &leaseOwner, &leaseExpiresAt,
+		&heartbeatAt, &lastError, &job.CreatedAt, &job.UpdatedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	job.Timeout = time.Duration(timeoutMs) * time.Millisecond
+	if algorithms.Valid {
+		if err := json.Unmarshal([]byte(algorithms.String), &job.Algorithms); err != nil {
+			return nil, fmt.Errorf("algoritma işi %d algoritmaları okunamadı: %v", job.ID, err)
+		}
+	}
+	if params.Valid {
+		if err := json.Unmarshal([]byte(params.String), &job.Params); err != nil {
+			return nil, fmt.Errorf("algoritma işi %d parametreleri okunamadı: %v", job.ID, err)
+		}
+	}
+	if leaseOwner.Valid {
+		job.LeaseOwner = &leaseOwner.String
+	}
+	if leaseExpiresAt.Valid {
+		job.LeaseExpiresAt = &leaseExpiresAt.Time
+	}
+	if heartbeatAt.Valid {
+		job.HeartbeatAt = &heartbeatAt.Time
+	}
+	if lastError.Valid {
+		job.LastError = &lastError.String
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return &job, nil
+}
+
+// UpdateVerification stores the verifier verdicts keyed by run ID; runs of
+// other matrices are left alone
+func (d *Database) UpdateVerification(id int, verdicts map[int]bool) error {
+	for runID, valid := range verdicts {
+		_, err := d.db.Exec("UPDATE algorithm_runs SET verified = $1 WHERE id = $2 AND matrix_id = $3", valid, runID, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matrixRecordColumns is the column list scanned by scanMatrixRecord; the XOR
+// count of the best run is reported as smallest_xor
+const matrixRecordColumns = `m.id, m.title, m.group_name, m.matrix_binary, m.matrix_hex, m.ham_xor_count,
+	       b.xor_count AS smallest_xor, m.best_run_id,
+	       m.matrix_hash, m.inverse_matrix_id, m.inverse_matrix_hash,
+	       m.field_matrix, m.field_polynomial, m.field_degree,
+	       m.differential_branch, m.linear_branch, m.is_mds, m.is_near_mds, m.is_involutory,
+	       m.is_semi_involutory, m.is_orthogonal, m.is_circulant, m.analyzed_at, m.canonical_hash, m.optimal_xor, m.xor_lower_bound,
+	       m.sxor_count, m.sxor_program, m.sxor_source, m.created_at, m.updated_at`
+
+// matrixRecordSource joins every matrix with its best run
+const matrixRecordSource = "matrix_records m LEFT JOIN algorithm_runs b ON b.id = m.best_run_id"
+
+// GetMatrixByID retrieves a matrix by its ID, or nil if it does not exist
+func (d *Database) GetMatrixByID(id int) (*MatrixRecord, error) {
+	query := "SELECT " + matrixRecordColumns + " FROM " + matrixRecordSource + " WHERE m.id = $1"
+
+	row := d.db.QueryRow(query, id)
+	record, err := d.scanMatrixRecord(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil || record == nil {
+		return record, err
+	}
+	if err := d.loadResults([]*MatrixRecord{record}, true); err != nil {
+		return nil, err
+	}
+
+	record.DepthResults, err = d.GetDepthResults(record)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetMatrixByHash retrieves a matrix by its hash, or nil if it does not exist
+func (d *Database) GetMatrixByHash(hash string) (*MatrixRecord, error) {
+	query := "SELECT " + matrixRecordColumns + " FROM " + matrixRecordSource + " WHERE m.matrix_hash = $1"
+
 	row := d.db.QueryRow(query, hash)
-	return d.scanMatrixRecord(row)
+	record, err := d.scanMatrixRecord(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil || record == nil {
+		return record, err
+	}
+	if err := d.loadResults([]*MatrixRecord{record}, true); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// XorRange bounds the XOR count of one solver in GetMatrices; nil bounds are not applied
+type XorRange struct {
+	Min *int
+	Max *int
+}
+
+// MatrixFilter holds the optional filters of GetMatrices; nil bounds and empty strings are not applied
+type MatrixFilter struct {
+	Title                 string
+	HamXorMin, HamXorMax  *int
+	SolverXor             map[string]XorRange // Keyed by solver name, bounds the solver's result; solvers that are not persisted are ignored
+	GXorMin, GXorMax      *int                // XOR count of the best run, the best g-XOR count
+	SXorMin, SXorMax      *int                // sxor_count
+	FieldDegree           *int
+	FieldPolynomial       string // Polynomial notation as stored, e.g. "x^4+x+1"
+	DifferentialBranchMin *int
+	LinearBranchMin       *int
+	Flags                 map[string]bool // Required values of analysisFlagColumns, e.g. {"is_mds": true}
+	GroupCanonical        bool            // List one matrix (the first stored) per canonical_hash
+	OptimalProven         *bool           // Whether the exact search proved the matrix's optimum
 }
 
 // GetMatrices retrieves matrices with pagination and filtering
-func (d *Database) GetMatrices(page, limit int, titleFilter string, hamXorMin, hamXorMax, boyarXorMin, boyarXorMax, paarXorMin, paarXorMax, slpXorMin, slpXorMax *int) ([]*MatrixRecord, int, error) {
+func (d *Database) GetMatrices(page, limit int, filter MatrixFilter) ([]*MatrixRecord, int, error) {
 	// Build WHERE clause
 	var conditions []string
 	var args []interface{}
 	argIndex := 1
 	
-	if titleFilter != "" {
-		conditions = append(conditions, fmt.Sprintf("LOWER(title) LIKE LOWER($%d)", argIndex))
-		args = append(args, "%"+titleFilter+"%")
+	if filter.Title != "" {
+		conditions = append(conditions, fmt.Sprintf("LOWER(m.title) LIKE LOWER($%d)", argIndex))
+		args = append(args, "%"+filter.Title+"%")
+		argIndex++
+	}
+
+	if filter.HamXorMin != nil {
+		conditions = append(conditions, fmt.Sprintf("m.ham_xor_count >= $%d", argIndex))
+		args = append(args, *filter.HamXorMin)
 		argIndex++
 	}
 
-	if hamXorMin != nil {
-		conditions = append(conditions, fmt.Sprintf("ham_xor_count >= $%d", argIndex))
-		args = append(args, *hamXorMin)
+	if filter.HamXorMax != nil {
+		conditions = append(conditions, fmt.Sprintf("m.ham_xor_count <= $%d", argIndex))
+		args = append(args, *filter.HamXorMax)
 		argIndex++
 	}
 
-	if hamXorMax != nil {
-		conditions = append(conditions, fmt.Sprintf("ham_xor_count <= $%d", argIndex))
-		args = append(args, *hamXorMax)
+	for _, info := range persistedSolvers() {
+		bounds, ok := filter.SolverXor[info.Name]
+		if !ok {
+			continue
+		}
+		if bounds.Min == nil && bounds.Max == nil {
+			continue
+		}
+		// The solver's result is its best run; aborted runs have no XOR count
+		runConditions := []string{fmt.Sprintf("r.algorithm = $%d", argIndex)}
+		args = append(args, info.Name)
 		argIndex++
+		if bounds.Min != nil {
+			runConditions = append(runConditions, fmt.Sprintf("r.xor_count >= $%d", argIndex))
+			args = append(args, *bounds.Min)
+			argIndex++
+		}
+		if bounds.Max != nil {
+			runConditions = append(runConditions, fmt.Sprintf("r.xor_count <= $%d", argIndex))
+			args = append(args, *bounds.Max)
+			argIndex++
+		}
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM algorithm_runs r WHERE r.matrix_id = m.id AND r.best AND r.status <> 'aborted' AND %s)",
+			strings.Join(runConditions, " AND ")))
 	}
 
-	if boyarXorMin != nil {
-		conditions = append(conditions, fmt.Sprintf("boyar_xor_count IS NOT NULL AND boyar_xor_count >= $%d", argIndex))
-		args = append(args, *boyarXorMin)
+	if filter.GXorMin != nil {
+		conditions = append(conditions, fmt.Sprintf("b.xor_count IS NOT NULL AND b.xor_count >= $%d", argIndex))
+		args = append(args, *filter.GXorMin)
 		argIndex++
 	}
 
-	if boyarXorMax != nil {
-		conditions = append(conditions, fmt.Sprintf("boyar_xor_count IS NOT NULL AND boyar_xor_count <= $%d", argIndex))
-		args = append(args, *boyarXorMax)
+	if filter.GXorMax != nil {
+		conditions = append(conditions, fmt.Sprintf("b.xor_count IS NOT NULL AND b.xor_count <= $%d", argIndex))
+		args = append(args, *filter.GXorMax)
 		argIndex++
 	}
 
-	if paarXorMin != nil {
-		conditions = append(conditions, fmt.Sprintf("paar_xor_count IS NOT NULL AND paar_xor_count >= $%d", argIndex))
-		args = append(args, *paarXorMin)
+	if filter.SXorMin != nil {
+		conditions = append(conditions, fmt.Sprintf("m.sxor_count IS NOT NULL AND m.sxor_count >= $%d", argIndex))
+		args = append(args, *filter.SXorMin)
 		argIndex++
 	}
 
-	if paarXorMax != nil {
-		conditions = append(conditions, fmt.Sprintf("paar_xor_count IS NOT NULL AND paar_xor_count <= $%d", argIndex))
-		args = append(args, *paarXorMax)
+	if filter.SXorMax != nil {
+		conditions = append(conditions, fmt.Sprintf("m.sxor_count IS NOT NULL AND m.sxor_count <= $%d", argIndex))
+		args = append(args, *filter.SXorMax)
 		argIndex++
 	}
 
-	if slpXorMin != nil {
-		conditions = append(conditions, fmt.Sprintf("slp_xor_count IS NOT NULL AND slp_xor_count >= $%d", argIndex))
-		args = append(args, *slpXorMin)
+	if filter.FieldDegree != nil {
+		conditions = append(conditions, fmt.Sprintf("m.field_degree = $%d", argIndex))
+		args = append(args, *filter.FieldDegree)
 		argIndex++
 	}
 
-	if slpXorMax != nil {
-		conditions = append(conditions, fmt.Sprintf("slp_xor_count IS NOT NULL AND slp_xor_count <= $%d", argIndex))
-		args = append(args, *slpXorMax)
+	if filter.FieldPolynomial != "" {
+		conditions = append(conditions, fmt.Sprintf("m.field_polynomial = $%d", argIndex))
+		args = append(args, filter.FieldPolynomial)
 		argIndex++
 	}
 
+	if filter.DifferentialBranchMin != nil {
+		conditions = append(conditions, fmt.Sprintf("m.differential_branch IS NOT NULL AND m.differential_branch >= $%d", argIndex))
+		args = append(args, *filter.DifferentialBranchMin)
+		argIndex++
+	}
+
+	if filter.LinearBranchMin != nil {
+		conditions = append(conditions, fmt.Sprintf("m.linear_branch IS NOT NULL AND m.linear_branch >= $%d", argIndex))
+		args = append(args, *filter.LinearBranchMin)
+		argIndex++
+	}
+
+	if filter.OptimalProven != nil {
+		if *filter.OptimalProven {
+			conditions = append(conditions, "m.optimal_xor IS NOT NULL")
+		} else {
+			conditions = append(conditions, "m.optimal_xor IS NULL")
+		}
+	}
+
+	for _, column := range analysisFlagColumns {
+		if value, ok := filter.Flags[column]; ok {
+			conditions = append(conditions, fmt.Sprintf("m.%s = $%d", column, argIndex))
+			args = append(args, value)
+			argIndex++
+		}
+	}
+
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
+	// Equivalent matrices are represented by the first stored one that matches the filter
+	if filter.GroupCanonical {
+		grouping := fmt.Sprintf("m.id IN (SELECT MIN(m.id) FROM %s %s GROUP BY COALESCE(m.canonical_hash, m.matrix_hash))",
+			matrixRecordSource, whereClause)
+		conditions = append(conditions, grouping)
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
 	// Count total records
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM matrix_records %s", whereClause)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", matrixRecordSource, whereClause)
 	var total int
 	err := d.db.QueryRow(countQuery, args...).Scan(&total)
 	if err != nil {
@@ -326,23 +1454,20 @@ func (d *Database) GetMatrices(page, limit int, titleFilter string, hamXorMin, h
 	// Get paginated records - optimized query without large fields for listing
 	offset := (page - 1) * limit
 	query := fmt.Sprintf(`
-	SELECT id, title, group_name, 
-	       CASE WHEN LENGTH(matrix_binary) > 100 THEN SUBSTRING(matrix_binary, 1, 100) || '...' ELSE matrix_binary END as matrix_binary,
-	       CASE WHEN LENGTH(matrix_hex) > 50 THEN SUBSTRING(matrix_hex, 1, 50) || '...' ELSE matrix_hex END as matrix_hex,
-	       ham_xor_count, smallest_xor,
-	       boyar_xor_count, boyar_depth, 
-	       CASE WHEN boyar_program IS NOT NULL THEN 'computed' ELSE NULL END as boyar_program,
-	       paar_xor_count, 
-	       CASE WHEN paar_program IS NOT NULL THEN 'computed' ELSE NULL END as paar_program,
-	       slp_xor_count, 
-	       CASE WHEN slp_program IS NOT NULL THEN 'computed' ELSE NULL END as slp_program,
-	       matrix_hash, inverse_matrix_id, inverse_matrix_hash, created_at, updated_at
-	FROM matrix_records %s
+	SELECT m.id, m.title, m.group_name, 
+	       CASE WHEN LENGTH(m.matrix_binary) > 100 THEN SUBSTRING(m.matrix_binary, 1, 100) || '...' ELSE m.matrix_binary END as matrix_binary,
+	       CASE WHEN LENGTH(m.matrix_hex) > 50 THEN SUBSTRING(m.matrix_hex, 1, 50) || '...' ELSE m.matrix_hex END as matrix_hex,
+	       m.ham_xor_count, b.xor_count AS smallest_xor, m.best_run_id,
+	       m.matrix_hash, m.inverse_matrix_id, m.inverse_matrix_hash,
+	       m.field_matrix, m.field_polynomial, m.field_degree,
+	       m.differential_branch, m.linear_branch, m.is_mds, m.is_near_mds, m.is_involutory,
+	       m.is_semi_involutory, m.is_orthogonal, m.is_circulant, m.analyzed_at, m.canonical_hash, m.optimal_xor, m.xor_lower_bound, m.sxor_count, m.created_at, m.updated_at
+	FROM %s %s
 	ORDER BY 
-	    CASE WHEN smallest_xor IS NOT NULL THEN smallest_xor ELSE ham_xor_count END ASC,
-	    created_at DESC
+	    CASE WHEN b.xor_count IS NOT NULL THEN b.xor_count ELSE m.ham_xor_count END ASC,
+	    m.created_at DESC
 	LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
+	`, matrixRecordSource, whereClause, argIndex, argIndex+1)
 
 	args = append(args, limit, offset)
 	
@@ -350,39 +1475,140 @@ func (d *Database) GetMatrices(page, limit int, titleFilter string, hamXorMin, h
 	if err != nil {
 		return nil, 0, err
 	}
-	defer rows.Close()
+	defer rows.Close()
+
+	var matrices []*MatrixRecord
+	for rows.Next() {
+		matrix, err := d.scanMatrixRecordOptimized(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		matrices = append(matrices, matrix)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	rows.Close()
+
+	if err := d.loadResults(matrices, false); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.GroupCanonical {
+		if err := d.countEquivalents(matrices); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return matrices, total, nil
+}
+
+// countEquivalents sets EquivalentCount of every matrix with a canonical hash
+func (d *Database) countEquivalents(matrices []*MatrixRecord) error {
+	byHash := make(map[string][]*MatrixRecord)
+	var placeholders []string
+	var args []interface{}
+	for _, matrix := range matrices {
+		if matrix.CanonicalHash == nil {
+			continue
+		}
+		if _, seen := byHash[*matrix.CanonicalHash]; !seen {
+			args = append(args, *matrix.CanonicalHash)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		byHash[*matrix.CanonicalHash] = append(byHash[*matrix.CanonicalHash], matrix)
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`
+	SELECT canonical_hash, COUNT(*) FROM matrix_records
+	WHERE canonical_hash IN (%s)
+	GROUP BY canonical_hash
+	`, strings.Join(placeholders, ", "))
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		var count int
+		if err := rows.Scan(&hash, &count); err != nil {
+			return err
+		}
+		for _, matrix := range byHash[hash] {
+			val := count
+			matrix.EquivalentCount = &val
+		}
+	}
+	return rows.Err()
+}
+
+// GetEquivalentMatrices returns the other stored matrices with the canonical
+// hash of record, i.e. its row and column permutations
+func (d *Database) GetEquivalentMatrices(record *MatrixRecord) ([]*MatrixRecord, error) {
+	if record.CanonicalHash == nil {
+		return nil, nil
+	}
+	rows, err := d.db.Query("SELECT id FROM matrix_records WHERE canonical_hash = $1 AND id <> $2 ORDER BY id",
+		*record.CanonicalHash, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
 
-	var matrices []*MatrixRecord
-	for rows.Next() {
-		matrix, err := d.scanMatrixRecordOptimized(rows)
+	var equivalents []*MatrixRecord
+	for _, id := range ids {
+		equivalent, err := d.GetMatrixByID(id)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
+		}
+		if equivalent != nil {
+			equivalents = append(equivalents, equivalent)
 		}
-		matrices = append(matrices, matrix)
 	}
-
-	return matrices, total, nil
+	return equivalents, nil
 }
 
 // scanMatrixRecord scans a row into a MatrixRecord
 func (d *Database) scanMatrixRecord(scanner interface{}) (*MatrixRecord, error) {
 	var record MatrixRecord
 	var groupName sql.NullString
-	var smallestXor, boyarXor, boyarDepth, paarXor, slpXor, inverseMatrixID sql.NullInt64
-	var boyarProgram, paarProgram, slpProgram, inverseMatrixHash sql.NullString
+	var smallestXor, bestRunID, inverseMatrixID sql.NullInt64
+	var inverseMatrixHash sql.NullString
+	var fieldMatrix, fieldPolynomial sql.NullString
+	var fieldDegree sql.NullInt64
+	var analysis analysisColumns
+	var canonicalHash sql.NullString
+	var optimalXor, xorLowerBound, sxorCount sql.NullInt64
+	var sxorProgram, sxorSource sql.NullString
+
+	dest := []interface{}{&record.ID, &record.Title, &groupName, &record.MatrixBinary, &record.MatrixHex,
+		&record.HamXorCount, &smallestXor, &bestRunID,
+		&record.MatrixHash, &inverseMatrixID, &inverseMatrixHash,
+		&fieldMatrix, &fieldPolynomial, &fieldDegree,
+		&analysis.differentialBranch, &analysis.linearBranch, &analysis.mds, &analysis.nearMDS, &analysis.involutory,
+		&analysis.semiInvolutory, &analysis.orthogonal, &analysis.circulant, &analysis.analyzedAt,
+		&canonicalHash, &optimalXor, &xorLowerBound, &sxorCount, &sxorProgram, &sxorSource, &record.CreatedAt, &record.UpdatedAt}
 
 	var err error
 	switch s := scanner.(type) {
 	case *sql.Row:
-		err = s.Scan(&record.ID, &record.Title, &groupName, &record.MatrixBinary, &record.MatrixHex,
-			&record.HamXorCount, &smallestXor, &boyarXor, &boyarDepth, &boyarProgram,
-			&paarXor, &paarProgram, &slpXor, &slpProgram,
-			&record.MatrixHash, &inverseMatrixID, &inverseMatrixHash, &record.CreatedAt, &record.UpdatedAt)
+		err = s.Scan(dest...)
 	case *sql.Rows:
-		err = s.Scan(&record.ID, &record.Title, &groupName, &record.MatrixBinary, &record.MatrixHex,
-			&record.HamXorCount, &smallestXor, &boyarXor, &boyarDepth, &boyarProgram,
-			&paarXor, &paarProgram, &slpXor, &slpProgram,
-			&record.MatrixHash, &inverseMatrixID, &inverseMatrixHash, &record.CreatedAt, &record.UpdatedAt)
+		err = s.Scan(dest...)
 	default:
 		return nil, fmt.Errorf("unsupported scanner type")
 	}
@@ -399,61 +1625,97 @@ func (d *Database) scanMatrixRecord(scanner interface{}) (*MatrixRecord, error)
 		val := int(smallestXor.Int64)
 		record.SmallestXor = &val
 	}
-	if boyarXor.Valid {
-		val := int(boyarXor.Int64)
-		record.BoyarXorCount = &val
+	if bestRunID.Valid {
+		val := int(bestRunID.Int64)
+		record.BestRunID = &val
 	}
-	if boyarDepth.Valid {
-		val := int(boyarDepth.Int64)
-		record.BoyarDepth = &val
+	if inverseMatrixID.Valid {
+		val := int(inverseMatrixID.Int64)
+		record.InverseMatrixID = &val
 	}
-	if boyarProgram.Valid {
-		record.BoyarProgram = &boyarProgram.String
+	if inverseMatrixHash.Valid {
+		record.InverseMatrixHash = &inverseMatrixHash.String
 	}
-	if paarXor.Valid {
-		val := int(paarXor.Int64)
-		record.PaarXorCount = &val
+	if fieldMatrix.Valid {
+		record.FieldMatrix = parseStoredFieldMatrix(fieldMatrix.String)
 	}
-	if paarProgram.Valid {
-		record.PaarProgram = &paarProgram.String
+	if fieldPolynomial.Valid {
+		record.FieldPolynomial = &fieldPolynomial.String
 	}
-	if slpXor.Valid {
-		val := int(slpXor.Int64)
-		record.SlpXorCount = &val
+	if fieldDegree.Valid {
+		val := int(fieldDegree.Int64)
+		record.FieldDegree = &val
 	}
-	if slpProgram.Valid {
-		record.SlpProgram = &slpProgram.String
+	analysis.apply(&record)
+	if canonicalHash.Valid {
+		record.CanonicalHash = &canonicalHash.String
 	}
-	if inverseMatrixID.Valid {
-		val := int(inverseMatrixID.Int64)
-		record.InverseMatrixID = &val
+	if optimalXor.Valid {
+		val := int(optimalXor.Int64)
+		record.OptimalXor = &val
 	}
-	if inverseMatrixHash.Valid {
-		record.InverseMatrixHash = &inverseMatrixHash.String
+	if xorLowerBound.Valid {
+		val := int(xorLowerBound.Int64)
+		record.XorLowerBound = &val
+	}
+	if sxorCount.Valid {
+		val := int(sxorCount.Int64)
+		record.SXorCount = &val
+	}
+	if sxorProgram.Valid {
+		var program InPlaceProgram
+		if err := json.Unmarshal([]byte(sxorProgram.String), &program); err != nil {
+			log.Printf("⚠️ Matris %d sxor programı okunamadı: %v", record.ID, err)
+		} else {
+			record.SXorProgram = &program
+		}
+	}
+	if sxorSource.Valid {
+		record.SXorSource = &sxorSource.String
 	}
 
 	return &record, nil
 }
 
-// scanMatrixRecordOptimized scans a row into a MatrixRecord for listing (optimized)
+// scanProgram decodes a program column, logging stored programs that cannot be read
+func scanProgram(value sql.NullString, record *MatrixRecord, column string) *Program {
+	if !value.Valid {
+		return nil
+	}
+	program, err := decodeStoredProgram(value.String, record.MatrixBinary)
+	if err != nil {
+		log.Printf("⚠️ Matris %d %s programı okunamadı: %v", record.ID, column, err)
+		return nil
+	}
+	return program
+}
+
+// scanMatrixRecordOptimized scans a row into a MatrixRecord for listing (optimized, without programs)
 func (d *Database) scanMatrixRecordOptimized(scanner interface{}) (*MatrixRecord, error) {
 	var record MatrixRecord
 	var groupName sql.NullString
-	var smallestXor, boyarXor, boyarDepth, paarXor, slpXor, inverseMatrixID sql.NullInt64
-	var boyarProgram, paarProgram, slpProgram, inverseMatrixHash sql.NullString
+	var smallestXor, bestRunID, inverseMatrixID sql.NullInt64
+	var inverseMatrixHash sql.NullString
+	var fieldMatrix, fieldPolynomial sql.NullString
+	var fieldDegree sql.NullInt64
+	var analysis analysisColumns
+	var canonicalHash sql.NullString
+	var optimalXor, xorLowerBound, sxorCount sql.NullInt64
+
+	dest := []interface{}{&record.ID, &record.Title, &groupName, &record.MatrixBinary, &record.MatrixHex,
+		&record.HamXorCount, &smallestXor, &bestRunID,
+		&record.MatrixHash, &inverseMatrixID, &inverseMatrixHash,
+		&fieldMatrix, &fieldPolynomial, &fieldDegree,
+		&analysis.differentialBranch, &analysis.linearBranch, &analysis.mds, &analysis.nearMDS, &analysis.involutory,
+		&analysis.semiInvolutory, &analysis.orthogonal, &analysis.circulant, &analysis.analyzedAt,
+		&canonicalHash, &optimalXor, &xorLowerBound, &sxorCount, &record.CreatedAt, &record.UpdatedAt}
 
 	var err error
 	switch s := scanner.(type) {
 	case *sql.Row:
-		err = s.Scan(&record.ID, &record.Title, &groupName, &record.MatrixBinary, &record.MatrixHex,
-			&record.HamXorCount, &smallestXor, &boyarXor, &boyarDepth, &boyarProgram,
-			&paarXor, &paarProgram, &slpXor, &slpProgram,
-			&record.MatrixHash, &inverseMatrixID, &inverseMatrixHash, &record.CreatedAt, &record.UpdatedAt)
+		err = s.Scan(dest...)
 	case *sql.Rows:
-		err = s.Scan(&record.ID, &record.Title, &groupName, &record.MatrixBinary, &record.MatrixHex,
-			&record.HamXorCount, &smallestXor, &boyarXor, &boyarDepth, &boyarProgram,
-			&paarXor, &paarProgram, &slpXor, &slpProgram,
-			&record.MatrixHash, &inverseMatrixID, &inverseMatrixHash, &record.CreatedAt, &record.UpdatedAt)
+		err = s.Scan(dest...)
 	default:
 		return nil, fmt.Errorf("unsupported scanner type")
 	}
@@ -470,47 +1732,141 @@ func (d *Database) scanMatrixRecordOptimized(scanner interface{}) (*MatrixRecord
 		val := int(smallestXor.Int64)
 		record.SmallestXor = &val
 	}
-	if boyarXor.Valid {
-		val := int(boyarXor.Int64)
-		record.BoyarXorCount = &val
+	if bestRunID.Valid {
+		val := int(bestRunID.Int64)
+		record.BestRunID = &val
 	}
-	if boyarDepth.Valid {
-		val := int(boyarDepth.Int64)
-		record.BoyarDepth = &val
+	if inverseMatrixID.Valid {
+		val := int(inverseMatrixID.Int64)
+		record.InverseMatrixID = &val
 	}
-	if boyarProgram.Valid {
-		record.BoyarProgram = &boyarProgram.String
+	if inverseMatrixHash.Valid {
+		record.InverseMatrixHash = &inverseMatrixHash.String
 	}
-	if paarXor.Valid {
-		val := int(paarXor.Int64)
-		record.PaarXorCount = &val
+	if fieldMatrix.Valid {
+		record.FieldMatrix = parseStoredFieldMatrix(fieldMatrix.String)
 	}
-	if paarProgram.Valid {
-		record.PaarProgram = &paarProgram.String
+	if fieldPolynomial.Valid {
+		record.FieldPolynomial = &fieldPolynomial.String
 	}
-	if slpXor.Valid {
-		val := int(slpXor.Int64)
-		record.SlpXorCount = &val
+	if fieldDegree.Valid {
+		val := int(fieldDegree.Int64)
+		record.FieldDegree = &val
 	}
-	if slpProgram.Valid {
-		record.SlpProgram = &slpProgram.String
+	analysis.apply(&record)
+	if canonicalHash.Valid {
+		record.CanonicalHash = &canonicalHash.String
 	}
-	if inverseMatrixID.Valid {
-		val := int(inverseMatrixID.Int64)
-		record.InverseMatrixID = &val
+	if optimalXor.Valid {
+		val := int(optimalXor.Int64)
+		record.OptimalXor = &val
 	}
-	if inverseMatrixHash.Valid {
-		record.InverseMatrixHash = &inverseMatrixHash.String
+	if xorLowerBound.Valid {
+		val := int(xorLowerBound.Int64)
+		record.XorLowerBound = &val
+	}
+	if sxorCount.Valid {
+		val := int(sxorCount.Int64)
+		record.SXorCount = &val
 	}
 
 	return &record, nil
 }
 
+// loadResults sets Results of records from the best runs of the persisted
+// solvers in one query. Listings leave the programs out.
+func (d *Database) loadResults(records []*MatrixRecord, programs bool) error {
+	byID := make(map[int]*MatrixRecord, len(records))
+	var placeholders []string
+	var args []interface{}
+	for _, record := range records {
+		record.Results = make(map[string]*SolverResult)
+		byID[record.ID] = record
+		args = append(args, record.ID)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	programColumn := "NULL"
+	if programs {
+		programColumn = "program"
+	}
+	query := fmt.Sprintf(`
+	SELECT id, matrix_id, algorithm, depth_limit, transpose, xor_count, depth, %s, seed, status, origin, verified
+	FROM algorithm_runs
+	WHERE best AND matrix_id IN (%s)
+	`, programColumn, strings.Join(placeholders, ", "))
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result SolverResult
+		var matrixID int
+		var algorithm, status string
+		var xorCount, depth, seed sql.NullInt64
+		var program, origin sql.NullString
+		var verified sql.NullBool
+		if err := rows.Scan(&result.RunID, &matrixID, &algorithm, &result.DepthLimit, &result.Transpose,
+			&xorCount, &depth, &program, &seed, &status, &origin, &verified); err != nil {
+			return err
+		}
+		info, ok := GetSolverInfo(algorithm)
+		record := byID[matrixID]
+		if !ok || !info.Persisted || record == nil {
+			continue
+		}
+
+		// Aborted runs only report their status
+		if xorCount.Valid && status != StatusAborted {
+			val := int(xorCount.Int64)
+			result.XorCount = &val
+		}
+		if depth.Valid {
+			val := int(depth.Int64)
+			result.Depth = &val
+		}
+		result.Program = scanProgram(program, record, info.Name)
+		if seed.Valid {
+			result.Seed = &seed.Int64
+		}
+		result.Status = &status
+		if origin.Valid {
+			result.Origin = &origin.String
+		}
+		if verified.Valid {
+			result.Verified = &verified.Bool
+		}
+		record.Results[info.Name] = &result
+	}
+	return rows.Err()
+}
+
 // Close closes the database connection
 func (d *Database) Close() error {
 	return d.db.Close()
 }
 
+// TitleExists reports whether a matrix with the given title is stored
+func (d *Database) TitleExists(title string) (bool, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM matrix_records WHERE title = $1", title).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateHamXorCount stores a recalculated Hamming XOR count
+func (d *Database) UpdateHamXorCount(id int, hamXor int) error {
+	_, err := d.db.Exec("UPDATE matrix_records SET ham_xor_count = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2", hamXor, id)
+	return err
+}
+
 // GetMatrixCount returns the total number of matrices in the database
 func (d *Database) GetMatrixCount() (int, error) {
 	var count int
@@ -519,7 +1875,7 @@ func (d *Database) GetMatrixCount() (int, error) {
 }
 
 // ImportMatricesFromFiles imports matrices from the matrices-data directory
-func (d *Database) ImportMatricesFromFiles(dataPath string) error {
+func ImportMatricesFromFiles(store MatrixStore, dataPath string) error {
 	importStartTime := time.Now()
 	log.Printf("🚀 [IMPORT] Matrices-data klasöründen matrisler import ediliyor: %s", dataPath)
 	
@@ -546,7 +1902,7 @@ func (d *Database) ImportMatricesFromFiles(dataPath string) error {
 		fileStartTime := time.Now()
 		log.Printf("📄 [IMPORT] Dosya işleniyor (%d/%d): %s", i+1, len(files), fileName)
 		
-		count, err := d.importMatricesFromFile(filePath)
+		count, err := importMatricesFromFile(store, filePath)
 		fileDuration := time.Since(fileStartTime)
 		
 		if err != nil {
@@ -564,7 +1920,7 @@ func (d *Database) ImportMatricesFromFiles(dataPath string) error {
 }
 
 // importMatricesFromFile imports matrices from a single file
-func (d *Database) importMatricesFromFile(filePath string) (int, error) {
+func importMatricesFromFile(store MatrixStore, filePath string) (int, error) {
 	fileStartTime := time.Now()
 	fileName := filepath.Base(filePath)
 	log.Printf("📖 [FILE] Dosya okunuyor: %s", fileName)
@@ -578,6 +1934,15 @@ func (d *Database) importMatricesFromFile(filePath string) (int, error) {
 	// Extract filename without extension as group name
 	groupName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
 
+	// Files named like "F2^4-x^4+x+1-..." hold matrices over that field
+	field, err := fieldFromFileName(fileName)
+	if err != nil {
+		return 0, fmt.Errorf("dosya adındaki alan geçersiz: %v", err)
+	}
+	if field != nil {
+		log.Printf("🔢 [FILE] %s matrisleri %s üzerinde yorumlanıyor", fileName, field)
+	}
+
 	scanner := bufio.NewScanner(file)
 	var currentMatrix [][]string
 	var currentTitle string
@@ -600,7 +1965,7 @@ func (d *Database) importMatricesFromFile(filePath string) (int, error) {
 			// Process current matrix if we have one
 			if len(currentMatrix) > 0 && currentTitle != "" {
 				matrixStartTime := time.Now()
-				err := d.saveMatrixFromImport(currentTitle, currentMatrix, groupName)
+				err := saveMatrixFromImport(store, currentTitle, currentMatrix, groupName, field)
 				matrixDuration := time.Since(matrixStartTime)
 				
 				if err != nil {
@@ -641,7 +2006,7 @@ func (d *Database) importMatricesFromFile(filePath string) (int, error) {
 	// Process the last matrix if exists
 	if len(currentMatrix) > 0 && currentTitle != "" {
 		matrixStartTime := time.Now()
-		err := d.saveMatrixFromImport(currentTitle, currentMatrix, groupName)
+		err := saveMatrixFromImport(store, currentTitle, currentMatrix, groupName, field)
 		matrixDuration := time.Since(matrixStartTime)
 		
 		if err != nil {
@@ -666,15 +2031,26 @@ func (d *Database) importMatricesFromFile(filePath string) (int, error) {
 	return importedCount, nil
 }
 
-// saveMatrixFromImport saves a matrix during import process
-func (d *Database) saveMatrixFromImport(title string, matrix [][]string, group string) error {
+// saveMatrixFromImport saves a matrix during import process. Matrices of
+// files that declare a field may be given as field elements; they are
+// expanded to binary and stored with their field representation.
+func saveMatrixFromImport(store MatrixStore, title string, matrix [][]string, group string, field *GF2m) error {
 	startTime := time.Now()
 	log.Printf("📊 [IMPORT] Matris işleme başlıyor: %s", title)
+
+	var elements FieldMatrix
+	if field != nil {
+		var err error
+		matrix, elements, err = resolveFieldMatrix(field, matrix)
+		if err != nil {
+			return fmt.Errorf("alan matrisi çözümlenemedi: %v", err)
+		}
+	}
 	
 	// Check if matrix already exists by hash
 	hashStartTime := time.Now()
 	matrixHash := calculateMatrixHash(matrix)
-	existing, err := d.GetMatrixByHash(matrixHash)
+	existing, err := store.GetMatrixByHash(matrixHash)
 	hashDuration := time.Since(hashStartTime)
 	log.Printf("⏱️  [IMPORT] Hash kontrolü tamamlandı (%v): %s", hashDuration, title)
 	
@@ -686,7 +2062,7 @@ func (d *Database) saveMatrixFromImport(title string, matrix [][]string, group s
 
 	// Save the matrix
 	saveStartTime := time.Now()
-	savedMatrix, err := d.SaveMatrix(title, matrix, group)
+	savedMatrix, err := SaveMatrix(store, title, matrix, group)
 	saveDuration := time.Since(saveStartTime)
 	log.Printf("💾 [IMPORT] Matris veritabanına kaydedildi (%v): %s", saveDuration, title)
 	
@@ -694,26 +2070,21 @@ func (d *Database) saveMatrixFromImport(title string, matrix [][]string, group s
 		return err
 	}
 
-	// Queue algorithm calculation using worker pool
-	if algorithmWorkerPool != nil {
-		log.Printf("🧮 [IMPORT] Algoritma hesaplamaları kuyruğa ekleniyor: %s", title)
-		
-		job := AlgorithmJob{
-			MatrixID: savedMatrix.ID,
-			Title:    title,
-			Matrix:   matrix,
+	if elements != nil {
+		if err := setFieldRepresentation(store, savedMatrix.ID, field, elements); err != nil {
+			log.Printf("⚠️  [IMPORT] Alan gösterimi kaydedilemedi: %s: %v", title, err)
 		}
-		
-		select {
-		case algorithmWorkerPool.jobs <- job:
-			log.Printf("✅ [IMPORT] Algoritma işi kuyruğa eklendi: %s", title)
-		default:
-			log.Printf("⚠️  [IMPORT] Algoritma kuyruğu dolu, atlanıyor: %s", title)
-		}
-	} else {
-		log.Printf("⚠️  [IMPORT] Worker pool başlatılmamış, algoritmalar atlanıyor: %s", title)
 	}
 
+	// Queue algorithm calculation for the worker pool
+	log.Printf("🧮 [IMPORT] Algoritma hesaplamaları kuyruğa ekleniyor: %s", title)
+	job := &AlgorithmJob{MatrixID: savedMatrix.ID, Source: JobSourceImport, ReuseEquivalents: true}
+	if err := EnqueueAlgorithmJob(store, job); err != nil {
+		log.Printf("❌ [IMPORT] %s: %v", title, err)
+		return err
+	}
+	log.Printf("✅ [IMPORT] Algoritma işi %d kuyruğa eklendi: %s", job.ID, title)
+
 	totalDuration := time.Since(startTime)
 	log.Printf("📈 [IMPORT] Matris işleme tamamlandı (%v): %s", totalDuration, title)
 	return nil
@@ -741,7 +2112,7 @@ func (d *Database) GetAllMatrixHashes() (map[string]bool, error) {
 }
 
 // GetFileMatrixHashes returns all matrix hashes from files
-func (d *Database) GetFileMatrixHashes(dataPath string) (map[string]bool, error) {
+func GetFileMatrixHashes(dataPath string) (map[string]bool, error) {
 	files, err := filepath.Glob(filepath.Join(dataPath, "*.txt"))
 	if err != nil {
 		return nil, err
@@ -749,7 +2120,7 @@ func (d *Database) GetFileMatrixHashes(dataPath string) (map[string]bool, error)
 
 	hashes := make(map[string]bool)
 	for _, filePath := range files {
-		fileHashes, err := d.getMatrixHashesFromFile(filePath)
+		fileHashes, err := getMatrixHashesFromFile(filePath)
 		if err != nil {
 			log.Printf("HATA: %s dosyasındaki hash'ler alınamadı: %v", filepath.Base(filePath), err)
 			continue
@@ -763,13 +2134,27 @@ func (d *Database) GetFileMatrixHashes(dataPath string) (map[string]bool, error)
 }
 
 // getMatrixHashesFromFile extracts matrix hashes from a single file
-func (d *Database) getMatrixHashesFromFile(filePath string) (map[string]bool, error) {
+func getMatrixHashesFromFile(filePath string) (map[string]bool, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
+	// Matrices of field files are hashed in their binary expansion, as stored
+	field, err := fieldFromFileName(filepath.Base(filePath))
+	if err != nil {
+		return nil, err
+	}
+	matrixHash := func(matrix [][]string) string {
+		if field != nil {
+			if binary, _, err := resolveFieldMatrix(field, matrix); err == nil {
+				return calculateMatrixHash(binary)
+			}
+		}
+		return calculateMatrixHash(matrix)
+	}
+
 	scanner := bufio.NewScanner(file)
 	var currentMatrix [][]string
 	var currentTitle string
@@ -787,7 +2172,7 @@ func (d *Database) getMatrixHashesFromFile(filePath string) (map[string]bool, er
 		if strings.HasPrefix(line, "------------------------------") {
 			// Process current matrix if we have one
 			if len(currentMatrix) > 0 && currentTitle != "" {
-				hash := calculateMatrixHash(currentMatrix)
+				hash := matrixHash(currentMatrix)
 				hashes[hash] = true
 			}
 			
@@ -817,27 +2202,38 @@ func (d *Database) getMatrixHashesFromFile(filePath string) (map[string]bool, er
 
 	// Process the last matrix if exists
 	if len(currentMatrix) > 0 && currentTitle != "" {
-		hash := calculateMatrixHash(currentMatrix)
+		hash := matrixHash(currentMatrix)
 		hashes[hash] = true
 	}
 
 	return hashes, scanner.Err()
 }
 
-// GetMatricesWithoutAlgorithms returns matrices that don't have algorithm results
+// GetMatricesWithoutAlgorithms returns matrices missing the result of a
+// default solver
 func (d *Database) GetMatricesWithoutAlgorithms(limit int) ([]*MatrixRecord, error) {
+	var missing []string
+	args := []interface{}{limit}
+	for _, info := range persistedSolvers() {
+		if info.Default {
+			args = append(args, info.Name)
+			missing = append(missing, fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM algorithm_runs r
+		WHERE r.matrix_id = m.id AND r.algorithm = $%d AND r.best AND r.status <> 'aborted')`, len(args)))
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
 	query := `
-	SELECT id, title, matrix_binary, matrix_hex, ham_xor_count, 
-	       boyar_xor_count, boyar_depth, boyar_program,
-	       paar_xor_count, paar_program, slp_xor_count, slp_program,
-	       matrix_hash, created_at, updated_at
-	FROM matrix_records 
-	WHERE (boyar_xor_count IS NULL OR paar_xor_count IS NULL OR slp_xor_count IS NULL)
-	ORDER BY created_at ASC
+	SELECT ` + matrixRecordColumns + `
+	FROM ` + matrixRecordSource + `
+	WHERE (` + strings.Join(missing, " OR ") + `)
+	ORDER BY m.created_at ASC
 	LIMIT $1
 	`
 	
-	rows, err := d.db.Query(query, limit)
+	rows, err := d.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -851,319 +2247,69 @@ func (d *Database) GetMatricesWithoutAlgorithms(limit int) ([]*MatrixRecord, err
 		}
 		matrices = append(matrices, matrix)
 	}
-
-	return matrices, nil
-}
-
-// Algorithm runner functions for import process
-
-// runBoyarSLP runs the Boyar SLP algorithm on a matrix
-func runBoyarSLP(matrix [][]string) (*AlgResult, error) {
-	boyar := NewBoyarSLP(10) // depth limit
-	
-	err := boyar.ReadTargetMatrix(matrix)
-	if err != nil {
-		return nil, err
-	}
-	
-	err = boyar.InitBase()
-	if err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	
-	result, err := boyar.Solve(matrix)
-	if err != nil {
+	rows.Close()
+
+	if err := d.loadResults(matrices, true); err != nil {
 		return nil, err
 	}
-	
-	return &result, nil
+	return matrices, nil
 }
 
-// runPaarAlgorithm runs the Paar algorithm on a matrix
-func runPaarAlgorithm(matrix [][]string) (*AlgResult, error) {
-	paar := NewPaarAlgorithm()
-	
-	err := paar.ReadTargetMatrix(matrix)
-	if err != nil {
-		return nil, err
-	}
-	
-	err = paar.InitBase()
-	if err != nil {
-		return nil, err
+// Global storage backend, set by InitDatabase
+var db MatrixStore
+
+// storageDriver returns config.Database.Driver, overridden by DB_DRIVER
+func storageDriver(config *Config) string {
+	if envDriver := os.Getenv("DB_DRIVER"); envDriver != "" {
+		return envDriver
 	}
-	
-	result, err := paar.Solve(matrix)
-	if err != nil {
-		return nil, err
+	if config.Database.Driver == "" {
+		return DriverPostgres
 	}
-	
-	return &result, nil
+	return config.Database.Driver
 }
 
-// runSLPHeuristic runs the SLP Heuristic algorithm on a matrix
-func runSLPHeuristic(matrix [][]string) (*AlgResult, error) {
-	slp := NewSLPHeuristic()
-	
-	err := slp.ReadTargetMatrix(matrix)
-	if err != nil {
-		return nil, err
-	}
-	
-	err = slp.InitBase()
-	if err != nil {
-		return nil, err
+// openStore opens the storage backend of the configured driver. The memory
+// driver keeps everything in process and loses it on exit.
+func openStore(config *Config) (MatrixStore, error) {
+	if storageDriver(config) == DriverMemory {
+		return NewMemoryStore(), nil
 	}
-	
-	result, err := slp.Solve(matrix)
+	database, err := openDatabase(config)
 	if err != nil {
 		return nil, err
 	}
-	
-	return &result, nil
-}
-
-// Worker pool for algorithm calculations
-type AlgorithmWorker struct {
-	jobs    chan AlgorithmJob
-	results chan AlgorithmResult
-	quit    chan bool
-}
-
-type AlgorithmJob struct {
-	MatrixID int
-	Title    string
-	Matrix   [][]string
-}
-
-type AlgorithmResult struct {
-	MatrixID     int
-	BoyarResult  *AlgResult
-	PaarResult   *AlgResult
-	SlpResult    *AlgResult
-	Error        error
-}
-
-var (
-	algorithmWorkerPool *AlgorithmWorker
-	maxWorkers          = 8 // 4-core 16GB sunucu için optimize edildi (2x core count)
-)
-
-// InitAlgorithmWorkerPool initializes the worker pool
-func InitAlgorithmWorkerPool() {
-	algorithmWorkerPool = &AlgorithmWorker{
-		jobs:    make(chan AlgorithmJob, 100),
-		results: make(chan AlgorithmResult, 100),
-		quit:    make(chan bool),
-	}
-
-	// Start workers
-	for i := 0; i < maxWorkers; i++ {
-		go algorithmWorkerPool.worker(i)
-	}
-
-	// Start result processor
-	go algorithmWorkerPool.processResults()
+	return database, nil
 }
 
-func (w *AlgorithmWorker) worker(id int) {
-	log.Printf("🔧 [WORKER-%d] Algorithm worker başlatıldı", id)
-	for {
-		select {
-		case job := <-w.jobs:
-			log.Printf("🔧 [WORKER-%d] İşleniyor: %s", id, job.Title)
-			
-			// Calculate algorithms
-			boyarResult, boyarErr := runBoyarSLP(job.Matrix)
-			if boyarErr != nil {
-				log.Printf("❌ [WORKER-%d] Boyar hatası: %v", id, boyarErr)
-			} else {
-				log.Printf("✅ [WORKER-%d] Boyar SLP tamamlandı - XOR: %d", id, boyarResult.XorCount)
-			}
-			
-			paarResult, paarErr := runPaarAlgorithm(job.Matrix)
-			if paarErr != nil {
-				log.Printf("❌ [WORKER-%d] Paar hatası: %v", id, paarErr)
-			} else {
-				log.Printf("✅ [WORKER-%d] Paar algoritması tamamlandı - XOR: %d", id, paarResult.XorCount)
-			}
-			
-			slpResult, slpErr := runSLPHeuristic(job.Matrix)
-			if slpErr != nil {
-				log.Printf("❌ [WORKER-%d] SLP hatası: %v", id, slpErr)
-			} else {
-				log.Printf("✅ [WORKER-%d] SLP Heuristic tamamlandı - XOR: %d", id, slpResult.XorCount)
-			}
-			
-			// Send result
-			result := AlgorithmResult{
-				MatrixID:    job.MatrixID,
-				BoyarResult: boyarResult,
-				PaarResult:  paarResult,
-				SlpResult:   slpResult,
-			}
-			
-			if boyarErr != nil || paarErr != nil || slpErr != nil {
-				result.Error = fmt.Errorf("algorithm errors: boyar=%v, paar=%v, slp=%v", boyarErr, paarErr, slpErr)
-			}
-			
-			w.results <- result
-			log.Printf("✅ [WORKER-%d] Tamamlandı: %s", id, job.Title)
-			
-		case <-w.quit:
-			log.Printf("🔧 [WORKER-%d] Kapatılıyor", id)
-			return
+// openDatabase opens the database of the configured driver; DB_PATH
+// overrides the configured SQLite file
+func openDatabase(config *Config) (*Database, error) {
+	driver := storageDriver(config)
+	switch driver {
+	case DriverPostgres:
+		return NewDatabase(databaseConnectionString(config))
+	case DriverSQLite:
+		path := config.Database.Path
+		if envPath := os.Getenv("DB_PATH"); envPath != "" {
+			path = envPath
 		}
-	}
-}
-
-func (w *AlgorithmWorker) processResults() {
-	for result := range w.results {
-		if result.Error != nil {
-			log.Printf("❌ [RESULT] Matris %d için algoritma hatası: %v", result.MatrixID, result.Error)
-			continue
+		if path == "" {
+			path = defaultSQLitePath
 		}
-		
-		err := db.UpdateMatrixResults(result.MatrixID, result.BoyarResult, result.PaarResult, result.SlpResult)
-		if err != nil {
-			log.Printf("❌ [RESULT] Matris %d için sonuçlar kaydedilemedi: %v", result.MatrixID, err)
-		} else {
-			log.Printf("✅ [RESULT] Matris %d için sonuçlar kaydedildi", result.MatrixID)
-		}
-	}
-}
-
-// Global database instance
-var db *Database
-
-// createTables creates the necessary database tables
-func createTables(database *sql.DB) error {
-	// First, try to add new columns if they don't exist
-	migrationSQL := `
-	-- Add group_name column if it doesn't exist
-	DO $$ 
-	BEGIN 
-		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='matrix_records' AND column_name='group_name') THEN
-			ALTER TABLE matrix_records ADD COLUMN group_name VARCHAR(255);
-		END IF;
-	END $$;
-
-	-- Add smallest_xor column if it doesn't exist
-	DO $$ 
-	BEGIN 
-		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='matrix_records' AND column_name='smallest_xor') THEN
-			ALTER TABLE matrix_records ADD COLUMN smallest_xor INTEGER;
-		END IF;
-	END $$;
-
-	-- Add inverse_matrix_id column if it doesn't exist
-	DO $$ 
-	BEGIN 
-		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='matrix_records' AND column_name='inverse_matrix_id') THEN
-			ALTER TABLE matrix_records ADD COLUMN inverse_matrix_id INTEGER;
-		END IF;
-	END $$;
-
-	-- Add inverse_matrix_hash column if it doesn't exist
-	DO $$ 
-	BEGIN 
-		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='matrix_records' AND column_name='inverse_matrix_hash') THEN
-			ALTER TABLE matrix_records ADD COLUMN inverse_matrix_hash VARCHAR(32);
-		END IF;
-	END $$;
-	`
-
-	_, err := database.Exec(migrationSQL)
-	if err != nil {
-		log.Printf("Migration hatası (devam ediliyor): %v", err)
-	}
-
-	createTableSQL := `
-	-- Create matrix_records table
-	CREATE TABLE IF NOT EXISTS matrix_records (
-		id SERIAL PRIMARY KEY,
-		title VARCHAR(255) NOT NULL,
-		group_name VARCHAR(255),
-		matrix_binary TEXT NOT NULL,
-		matrix_hex TEXT NOT NULL,
-		ham_xor_count INTEGER NOT NULL,
-		smallest_xor INTEGER,
-		boyar_xor_count INTEGER,
-		boyar_depth INTEGER,
-		boyar_program TEXT,
-		paar_xor_count INTEGER,
-		paar_program TEXT,
-		slp_xor_count INTEGER,
-		slp_program TEXT,
-		matrix_hash VARCHAR(32) NOT NULL UNIQUE,
-		inverse_matrix_id INTEGER,
-		inverse_matrix_hash VARCHAR(32),
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	-- Create indexes for better performance
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_hash ON matrix_records(matrix_hash);
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_title ON matrix_records(title);
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_group ON matrix_records(group_name);
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_ham_xor ON matrix_records(ham_xor_count);
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_smallest_xor ON matrix_records(smallest_xor);
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_boyar_xor ON matrix_records(boyar_xor_count);
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_paar_xor ON matrix_records(paar_xor_count);
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_slp_xor ON matrix_records(slp_xor_count);
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_inverse_id ON matrix_records(inverse_matrix_id);
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_inverse_hash ON matrix_records(inverse_matrix_hash);
-	CREATE INDEX IF NOT EXISTS idx_matrix_records_created_at ON matrix_records(created_at);
-	`
-
-	_, err = database.Exec(createTableSQL)
-	if err != nil {
-		return fmt.Errorf("tablo oluşturma hatası: %v", err)
-	}
-
-	log.Printf("Veritabanı tabloları başarıyla oluşturuldu/kontrol edildi")
-	
-	// Update smallest_xor for existing records
-	go func() {
-		time.Sleep(2 * time.Second) // Wait for database to be ready
-		updateSmallestXorForExistingRecords(database)
-	}()
-	
-	return nil
-}
-
-// updateSmallestXorForExistingRecords updates smallest_xor for existing records
-func updateSmallestXorForExistingRecords(database *sql.DB) {
-	log.Printf("Mevcut kayıtlar için smallest_xor değerleri güncelleniyor...")
-	
-	query := `
-	UPDATE matrix_records 
-	SET smallest_xor = (
-		SELECT MIN(xor_value) FROM (
-			SELECT COALESCE(boyar_xor_count, 999999) as xor_value
-			UNION ALL
-			SELECT COALESCE(paar_xor_count, 999999) as xor_value
-			UNION ALL
-			SELECT COALESCE(slp_xor_count, 999999) as xor_value
-		) AS xor_values
-		WHERE xor_value < 999999
-	)
-	WHERE smallest_xor IS NULL 
-	AND (boyar_xor_count IS NOT NULL OR paar_xor_count IS NOT NULL OR slp_xor_count IS NOT NULL)
-	`
-	
-	result, err := database.Exec(query)
-	if err != nil {
-		log.Printf("Smallest XOR güncelleme hatası: %v", err)
-		return
+		return NewSQLiteDatabase(path)
+	case DriverMemory:
+		return nil, fmt.Errorf("memory sürücüsü bir SQL veritabanı değil")
 	}
-	
-	rowsAffected, _ := result.RowsAffected()
-	log.Printf("✓ %d kayıt için smallest_xor değeri güncellendi", rowsAffected)
+	return nil, fmt.Errorf("desteklenmeyen veritabanı sürücüsü: %s (postgres, sqlite veya memory)", driver)
 }
 
-// InitDatabase initializes the database connection
-func InitDatabase(config *Config) error {
+// databaseConnectionString builds the connection string from config; DB_*
+// environment variables override the configured values
+func databaseConnectionString(config *Config) string {
 	// Build connection string from config
 	connectionString := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		config.Database.Host, config.Database.Port, config.Database.User,
@@ -1188,28 +2334,60 @@ func InitDatabase(config *Config) error {
 	if sslmode := os.Getenv("DB_SSLMODE"); sslmode != "" {
 		connectionString = strings.Replace(connectionString, "sslmode="+config.Database.SSLMode, "sslmode="+sslmode, 1)
 	}
+	return connectionString
+}
 
+// InitDatabase initializes the database connection
+func InitDatabase(config *Config) error {
 	log.Printf("🔗 [DB] Veritabanına bağlanılıyor...")
 
-	var err error
-	db, err = NewDatabase(connectionString)
+	store, err := openStore(config)
 	if err != nil {
 		return fmt.Errorf("veritabanı bağlantısı kurulamadı: %v", err)
 	}
+	db = store
 
-	log.Printf("✅ [DB] Veritabanı bağlantısı başarılı")
+	log.Printf("✅ [DB] Veritabanı bağlantısı başarılı (%s)", storageDriver(config))
 
-	// Create tables if they don't exist
-	err = createTables(db.db)
-	if err != nil {
-		return fmt.Errorf("veritabanı tabloları oluşturulamadı: %v", err)
+	if database, ok := store.(*Database); ok {
+		// Refuse a schema migrated by a newer release, then apply pending migrations
+		if err := checkSchemaVersion(database); err != nil {
+			return err
+		}
+		applied, err := MigrateUp(database, 0)
+		if err != nil {
+			return fmt.Errorf("veritabanı migration hatası: %v", err)
+		}
+		log.Printf("✅ [DB] Veritabanı şeması güncel (%d migration uygulandı)", applied)
+
+		// Analyze matrices stored before the analysis and canonical_hash columns existed
+		go func() {
+			database.fillCanonicalHashes()
+			database.analyzeExistingRecords()
+		}()
+	} else {
+		log.Printf("⚠️  [DB] Bellek içi depolama kullanılıyor, veriler kapanışta kaybolacak")
+	}
+
+	if config != nil {
+		solverTimeout = time.Duration(config.Import.SolverTimeoutSeconds) * time.Second
+		if config.Import.BoyarDepthLimit > 0 {
+			boyarDepthLimit = config.Import.BoyarDepthLimit
+		}
 	}
 
 	// Initialize algorithm worker pool
 	log.Printf("🔧 [WORKER] Algorithm worker pool başlatılıyor...")
-	InitAlgorithmWorkerPool()
+	algorithms := DefaultAlgorithms()
+	if config != nil && len(config.Import.Algorithms) > 0 {
+		algorithms = config.Import.Algorithms
+	}
+	InitAlgorithmWorkerPool(store, algorithms)
 	log.Printf("✅ [WORKER] Algorithm worker pool başlatıldı")
 
+	// Continue generator jobs interrupted by the last shutdown
+	go ResumeGeneratorJobs(store)
+
 	// Auto import data if enabled
 	if config != nil && config.Import.Enabled && config.Import.ProcessOnStart {
 		go func() {
@@ -1232,7 +2410,7 @@ func InitDatabase(config *Config) error {
 
 			// Get hashes from database
 			dbHashStartTime := time.Now()
-			dbHashes, err := db.GetAllMatrixHashes()
+			dbHashes, err := store.GetAllMatrixHashes()
 			dbHashDuration := time.Since(dbHashStartTime)
 			if err != nil {
 				log.Printf("❌ [AUTO-IMPORT] Veritabanı hash'leri alınamadı (%v): %v", dbHashDuration, err)
@@ -1242,7 +2420,7 @@ func InitDatabase(config *Config) error {
 
 			// Get hashes from files
 			fileHashStartTime := time.Now()
-			fileHashes, err := db.GetFileMatrixHashes(dataPath)
+			fileHashes, err := GetFileMatrixHashes(dataPath)
 			fileHashDuration := time.Since(fileHashStartTime)
 			if err != nil {
 				log.Printf("❌ [AUTO-IMPORT] Dosya hash'leri alınamadı (%v): %v", fileHashDuration, err)
@@ -1267,7 +2445,7 @@ func InitDatabase(config *Config) error {
 			if missingCount > 0 {
 				log.Printf("🚀 [AUTO-IMPORT] Veritabanında %d eksik matris var, import işlemi başlatılıyor...", missingCount)
 				importStartTime := time.Now()
-				err := db.ImportMatricesFromFiles(dataPath)
+				err := ImportMatricesFromFiles(store, dataPath)
 				importDuration := time.Since(importStartTime)
 				if err != nil {
 					log.Printf("❌ [AUTO-IMPORT] Matris import işlemi başarısız (%v): %v", importDuration, err)
@@ -1296,13 +2474,7 @@ func MatrixExists(name string) (bool, error) {
 		return false, fmt.Errorf("veritabanı bağlantısı yok")
 	}
 
-	var count int
-	err := db.db.QueryRow("SELECT COUNT(*) FROM matrix_records WHERE title = $1", name).Scan(&count)
-	if err != nil {
-		return false, err
-	}
-
-	return count > 0, nil
+	return db.TitleExists(name)
 }
 
 // SaveMatrixToDB saves a matrix to database and returns the ID
@@ -1311,7 +2483,7 @@ func SaveMatrixToDB(name string, matrix Matrix, source string) (int, error) {
 		return 0, fmt.Errorf("veritabanı bağlantısı yok")
 	}
 
-	record, err := db.SaveMatrix(name, matrix, "")
+	record, err := SaveMatrix(db, name, matrix, "")
 	if err != nil {
 		return 0, err
 	}
@@ -1325,35 +2497,20 @@ func CalculateAndSaveAlgorithm(matrixID int, matrix Matrix, algorithm string) er
 		return fmt.Errorf("veritabanı bağlantısı yok")
 	}
 
-	var boyarResult, paarResult, slpResult *AlgResult
-
-	switch strings.ToLower(algorithm) {
-	case "boyar":
-		result, err := runBoyarSLP(matrix)
-		if err != nil {
-			return fmt.Errorf("boyar algoritması hatası: %v", err)
-		}
-		boyarResult = result
-
-	case "paar":
-		result, err := runPaarAlgorithm(matrix)
-		if err != nil {
-			return fmt.Errorf("paar algoritması hatası: %v", err)
-		}
-		paarResult = result
+	info, ok := GetSolverInfo(algorithm)
+	if !ok {
+		return fmt.Errorf("desteklenmeyen algoritma: %s", algorithm)
+	}
 
-	case "slp":
-		result, err := runSLPHeuristic(matrix)
-		if err != nil {
-			return fmt.Errorf("slp algoritması hatası: %v", err)
-		}
-		slpResult = result
+	ctx, cancel := withSolverDeadline(context.Background(), 0)
+	defer cancel()
 
-	default:
-		return fmt.Errorf("desteklenmeyen algoritma: %s", algorithm)
+	result, err := runSolver(ctx, info.Name, nil, matrix)
+	if err != nil {
+		return fmt.Errorf("%s algoritması hatası: %v", info.Name, err)
 	}
 
-	return db.UpdateMatrixResults(matrixID, boyarResult, paarResult, slpResult)
+	return UpdateMatrixResults(db, matrixID, map[string]*AlgResult{info.Name: result})
 }
 
 // calculateMatrixInverse calculates the inverse of a binary matrix using Gaussian elimination
@@ -1433,9 +2590,9 @@ func calculateMatrixInverse(matrix Matrix) (Matrix, error) {
 }
 
 // SaveMatrixInverse calculates and saves the inverse of a matrix
-func (d *Database) SaveMatrixInverse(originalID int) (*MatrixRecord, error) {
+func SaveMatrixInverse(store MatrixStore, originalID int) (*MatrixRecord, error) {
 	// Get original matrix
-	original, err := d.GetMatrixByID(originalID)
+	original, err := store.GetMatrixByID(originalID)
 	if err != nil {
 		return nil, fmt.Errorf("orijinal matris alınamadı: %v", err)
 	}
@@ -1447,7 +2604,7 @@ func (d *Database) SaveMatrixInverse(originalID int) (*MatrixRecord, error) {
 	// Check if inverse already exists
 	if original.InverseMatrixID != nil {
 		// Return existing inverse
-		return d.GetMatrixByID(*original.InverseMatrixID)
+		return store.GetMatrixByID(*original.InverseMatrixID)
 	}
 	
 	// Parse matrix from binary string
@@ -1467,10 +2624,10 @@ func (d *Database) SaveMatrixInverse(originalID int) (*MatrixRecord, error) {
 	
 	// Check if inverse already exists by hash
 	inverseHash := calculateMatrixHash(inverse)
-	existing, err := d.GetMatrixByHash(inverseHash)
+	existing, err := store.GetMatrixByHash(inverseHash)
 	if err == nil && existing != nil {
 		// Update original matrix with inverse reference
-		err = d.updateMatrixInverseReference(originalID, existing.ID, inverseHash)
+		err = store.SetInverseReference(originalID, existing.ID, inverseHash)
 		if err != nil {
 			log.Printf("❌ Orijinal matrise ters matris referansı eklenemedi: %v", err)
 		}
@@ -1478,59 +2635,30 @@ func (d *Database) SaveMatrixInverse(originalID int) (*MatrixRecord, error) {
 	}
 	
 	// Save inverse matrix
-	inverseRecord, err := d.SaveMatrix(inverseTitle, inverse, original.Group)
+	inverseRecord, err := SaveMatrix(store, inverseTitle, inverse, original.Group)
 	if err != nil {
 		return nil, fmt.Errorf("ters matris kaydedilemedi: %v", err)
 	}
 	
 	// Update original matrix with inverse reference
-	err = d.updateMatrixInverseReference(originalID, inverseRecord.ID, inverseHash)
+	err = store.SetInverseReference(originalID, inverseRecord.ID, inverseHash)
 	if err != nil {
 		log.Printf("❌ Orijinal matrise ters matris referansı eklenemedi: %v", err)
 	}
 	
-	// Calculate algorithms for inverse matrix in background
-	go func() {
-		log.Printf("🔄 [INVERSE] %s için algoritma hesaplamaları başlıyor", inverseTitle)
-		
-		// Calculate Boyar SLP
-		boyarResult, err := runBoyarSLP(inverse)
-		if err != nil {
-			log.Printf("❌ [INVERSE-BOYAR] %s için Boyar SLP hesaplanamadı: %v", inverseTitle, err)
-		} else {
-			log.Printf("✅ [INVERSE-BOYAR] %s için Boyar SLP tamamlandı - XOR: %d", inverseTitle, boyarResult.XorCount)
-		}
-
-		// Calculate Paar Algorithm
-		paarResult, err := runPaarAlgorithm(inverse)
-		if err != nil {
-			log.Printf("❌ [INVERSE-PAAR] %s için Paar algoritması hesaplanamadı: %v", inverseTitle, err)
-		} else {
-			log.Printf("✅ [INVERSE-PAAR] %s için Paar algoritması tamamlandı - XOR: %d", inverseTitle, paarResult.XorCount)
-		}
-
-		// Calculate SLP Heuristic
-		slpResult, err := runSLPHeuristic(inverse)
-		if err != nil {
-			log.Printf("❌ [INVERSE-SLP] %s için SLP Heuristic hesaplanamadı: %v", inverseTitle, err)
-		} else {
-			log.Printf("✅ [INVERSE-SLP] %s için SLP Heuristic tamamlandı - XOR: %d", inverseTitle, slpResult.XorCount)
-		}
-
-		// Update matrix with results
-		err = d.UpdateMatrixResults(inverseRecord.ID, boyarResult, paarResult, slpResult)
-		if err != nil {
-			log.Printf("❌ [INVERSE-UPDATE] %s için sonuçlar kaydedilemedi: %v", inverseTitle, err)
-		} else {
-			log.Printf("✅ [INVERSE-UPDATE] %s için sonuçlar kaydedildi", inverseTitle)
-		}
-	}()
+	// Queue the algorithms for the inverse matrix
+	job := &AlgorithmJob{MatrixID: inverseRecord.ID, Source: JobSourceInverse, Algorithms: DefaultAlgorithms(), Priority: jobPriorityUser}
+	if err := EnqueueAlgorithmJob(store, job); err != nil {
+		log.Printf("❌ [INVERSE] %s: %v", inverseTitle, err)
+	} else {
+		log.Printf("🔄 [INVERSE] %s için algoritma işi %d kuyruğa eklendi", inverseTitle, job.ID)
+	}
 	
 	return inverseRecord, nil
 }
 
-// updateMatrixInverseReference updates the original matrix with inverse reference
-func (d *Database) updateMatrixInverseReference(originalID, inverseID int, inverseHash string) error {
+// SetInverseReference points the original matrix at its stored inverse
+func (d *Database) SetInverseReference(originalID, inverseID int, inverseHash string) error {
 	query := `
 	UPDATE matrix_records 
 	SET inverse_matrix_id = $1, inverse_matrix_hash = $2, updated_at = CURRENT_TIMESTAMP
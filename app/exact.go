@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+)
+
+// defaultExactMaxNodes bounds the exact search when no budget is requested
+const defaultExactMaxNodes = 2000000
+
+// maxExactDimension is the largest number of rows or columns the exact
+// search accepts; beyond it even the lower levels are out of reach
+const maxExactDimension = 16
+
+// ExactSolver searches for a program with the minimum number of XOR gates.
+// The SLP heuristic gives an upper bound; iterative deepening then tries
+// every gate count below it. A level is pruned with the Boyar distances of
+// SLPHeuristic: a target at distance d needs at least d more gates, and
+// every missing target needs a gate of its own. Independent gates are only
+// tried in one order. A completed search proves the result optimal
+// (StatusCompleted); when the budget runs out the best program so far is
+// returned with StatusTimedOut. LowerBound is the gate count of the first
+// level not yet refuted, equal to XorCount once the search completes.
+type ExactSolver struct {
+	MaxNodes int // Search budget: maximum number of visited search nodes
+
+	slp       *SLPHeuristic // Base, Dist and reachable of the search path
+	ctx       context.Context
+	nodes     int
+	exhausted bool
+	pairs     [][2]int // Gates of the search path as base indexes
+}
+
+func NewExactSolver(maxNodes int) *ExactSolver {
+	if maxNodes <= 0 {
+		maxNodes = defaultExactMaxNodes
+	}
+	return &ExactSolver{MaxNodes: maxNodes}
+}
+
+// Name returns the registry name of the solver
+func (e *ExactSolver) Name() string {
+	return "exact"
+}
+
+// Parameters returns the effective solver parameters
+func (e *ExactSolver) Parameters() SolverParams {
+	return SolverParams{"max_nodes": e.MaxNodes}
+}
+
+func (e *ExactSolver) Solve(ctx context.Context, matrix Matrix) (AlgResult, error) {
+	if len(matrix) > maxExactDimension || (len(matrix) > 0 && len(matrix[0]) > maxExactDimension) {
+		return AlgResult{}, fmt.Errorf("kesin arama en fazla %dx%d matrisleri destekler", maxExactDimension, maxExactDimension)
+	}
+
+	upper, err := NewSLPHeuristic().Solve(ctx, matrix)
+	if err != nil {
+		return AlgResult{}, err
+	}
+
+	e.slp = NewSLPHeuristic()
+	if err := e.slp.ReadTargetMatrix(matrix); err != nil {
+		return AlgResult{}, err
+	}
+	if err := e.slp.InitBase(); err != nil {
+		return AlgResult{}, err
+	}
+	e.slp.ctx = ctx
+	e.ctx = ctx
+	e.nodes = 0
+	e.exhausted = false
+
+	for bound := e.lowerBound(); bound < upper.XorCount; bound++ {
+		e.pairs = e.pairs[:0]
+		if e.search(bound) {
+			log.Printf("[EXACT] %d XOR ile optimal program bulundu (sezgisel: %d, %d düğüm)", bound, upper.XorCount, e.nodes)
+			return AlgResult{
+				XorCount:   bound,
+				Program:    e.buildProgram(),
+				Status:     StatusCompleted,
+				LowerBound: bound,
+			}, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			if !deadlineExceeded(err) {
+				return AlgResult{}, err
+			}
+			log.Printf("[EXACT] Süre doldu (%d düğüm): optimal XOR %d ile %d arasında", e.nodes, bound, upper.XorCount)
+			return e.unproven(upper, bound), nil
+		}
+		if e.exhausted {
+			log.Printf("[EXACT] Arama bütçesi (%d düğüm) doldu: optimal XOR %d ile %d arasında", e.MaxNodes, bound, upper.XorCount)
+			return e.unproven(upper, bound), nil
+		}
+	}
+
+	log.Printf("[EXACT] Sezgisel sonuç optimal: %d XOR (%d düğüm)", upper.XorCount, e.nodes)
+	upper.Status = StatusCompleted
+	upper.LowerBound = upper.XorCount
+	return upper, nil
+}
+
+// unproven returns the heuristic result of a search that ran out of budget
+// while refuting programs with bound gates
+func (e *ExactSolver) unproven(upper AlgResult, bound int) AlgResult {
+	upper.Status = StatusTimedOut
+	upper.LowerBound = bound
+	return upper
+}
+
+// lowerBound returns the gates the current base needs at least: the largest
+// target distance, and one gate per distinct missing target
+func (e *ExactSolver) lowerBound() int {
+	maxDist, missing := 0, 0
+	for i := 0; i < e.slp.NumTargets; i++ {
+		if e.slp.Dist[i] > maxDist {
+			maxDist = e.slp.Dist[i]
+		}
+		if e.slp.Dist[i] > 0 && !e.duplicateTarget(i) {
+			missing++
+		}
+	}
+	if missing > maxDist {
+		return missing
+	}
+	return maxDist
+}
+
+// distanceBound is lowerBound for the base extended by a candidate with the
+// given distances, so hopeless candidates are dropped before recursing
+func (e *ExactSolver) distanceBound(dist []int) int {
+	saved := append([]int(nil), e.slp.Dist[:e.slp.NumTargets]...)
+	copy(e.slp.Dist, dist)
+	bound := e.lowerBound()
+	copy(e.slp.Dist, saved)
+	return bound
+}
+
+// duplicateTarget reports whether an earlier target equals target i
+func (e *ExactSolver) duplicateTarget(i int) bool {
+	for j := 0; j < i; j++ {
+		if e.slp.Target[j].Equal(e.slp.Target[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// exactCandidate is a new base element with the distances it leads to
+type exactCandidate struct {
+	pair     [2]int
+	value    BitVector
+	dist     []int
+	total    int
+	isTarget bool
+}
+
+// search reports whether the targets can be completed with at most
+// remaining more gates, leaving the gates of a solution in pairs
+func (e *ExactSolver) search(remaining int) bool {
+	s := e.slp
+	if s.countFound() == s.NumTargets {
+		return true
+	}
+	bound := e.lowerBound()
+	if bound > remaining {
+		return false
+	}
+	if e.nodes >= e.MaxNodes {
+		e.exhausted = true
+		return false
+	}
+	if s.stopped || e.ctx.Err() != nil {
+		return false
+	}
+	e.nodes++
+
+	// With as many gates left as missing targets every gate must be a target
+	onlyTargets := bound == remaining && remaining == e.missingTargets()
+
+	var candidates []exactCandidate
+	for i := 0; i < s.BaseSize-1; i++ {
+		for j := i + 1; j < s.BaseSize; j++ {
+			if !e.canonicalOrder(i, j) {
+				continue
+			}
+			value := s.Base[i].Xor(s.Base[j])
+			if value.IsZero() || s.isBase(value) {
+				continue
+			}
+			isTarget := s.isTarget(value)
+			if onlyTargets && !isTarget {
+				continue
+			}
+			total := s.TotalDistance(value)
+			if s.stopped {
+				return false
+			}
+			dist := append([]int(nil), s.NDist[:s.NumTargets]...)
+			if e.distanceBound(dist) > remaining-1 {
+				continue
+			}
+			candidates = append(candidates, exactCandidate{
+				pair:     [2]int{i, j},
+				value:    value,
+				dist:     dist,
+				total:    total,
+				isTarget: isTarget,
+			})
+		}
+	}
+
+	// Closest candidates first, so solutions turn up early
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if candidates[a].total != candidates[b].total {
+			return candidates[a].total < candidates[b].total
+		}
+		return candidates[a].isTarget && !candidates[b].isTarget
+	})
+
+	saved := append([]int(nil), s.Dist[:s.NumTargets]...)
+	for _, candidate := range candidates {
+		copy(s.Dist, candidate.dist)
+		s.Base[s.BaseSize] = candidate.value
+		s.BaseSize++
+		e.pairs = append(e.pairs, candidate.pair)
+
+		if e.search(remaining - 1) {
+			return true
+		}
+
+		e.pairs = e.pairs[:len(e.pairs)-1]
+		s.BaseSize--
+		copy(s.Dist, saved)
+		if e.exhausted || s.stopped || e.ctx.Err() != nil {
+			return false
+		}
+	}
+	return false
+}
+
+// missingTargets returns the number of distinct targets not in the base
+func (e *ExactSolver) missingTargets() int {
+	missing := 0
+	for i := 0; i < e.slp.NumTargets; i++ {
+		if e.slp.Dist[i] > 0 && !e.duplicateTarget(i) {
+			missing++
+		}
+	}
+	return missing
+}
+
+// canonicalOrder reports whether gate (i, j) may follow the last gate of the
+// path. Two consecutive gates that do not depend on each other can be
+// swapped, so only the order with the smaller pair first is searched: a gate
+// either uses the previous gate or has a larger pair.
+func (e *ExactSolver) canonicalOrder(i, j int) bool {
+	if len(e.pairs) == 0 {
+		return true
+	}
+	last := e.pairs[len(e.pairs)-1]
+	if j == e.slp.BaseSize-1 {
+		return true
+	}
+	return i > last[0] || (i == last[0] && j > last[1])
+}
+
+// buildProgram converts the gates of the search path into a Program
+func (e *ExactSolver) buildProgram() *Program {
+	s := e.slp
+	program := NewProgram(s.NumInputs, s.NumTargets)
+	for _, pair := range e.pairs {
+		program.AddGate(pair[0], pair[1])
+	}
+	program.ResolveOutputs(s.Target[:s.NumTargets])
+	return program
+}
+
+func init() {
+	RegisterSolver(SolverInfo{
+		Name:  "exact",
+		Label: "Exact",
+		Factory: func(params SolverParams) (Solver, error) {
+			return NewExactSolver(params.Int("max_nodes", defaultExactMaxNodes)), nil
+		},
+	})
+}
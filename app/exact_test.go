@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// randomMatrix returns a rows×columns matrix with each entry set with probability density
+func randomMatrix(rng *rand.Rand, rows, columns int, density float64) Matrix {
+	matrix := make(Matrix, rows)
+	for i := range matrix {
+		matrix[i] = make([]string, columns)
+		for j := range matrix[i] {
+			matrix[i][j] = "0"
+			if rng.Float64() < density {
+				matrix[i][j] = "1"
+			}
+		}
+	}
+	return matrix
+}
+
+func TestExactSolverKnownOptimum(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix Matrix
+		xor    int
+	}{
+		{"verify", verifyMatrix, 3},
+		{"single row", Matrix{wideRow(4, 0, 1, 2, 3)}, 3},
+		{"shared pair", Matrix{wideRow(4, 0, 1, 2), wideRow(4, 0, 1, 3)}, 3},
+		{"identity", Matrix{wideRow(2, 0), wideRow(2, 1)}, 0},
+	}
+
+	for _, tt := range tests {
+		result, err := runSolver(context.Background(), "exact", nil, tt.matrix)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if result.Status != StatusCompleted || result.XorCount != tt.xor || result.LowerBound != tt.xor {
+			t.Errorf("%s: %d XOR, alt sınır %d (%s), beklenen %d", tt.name, result.XorCount, result.LowerBound, result.Status, tt.xor)
+		}
+		if v := VerifyProgram(tt.matrix, result.Program); !v.Valid {
+			t.Errorf("%s: program doğrulanamadı: %v", tt.name, v.Errors)
+		}
+	}
+}
+
+func TestExactSolverBeatsHeuristics(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for round := 0; round < 8; round++ {
+		matrix := randomMatrix(rng, 5, 5, 0.5)
+		result, err := runSolver(context.Background(), "exact", nil, matrix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Status != StatusCompleted || result.LowerBound != result.XorCount {
+			t.Fatalf("tur %d: arama tamamlanmadı (%s, alt sınır %d)", round, result.Status, result.LowerBound)
+		}
+		v := VerifyProgram(matrix, result.Program)
+		v.CheckClaims(&result.XorCount, nil)
+		if !v.Valid {
+			t.Fatalf("tur %d: program doğrulanamadı: %v", round, v.Errors)
+		}
+		for _, name := range []string{"paar", "slp", "boyar"} {
+			heuristic, err := runSolver(context.Background(), name, nil, matrix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Compare programs: Paar's count of zero and repeated rows is not its gate count
+			if gates := heuristic.Program.XorCount(); gates < result.XorCount {
+				t.Errorf("tur %d: %s %d XOR buldu, kesin arama %d", round, name, gates, result.XorCount)
+			}
+		}
+	}
+}
+
+func TestExactSolverBudget(t *testing.T) {
+	matrix := randomMatrix(rand.New(rand.NewSource(5)), 8, 8, 0.5)
+
+	// An exhausted node budget returns the heuristic program with the bound reached so far
+	solver := NewExactSolver(1)
+	result, err := solver.Solve(context.Background(), matrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != StatusTimedOut || result.LowerBound <= 0 || result.LowerBound >= result.XorCount {
+		t.Errorf("bütçe: %d XOR, alt sınır %d (%s)", result.XorCount, result.LowerBound, result.Status)
+	}
+	if v := VerifyProgram(matrix, result.Program); !v.Valid {
+		t.Errorf("bütçe: program doğrulanamadı: %v", v.Errors)
+	}
+
+	// A passed deadline stops the search the same way
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	result, err = NewExactSolver(0).Solve(ctx, matrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != StatusTimedOut || result.LowerBound >= result.XorCount {
+		t.Errorf("süre: %d XOR, alt sınır %d (%s)", result.XorCount, result.LowerBound, result.Status)
+	}
+
+	if _, err := NewExactSolver(0).Solve(context.Background(), Matrix{wideRow(17, 0, 16)}); err == nil {
+		t.Error("17 sütunlu matris kabul edildi")
+	}
+}
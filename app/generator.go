@@ -0,0 +1,413 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Generator job statuses
+const (
+	GeneratorRunning   = "running"
+	GeneratorPaused    = "paused"
+	GeneratorCompleted = "completed" // Enumeration exhausted or max_results reached
+	GeneratorFailed    = "failed"
+)
+
+const (
+	maxGeneratorDimension     = 8               // Largest n whose MDS check fits analysisSearchBudget comfortably
+	defaultGeneratorResults   = 100             // max_results when the request gives none
+	generatorProgressInterval = 5 * time.Second // How often a running job stores its cursor
+)
+
+// ConstructionFamily enumerates the candidate n×n matrices of one
+// construction over a field with q elements. A cursor holds the parameters of
+// one candidate; jobs store the cursor of the next candidate so the
+// enumeration can resume after a pause or restart.
+type ConstructionFamily struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	About string `json:"about"`
+
+	validate func(n int, q uint32) error
+	start    func(n int) []uint32
+	next     func(cursor []uint32, n int, q uint32) bool // Advances cursor, false after the last candidate
+	build    func(field *GF2m, n int, cursor []uint32) FieldMatrix
+	total    func(n int, q uint32) float64 // Number of cursors
+}
+
+// constructionFamilies lists the supported families in API order
+var constructionFamilies = []*ConstructionFamily{
+	{
+		Name:     "circulant",
+		Label:    "Circulant",
+		About:    "Row i is the first row rotated right by i; the first row ranges over non-zero elements",
+		validate: func(n int, q uint32) error { return nil },
+		start:    func(n int) []uint32 { return firstDigits(n) },
+		next:     func(cursor []uint32, n int, q uint32) bool { return advanceDigits(cursor, q) },
+		build: func(field *GF2m, n int, a []uint32) FieldMatrix {
+			return buildField(n, func(i, j int) uint32 { return a[(j-i+n)%n] })
+		},
+		total: func(n int, q uint32) float64 { return math.Pow(float64(q-1), float64(n)) },
+	},
+	{
+		Name:  "hadamard",
+		Label: "Hadamard",
+		About: "Entry (i, j) is a[i xor j] for n a power of two; a ranges over non-zero elements",
+		validate: func(n int, q uint32) error {
+			if n&(n-1) != 0 {
+				return fmt.Errorf("hadamard için boyut 2'nin kuvveti olmalı: %d", n)
+			}
+			return nil
+		},
+		start: func(n int) []uint32 { return firstDigits(n) },
+		next:  func(cursor []uint32, n int, q uint32) bool { return advanceDigits(cursor, q) },
+		build: func(field *GF2m, n int, a []uint32) FieldMatrix {
+			return buildField(n, func(i, j int) uint32 { return a[i^j] })
+		},
+		total: func(n int, q uint32) float64 { return math.Pow(float64(q-1), float64(n)) },
+	},
+	{
+		Name:     "toeplitz",
+		Label:    "Toeplitz",
+		About:    "Entry (i, j) is a[j-i+n-1], constant along diagonals; the 2n-1 diagonals range over non-zero elements",
+		validate: func(n int, q uint32) error { return nil },
+		start:    func(n int) []uint32 { return firstDigits(2*n - 1) },
+		next:     func(cursor []uint32, n int, q uint32) bool { return advanceDigits(cursor, q) },
+		build: func(field *GF2m, n int, a []uint32) FieldMatrix {
+			return buildField(n, func(i, j int) uint32 { return a[j-i+n-1] })
+		},
+		total: func(n int, q uint32) float64 { return math.Pow(float64(q-1), float64(2*n-1)) },
+	},
+	{
+		Name:  "cauchy",
+		Label: "Cauchy",
+		About: "Entry (i, j) is 1/(x[i]+y[j]) for increasing x and y with no common element; every such matrix is MDS",
+		validate: func(n int, q uint32) error {
+			if uint32(2*n) > q {
+				return fmt.Errorf("cauchy için alan en az %d eleman içermeli", 2*n)
+			}
+			return nil
+		},
+		start: func(n int) []uint32 {
+			cursor := make([]uint32, 2*n)
+			for i := 0; i < n; i++ {
+				cursor[i], cursor[n+i] = uint32(i), uint32(i)
+			}
+			return cursor
+		},
+		next: func(cursor []uint32, n int, q uint32) bool {
+			if advanceCombination(cursor[n:], q) {
+				return true
+			}
+			for i := 0; i < n; i++ {
+				cursor[n+i] = uint32(i)
+			}
+			return advanceCombination(cursor[:n], q)
+		},
+		build: func(field *GF2m, n int, c []uint32) FieldMatrix {
+			x, y := c[:n], c[n:]
+			for _, a := range x {
+				for _, b := range y {
+					if a == b {
+						return nil
+					}
+				}
+			}
+			return buildField(n, func(i, j int) uint32 { return field.Inv(x[i] ^ y[j]) })
+		},
+		total: func(n int, q uint32) float64 {
+			combinations := 1.0
+			for i := 0; i < n; i++ {
+				combinations = combinations * float64(q-uint32(i)) / float64(i+1)
+			}
+			return combinations * combinations
+		},
+	},
+}
+
+// GetConstructionFamily returns the family with the given name
+func GetConstructionFamily(name string) (*ConstructionFamily, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, family := range constructionFamilies {
+		if family.Name == name {
+			return family, true
+		}
+	}
+	return nil, false
+}
+
+// firstDigits returns k digits of 1, the first cursor of digit families
+func firstDigits(k int) []uint32 {
+	digits := make([]uint32, k)
+	for i := range digits {
+		digits[i] = 1
+	}
+	return digits
+}
+
+// advanceDigits steps digits in 1..q-1 like an odometer, last digit fastest
+func advanceDigits(digits []uint32, q uint32) bool {
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] < q-1 {
+			digits[i]++
+			return true
+		}
+		digits[i] = 1
+	}
+	return false
+}
+
+// advanceCombination steps an increasing sequence over 0..q-1 to the next one
+// in lexicographic order
+func advanceCombination(c []uint32, q uint32) bool {
+	k := len(c)
+	i := k - 1
+	for i >= 0 && c[i] == q-uint32(k-i) {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	c[i]++
+	for j := i + 1; j < k; j++ {
+		c[j] = c[j-1] + 1
+	}
+	return true
+}
+
+// buildField returns the n×n matrix with the given entries
+func buildField(n int, entry func(i, j int) uint32) FieldMatrix {
+	m := make(FieldMatrix, n)
+	for i := range m {
+		m[i] = make([]uint32, n)
+		for j := range m[i] {
+			m[i][j] = entry(i, j)
+		}
+	}
+	return m
+}
+
+// GeneratorJob enumerates one construction family over a field and stores
+// the MDS candidates it finds in matrix_records
+type GeneratorJob struct {
+	ID              int       `json:"id"`
+	Family          string    `json:"family"`
+	Polynomial      string    `json:"polynomial"`
+	FieldDegree     int       `json:"field_degree"`
+	Dimension       int       `json:"dimension"`
+	Algorithms      []string  `json:"algorithms"`       // Solvers queued for every new matrix
+	MaxResults      int       `json:"max_results"`      // The job completes after this many new matrices
+	Cursor          []uint32  `json:"cursor,omitempty"` // Parameters of the next candidate, nil once the enumeration is exhausted
+	Examined        int64     `json:"examined"`         // Cursors examined so far
+	CandidatesTotal float64   `json:"candidates_total"` // Size of the enumeration
+	Found           int       `json:"found"`            // Candidates that passed the MDS check
+	Inserted        int       `json:"inserted"`         // New matrix_records rows
+	Duplicates      int       `json:"duplicates"`       // MDS candidates already stored under their matrix_hash
+	Status          string    `json:"status"`
+	Error           *string   `json:"error,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// snapshot returns a copy of the job that the running enumeration does not modify
+func (job *GeneratorJob) snapshot() *GeneratorJob {
+	copied := *job
+	copied.Cursor = append([]uint32(nil), job.Cursor...)
+	copied.Algorithms = append([]string(nil), job.Algorithms...)
+	return &copied
+}
+
+// newGeneratorJob validates a job request and returns the job at its first candidate
+func newGeneratorJob(familyName, polynomial string, dimension, maxResults int, algorithms []string) (*GeneratorJob, error) {
+	family, ok := GetConstructionFamily(familyName)
+	if !ok {
+		return nil, fmt.Errorf("desteklenmeyen yapı ailesi: %s", familyName)
+	}
+	field, err := NewGF2m(polynomial)
+	if err != nil {
+		return nil, err
+	}
+	if dimension < 2 || dimension > maxGeneratorDimension {
+		return nil, fmt.Errorf("boyut 2 ile %d arasında olmalı: %d", maxGeneratorDimension, dimension)
+	}
+	q := uint32(1) << uint(field.M)
+	if err := family.validate(dimension, q); err != nil {
+		return nil, err
+	}
+	if maxResults <= 0 {
+		maxResults = defaultGeneratorResults
+	}
+	algorithms, err = normalizeAlgorithms(algorithms)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeneratorJob{
+		Family:          family.Name,
+		Polynomial:      field.Polynomial(),
+		FieldDegree:     field.M,
+		Dimension:       dimension,
+		Algorithms:      algorithms,
+		MaxResults:      maxResults,
+		Cursor:          family.start(dimension),
+		CandidatesTotal: family.total(dimension, q),
+		Status:          GeneratorRunning,
+	}, nil
+}
+
+var (
+	generatorRunsMu sync.Mutex
+	generatorRuns   = make(map[int]context.CancelFunc) // Cancels the running jobs by ID
+)
+
+// generatorJobRunning reports whether the job is being enumerated by this process
+func generatorJobRunning(id int) bool {
+	generatorRunsMu.Lock()
+	defer generatorRunsMu.Unlock()
+	_, ok := generatorRuns[id]
+	return ok
+}
+
+// StartGeneratorJob enumerates job in the background from its cursor
+func StartGeneratorJob(store MatrixStore, job *GeneratorJob) error {
+	generatorRunsMu.Lock()
+	if _, ok := generatorRuns[job.ID]; ok {
+		generatorRunsMu.Unlock()
+		return fmt.Errorf("üretici işi %d zaten çalışıyor", job.ID)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	generatorRuns[job.ID] = cancel
+	generatorRunsMu.Unlock()
+
+	go func() {
+		defer func() {
+			generatorRunsMu.Lock()
+			delete(generatorRuns, job.ID)
+			generatorRunsMu.Unlock()
+			cancel()
+		}()
+		runGeneratorJob(ctx, store, job)
+	}()
+	return nil
+}
+
+// PauseGeneratorJob stops a running job; it stores its cursor and the paused status on exit
+func PauseGeneratorJob(id int) bool {
+	generatorRunsMu.Lock()
+	defer generatorRunsMu.Unlock()
+	cancel, ok := generatorRuns[id]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// ResumeGeneratorJobs restarts the jobs that were running when the service stopped
+func ResumeGeneratorJobs(store MatrixStore) {
+	jobs, err := store.GetGeneratorJobs(GeneratorRunning)
+	if err != nil {
+		log.Printf("❌ [GENERATOR] Çalışan işler alınamadı: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		log.Printf("🔄 [GENERATOR] İş %d kaldığı yerden devam ediyor (%d aday incelendi)", job.ID, job.Examined)
+		if err := StartGeneratorJob(store, job); err != nil {
+			log.Printf("⚠️  [GENERATOR] İş %d başlatılamadı: %v", job.ID, err)
+		}
+	}
+}
+
+// runGeneratorJob examines candidates from the job cursor until the
+// enumeration is exhausted, max_results new matrices are stored or ctx is
+// cancelled. Progress is stored periodically; candidates examined again after
+// a crash are caught by the matrix_hash check.
+func runGeneratorJob(ctx context.Context, store MatrixStore, job *GeneratorJob) {
+	family, _ := GetConstructionFamily(job.Family)
+	field, err := NewGF2m(job.Polynomial)
+	if family == nil || err != nil {
+		finishGeneratorJob(store, job, GeneratorFailed, fmt.Errorf("iş tanımı geçersiz: %s %s: %v", job.Family, job.Polynomial, err))
+		return
+	}
+	q := uint32(1) << uint(field.M)
+	group := fmt.Sprintf("generator-%d-%s", job.ID, family.Name)
+
+	log.Printf("🔄 [GENERATOR] İş %d: %s %dx%d %s üzerinde başlıyor", job.ID, family.Label, job.Dimension, job.Dimension, field)
+	lastSave := time.Now()
+	for job.Cursor != nil && job.Inserted < job.MaxResults {
+		if ctx.Err() != nil {
+			finishGeneratorJob(store, job, GeneratorPaused, nil)
+			return
+		}
+
+		elements := family.build(field, job.Dimension, job.Cursor)
+		job.Examined++
+		if elements != nil {
+			budget := analysisSearchBudget
+			if mds, ok := field.allMinorsNonSingular(elements, &budget); ok && mds {
+				job.Found++
+				if err := storeGeneratedMatrix(store, job, family, field, elements, group); err != nil {
+					finishGeneratorJob(store, job, GeneratorFailed, err)
+					return
+				}
+			}
+		}
+
+		if !family.next(job.Cursor, job.Dimension, q) {
+			job.Cursor = nil
+		}
+		if time.Since(lastSave) >= generatorProgressInterval {
+			if err := store.SaveGeneratorProgress(job); err != nil {
+				log.Printf("⚠️  [GENERATOR] İş %d ilerlemesi kaydedilemedi: %v", job.ID, err)
+			}
+			lastSave = time.Now()
+		}
+	}
+	finishGeneratorJob(store, job, GeneratorCompleted, nil)
+}
+
+// storeGeneratedMatrix saves an MDS candidate unless its matrix_hash is
+// already stored, and queues the job's solvers for it
+func storeGeneratedMatrix(store MatrixStore, job *GeneratorJob, family *ConstructionFamily, field *GF2m, elements FieldMatrix, group string) error {
+	matrix := field.Expand(elements)
+	if existing, err := store.GetMatrixByHash(calculateMatrixHash(matrix)); err == nil && existing != nil {
+		job.Duplicates++
+		return nil
+	}
+
+	parameters := make([]string, len(job.Cursor))
+	for i, a := range job.Cursor {
+		parameters[i] = field.FormatElement(a)
+	}
+	title := fmt.Sprintf("%s(%s) %dx%d %s", family.Label, strings.Join(parameters, ","), job.Dimension, job.Dimension, field)
+	record, err := SaveFieldMatrix(store, title, field, elements, group)
+	if err != nil {
+		return fmt.Errorf("matris kaydedilemedi: %v", err)
+	}
+	job.Inserted++
+	log.Printf("✅ [GENERATOR] İş %d: %s kaydedildi (ID: %d)", job.ID, title, record.ID)
+
+	queued := &AlgorithmJob{MatrixID: record.ID, Source: JobSourceGenerator, Algorithms: job.Algorithms, ReuseEquivalents: true}
+	return EnqueueAlgorithmJob(store, queued)
+}
+
+// finishGeneratorJob stores the final progress of a run with its status
+func finishGeneratorJob(store MatrixStore, job *GeneratorJob, status string, cause error) {
+	job.Status = status
+	job.Error = nil
+	if cause != nil {
+		message := cause.Error()
+		job.Error = &message
+		log.Printf("❌ [GENERATOR] İş %d durdu: %v", job.ID, cause)
+	} else {
+		log.Printf("✅ [GENERATOR] İş %d %s: %d aday, %d MDS, %d yeni, %d tekrar",
+			job.ID, status, job.Examined, job.Found, job.Inserted, job.Duplicates)
+	}
+	if err := store.SaveGeneratorProgress(job); err != nil {
+		log.Printf("❌ [GENERATOR] İş %d kaydedilemedi: %v", job.ID, err)
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxFieldDegree bounds the element size of supported fields GF(2^m)
+const maxFieldDegree = 16
+
+// GF2m is the binary extension field GF(2^m) defined by an irreducible polynomial.
+// Elements are polynomials over GF(2) stored with bit i as the coefficient of x^i.
+type GF2m struct {
+	M    int    // Element size in bits
+	Poly uint32 // Reduction polynomial including the x^m term
+}
+
+// FieldMatrix is a matrix of GF(2^m) elements
+type FieldMatrix [][]uint32
+
+// NewGF2m returns the field defined by polynomial, given in polynomial
+// notation ("x^4+x+1") or as hex ("0x13"). The polynomial must be irreducible.
+func NewGF2m(polynomial string) (*GF2m, error) {
+	poly, err := ParseGF2Polynomial(polynomial)
+	if err != nil {
+		return nil, err
+	}
+	m := bits.Len32(poly) - 1
+	if m < 1 || m > maxFieldDegree {
+		return nil, fmt.Errorf("polinom derecesi 1 ile %d arasında olmalı: %s", maxFieldDegree, polynomial)
+	}
+	if !gf2Irreducible(poly) {
+		return nil, fmt.Errorf("polinom indirgenemez değil: %s", FormatGF2Polynomial(poly))
+	}
+	return &GF2m{M: m, Poly: poly}, nil
+}
+
+// ParseGF2Polynomial parses a polynomial over GF(2) in polynomial notation
+// ("x^4+x+1", repeated terms cancel) or as hex with a 0x prefix ("0x13")
+func ParseGF2Polynomial(s string) (uint32, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("boş polinom")
+	}
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		value, err := strconv.ParseUint(s[2:], 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("geçersiz hex polinom: %s", s)
+		}
+		return uint32(value), nil
+	}
+
+	var poly uint32
+	for _, term := range strings.Split(strings.ReplaceAll(s, " ", ""), "+") {
+		exponent, err := parseGF2Term(term)
+		if err != nil {
+			return 0, fmt.Errorf("geçersiz polinom terimi %q: %s", term, s)
+		}
+		if exponent < 0 {
+			continue
+		}
+		if exponent > 31 {
+			return 0, fmt.Errorf("polinom derecesi çok büyük: %s", s)
+		}
+		poly ^= 1 << uint(exponent)
+	}
+	return poly, nil
+}
+
+// parseGF2Term returns the exponent of "1", "x" or "x^k", or -1 for "0"
+func parseGF2Term(term string) (int, error) {
+	switch strings.ToLower(term) {
+	case "0":
+		return -1, nil
+	case "1":
+		return 0, nil
+	case "x":
+		return 1, nil
+	}
+	lower := strings.ToLower(term)
+	if !strings.HasPrefix(lower, "x^") {
+		return 0, fmt.Errorf("geçersiz terim")
+	}
+	return strconv.Atoi(lower[2:])
+}
+
+// FormatGF2Polynomial renders p in polynomial notation, highest term first
+func FormatGF2Polynomial(p uint32) string {
+	if p == 0 {
+		return "0"
+	}
+	var terms []string
+	for i := bits.Len32(p) - 1; i >= 0; i-- {
+		if p&(1<<uint(i)) == 0 {
+			continue
+		}
+		switch i {
+		case 0:
+			terms = append(terms, "1")
+		case 1:
+			terms = append(terms, "x")
+		default:
+			terms = append(terms, fmt.Sprintf("x^%d", i))
+		}
+	}
+	return strings.Join(terms, "+")
+}
+
+// gf2Mod returns a mod b for polynomials over GF(2)
+func gf2Mod(a, b uint32) uint32 {
+	db := bits.Len32(b)
+	for bits.Len32(a) >= db {
+		a ^= b << uint(bits.Len32(a)-db)
+	}
+	return a
+}
+
+// gf2Irreducible reports whether p has no factor of degree 1..deg(p)/2
+func gf2Irreducible(p uint32) bool {
+	m := bits.Len32(p) - 1
+	if m < 1 {
+		return false
+	}
+	for d := 1; d <= m/2; d++ {
+		for q := uint32(1) << uint(d); q < 1<<uint(d+1); q++ {
+			if gf2Mod(p, q) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String returns the field as "GF(2^m)/x^m+..."
+func (f *GF2m) String() string {
+	return fmt.Sprintf("GF(2^%d)/%s", f.M, f.Polynomial())
+}
+
+// Polynomial returns the reduction polynomial in polynomial notation
+func (f *GF2m) Polynomial() string {
+	return FormatGF2Polynomial(f.Poly)
+}
+
+// Mul returns a·b in the field
+func (f *GF2m) Mul(a, b uint32) uint32 {
+	var product uint32
+	top := uint32(1) << uint(f.M)
+	for b != 0 {
+		if b&1 != 0 {
+			product ^= a
+		}
+		b >>= 1
+		a <<= 1
+		if a&top != 0 {
+			a ^= f.Poly
+		}
+	}
+	return product
+}
+
+// Inv returns the multiplicative inverse a^(2^m-2) of a non-zero element
+func (f *GF2m) Inv(a uint32) uint32 {
+	result := uint32(1)
+	for e := (1 << uint(f.M)) - 2; e > 0; e >>= 1 {
+		if e&1 != 0 {
+			result = f.Mul(result, a)
+		}
+		a = f.Mul(a, a)
+	}
+	return result
+}
+
+// ParseElement parses a field element given as hex ("0xb" or "b") or in
+// polynomial notation ("x^3+x+1")
+func (f *GF2m) ParseElement(s string) (uint32, error) {
+	s = strings.TrimSpace(s)
+	var value uint32
+	switch {
+	case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+		v, err := strconv.ParseUint(s[2:], 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("geçersiz hex eleman: %s", s)
+		}
+		value = uint32(v)
+	case strings.ContainsAny(s, "xX"):
+		v, err := ParseGF2Polynomial(s)
+		if err != nil {
+			return 0, err
+		}
+		value = v
+	default:
+		v, err := strconv.ParseUint(s, 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("geçersiz eleman: %s", s)
+		}
+		value = uint32(v)
+	}
+	if value >= 1<<uint(f.M) {
+		return 0, fmt.Errorf("eleman GF(2^%d) dışında: %s", f.M, s)
+	}
+	return value, nil
+}
+
+// FormatElement renders a field element as hex without prefix
+func (f *GF2m) FormatElement(a uint32) string {
+	return strconv.FormatUint(uint64(a), 16)
+}
+
+// ParseMatrix parses a rectangular matrix of field elements
+func (f *GF2m) ParseMatrix(rows [][]string) (FieldMatrix, error) {
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return nil, fmt.Errorf("matris boş")
+	}
+	matrix := make(FieldMatrix, len(rows))
+	for i, row := range rows {
+		if len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("satır %d uzunluğu farklı: %d != %d", i, len(row), len(rows[0]))
+		}
+		matrix[i] = make([]uint32, len(row))
+		for j, s := range row {
+			value, err := f.ParseElement(s)
+			if err != nil {
+				return nil, fmt.Errorf("satır %d, sütun %d: %v", i, j, err)
+			}
+			matrix[i][j] = value
+		}
+	}
+	return matrix, nil
+}
+
+// Expand returns the binary matrix of elements: every element a becomes the
+// m×m block of multiplication by a, whose column j holds the bits of a·x^j
+// (bit i in row i of the block)
+func (f *GF2m) Expand(elements FieldMatrix) Matrix {
+	binary := make(Matrix, len(elements)*f.M)
+	for r, row := range elements {
+		for i := 0; i < f.M; i++ {
+			binary[r*f.M+i] = make([]string, len(row)*f.M)
+		}
+		for c, a := range row {
+			column := a
+			for j := 0; j < f.M; j++ {
+				for i := 0; i < f.M; i++ {
+					bit := "0"
+					if column&(1<<uint(i)) != 0 {
+						bit = "1"
+					}
+					binary[r*f.M+i][c*f.M+j] = bit
+				}
+				column = f.Mul(column, 2)
+			}
+		}
+	}
+	return binary
+}
+
+// Compress recovers the field matrix of a binary matrix produced by Expand.
+// It reports false if the matrix is not made of multiplication blocks.
+func (f *GF2m) Compress(binary Matrix) (FieldMatrix, bool) {
+	if len(binary) == 0 || len(binary)%f.M != 0 || len(binary[0])%f.M != 0 {
+		return nil, false
+	}
+	elements := make(FieldMatrix, len(binary)/f.M)
+	for r := range elements {
+		elements[r] = make([]uint32, len(binary[0])/f.M)
+		for c := range elements[r] {
+			// The first column of a block is a·1 = a
+			for i := 0; i < f.M; i++ {
+				if binary[r*f.M+i][c*f.M] == "1" {
+					elements[r][c] |= 1 << uint(i)
+				}
+			}
+		}
+	}
+
+	expanded := f.Expand(elements)
+	for i := range binary {
+		if len(binary[i]) != len(expanded[i]) {
+			return nil, false
+		}
+		for j := range binary[i] {
+			if binary[i][j] != expanded[i][j] {
+				return nil, false
+			}
+		}
+	}
+	return elements, true
+}
+
+// Format renders elements as hex rows separated by ";" ("4 6 6;a 8 a;c c e"),
+// the form stored in matrix_records.field_matrix
+func (f *GF2m) Format(elements FieldMatrix) string {
+	rows := make([]string, len(elements))
+	for i, row := range elements {
+		values := make([]string, len(row))
+		for j, a := range row {
+			values[j] = f.FormatElement(a)
+		}
+		rows[i] = strings.Join(values, " ")
+	}
+	return strings.Join(rows, ";")
+}
+
+// parseStoredFieldMatrix splits a stored field matrix into its hex elements
+func parseStoredFieldMatrix(s string) [][]string {
+	var rows [][]string
+	for _, row := range strings.Split(s, ";") {
+		rows = append(rows, strings.Fields(row))
+	}
+	return rows
+}
+
+// fieldFileName matches data file names that declare their field, e.g.
+// "F2^4-x^4+x+1-(3x3)-mds-semi-involutif-binary.txt"
+var fieldFileName = regexp.MustCompile(`^F2\^(\d+)-([^-]+)-`)
+
+// fieldFromFileName returns the field a data file name declares, or nil
+func fieldFromFileName(name string) (*GF2m, error) {
+	match := fieldFileName.FindStringSubmatch(name)
+	if match == nil {
+		return nil, nil
+	}
+	field, err := NewGF2m(match[2])
+	if err != nil {
+		return nil, err
+	}
+	if m, _ := strconv.Atoi(match[1]); m != field.M {
+		return nil, fmt.Errorf("dosya adındaki derece (%s) polinomla uyuşmuyor: %s", match[1], field.Polynomial())
+	}
+	return field, nil
+}
+
+// resolveFieldMatrix interprets rows read from a file of field field: a
+// binary matrix made of multiplication blocks keeps its bits, anything else
+// is parsed as field elements and expanded. It returns the binary matrix and
+// the field matrix, which is nil if the binary matrix has no field form.
+func resolveFieldMatrix(field *GF2m, rows [][]string) (Matrix, FieldMatrix, error) {
+	if isBinaryMatrix(rows) {
+		if elements, ok := field.Compress(rows); ok {
+			return rows, elements, nil
+		}
+		if len(rows)%field.M == 0 && len(rows[0])%field.M == 0 {
+			return rows, nil, nil
+		}
+	}
+	elements, err := field.ParseMatrix(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return field.Expand(elements), elements, nil
+}
+
+// isBinaryMatrix reports whether every entry is "0" or "1"
+func isBinaryMatrix(rows [][]string) bool {
+	for _, row := range rows {
+		for _, s := range row {
+			if s != "0" && s != "1" {
+				return false
+			}
+		}
+	}
+	return len(rows) > 0
+}
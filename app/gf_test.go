@@ -0,0 +1,175 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// mdsMatrix is the circulant MDS matrix circ(1, 1, 2) over GF(2^4)/x^4+x+1
+var mdsMatrix = [][]string{{"1", "1", "2"}, {"2", "1", "1"}, {"1", "2", "1"}}
+
+func TestNewGF2m(t *testing.T) {
+	for _, polynomial := range []string{"x^4+x+1", "0x13", "x + x^4 + 1"} {
+		field, err := NewGF2m(polynomial)
+		if err != nil {
+			t.Errorf("%s: %v", polynomial, err)
+			continue
+		}
+		if field.M != 4 || field.Polynomial() != "x^4+x+1" {
+			t.Errorf("%s: %s, beklenen GF(2^4)/x^4+x+1", polynomial, field)
+		}
+	}
+
+	// x^4+x^2+1 = (x^2+x+1)^2, x^17 exceeds the supported degree
+	for _, polynomial := range []string{"x^4+x^2+1", "x^2+1", "1", "x^17+x^3+1", "x+", "y^2+1"} {
+		if _, err := NewGF2m(polynomial); err == nil {
+			t.Errorf("%s kabul edildi", polynomial)
+		}
+	}
+}
+
+func TestGF2PolynomialFormat(t *testing.T) {
+	tests := []struct {
+		in   string
+		poly uint32
+		out  string
+	}{
+		{"x^8+x^4+x^3+x+1", 0x11b, "x^8+x^4+x^3+x+1"},
+		{"1+x", 0x3, "x+1"},
+		{"x+x+1", 0x1, "1"}, // Repeated terms cancel
+		{"0x11B", 0x11b, "x^8+x^4+x^3+x+1"},
+		{"0", 0, "0"},
+	}
+
+	for _, tt := range tests {
+		poly, err := ParseGF2Polynomial(tt.in)
+		if err != nil || poly != tt.poly {
+			t.Errorf("%s: %#x (%v), beklenen %#x", tt.in, poly, err, tt.poly)
+			continue
+		}
+		if got := FormatGF2Polynomial(poly); got != tt.out {
+			t.Errorf("%s: %s, beklenen %s", tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestGF2mMul(t *testing.T) {
+	field, err := NewGF2m("x^8+x^4+x^3+x+1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Known AES products
+	if got := field.Mul(0x57, 0x83); got != 0xc1 {
+		t.Errorf("57·83 = %x, beklenen c1", got)
+	}
+	if got := field.Mul(0x53, 0xca); got != 0x01 {
+		t.Errorf("53·ca = %x, beklenen 1", got)
+	}
+	for a := uint32(0); a < 256; a++ {
+		if field.Mul(a, 1) != a || field.Mul(a, 0) != 0 || field.Mul(a, 3) != field.Mul(3, a) {
+			t.Fatalf("%x ile çarpma hatalı", a)
+		}
+	}
+}
+
+func TestExpandCompress(t *testing.T) {
+	field, err := NewGF2m("x^4+x+1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	elements, err := field.ParseMatrix(mdsMatrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binary := field.Expand(elements)
+	if len(binary) != 12 || len(binary[0]) != 12 {
+		t.Fatalf("genişletilmiş matris %dx%d, beklenen 12x12", len(binary), len(binary[0]))
+	}
+	// The block of 2 = x maps x^j to x^(j+1), and x^3 to x^4 = x+1
+	block := [][]string{
+		{"0", "0", "0", "1"},
+		{"1", "0", "0", "1"},
+		{"0", "1", "0", "0"},
+		{"0", "0", "1", "0"},
+	}
+	for i, row := range block {
+		if got := binary[i][8:12]; !reflect.DeepEqual(got, row) {
+			t.Errorf("2 bloğu satır %d: %v, beklenen %v", i, got, row)
+		}
+	}
+
+	compressed, ok := field.Compress(binary)
+	if !ok || !reflect.DeepEqual(compressed, elements) {
+		t.Errorf("Compress %v (%v), beklenen %v", compressed, ok, elements)
+	}
+	if got := field.Format(compressed); got != "1 1 2;2 1 1;1 2 1" {
+		t.Errorf("Format %q", got)
+	}
+	if !reflect.DeepEqual(parseStoredFieldMatrix(field.Format(compressed)), mdsMatrix) {
+		t.Errorf("saklanan matris geri okunamadı")
+	}
+
+	// The blocks of x reduce differently under another polynomial
+	other, err := NewGF2m("x^4+x^3+1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := other.Compress(binary); ok {
+		t.Error("başka polinomun blokları kabul edildi")
+	}
+	binary[0][1] = "1"
+	if _, ok := field.Compress(binary); ok {
+		t.Error("çarpma bloğu olmayan matris sıkıştırıldı")
+	}
+}
+
+func TestResolveFieldMatrix(t *testing.T) {
+	field, err := NewGF2m("x^4+x+1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binary, elements, err := resolveFieldMatrix(field, mdsMatrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binary) != 12 || elements == nil {
+		t.Fatalf("eleman matrisi genişletilmedi: %d satır", len(binary))
+	}
+
+	// An expanded matrix keeps its bits and recovers its elements
+	again, recovered, err := resolveFieldMatrix(field, binary)
+	if err != nil || !reflect.DeepEqual(again, binary) || !reflect.DeepEqual(recovered, elements) {
+		t.Errorf("ikili matris çözümlenemedi: %v %v", recovered, err)
+	}
+
+	// A binary matrix of matching size without field form has no elements
+	plain := make(Matrix, 4)
+	for i := range plain {
+		plain[i] = wideRow(4, i, (i+1)%4)
+	}
+	if kept, none, err := resolveFieldMatrix(field, plain); err != nil || none != nil || !reflect.DeepEqual(kept, plain) {
+		t.Errorf("alan biçimi olmayan matris: %v %v", none, err)
+	}
+
+	if _, _, err := resolveFieldMatrix(field, [][]string{{"1", "10"}}); err == nil {
+		t.Error("alan dışındaki eleman kabul edildi")
+	}
+}
+
+func TestFieldFromFileName(t *testing.T) {
+	field, err := fieldFromFileName("F2^4-x^4+x+1-(3x3)-mds.txt")
+	if err != nil || field == nil || field.M != 4 || field.Poly != 0x13 {
+		t.Errorf("alan okunamadı: %v %v", field, err)
+	}
+
+	if field, err := fieldFromFileName("AES.txt"); field != nil || err != nil {
+		t.Errorf("alansız dosya: %v %v", field, err)
+	}
+	for _, name := range []string{"F2^4-x^3+x+1-a.txt", "F2^4-x^4+x^2+1-a.txt"} {
+		if _, err := fieldFromFileName(name); err == nil {
+			t.Errorf("%s kabul edildi", name)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// hdlTestVectors is the number of random vectors embedded in the generated
+// testbenches together with their products computed by the matrix
+const hdlTestVectors = 64
+
+// hdlTestSeed seeds the testbench vectors so repeated exports are identical
+const hdlTestSeed = 1
+
+// testVectors returns random input vectors and their naive matrix-vector products
+func (u *codegenUnit) testVectors() (inputs, outputs []BitVector) {
+	rng := rand.New(rand.NewSource(hdlTestSeed))
+	for v := 0; v < hdlTestVectors; v++ {
+		x := NewBitVector(u.NumInputs)
+		for j := 0; j < u.NumInputs; j++ {
+			if rng.Intn(2) == 1 {
+				x.Set(j)
+			}
+		}
+		y := NewBitVector(len(u.Rows))
+		for i, row := range u.Rows {
+			if row.AndPopCount(x)%2 == 1 {
+				y.Set(i)
+			}
+		}
+		inputs = append(inputs, x)
+		outputs = append(outputs, y)
+	}
+	return inputs, outputs
+}
+
+// hdlBits renders the first n bits of v as a binary literal, most significant bit first
+func hdlBits(v BitVector, n int) string {
+	var b strings.Builder
+	for i := n - 1; i >= 0; i-- {
+		if v.Test(i) {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+// hdlPorts returns the scalar port names: x0.. for the matrix columns and y0.. for the rows
+func (u *codegenUnit) hdlPorts() (inputs, outputs []string) {
+	for j := 0; j < u.NumInputs; j++ {
+		inputs = append(inputs, fmt.Sprintf("x%d", j))
+	}
+	for i := range u.Rows {
+		outputs = append(outputs, fmt.Sprintf("y%d", i))
+	}
+	return inputs, outputs
+}
+
+// generateVerilog emits a structural module with one xor primitive per gate
+// and a self-checking testbench
+func generateVerilog(u *codegenUnit) []GeneratedFile {
+	module := fmt.Sprintf("xor_opt_%d", u.ID)
+	m, n := len(u.Rows), u.NumInputs
+	p := u.Program
+	inputs, outputs := u.hdlPorts()
+
+	var code strings.Builder
+	for _, line := range u.summary() {
+		fmt.Fprintf(&code, "// %s\n", line)
+	}
+	fmt.Fprintf(&code, "module %s (\n", module)
+	ports := make([]string, 0, n+m)
+	for _, name := range inputs {
+		ports = append(ports, "    input  wire "+name)
+	}
+	for _, name := range outputs {
+		ports = append(ports, "    output wire "+name)
+	}
+	code.WriteString(strings.Join(ports, ",\n"))
+	code.WriteString("\n);\n")
+	if len(p.Gates) > 0 {
+		code.WriteString("\n")
+		for k := range p.Gates {
+			fmt.Fprintf(&code, "    wire t%d;\n", k)
+		}
+		code.WriteString("\n")
+		for k, gate := range p.Gates {
+			fmt.Fprintf(&code, "    xor g%d (t%d, %s, %s); // depth %d\n", k, k, p.SignalName(gate.A), p.SignalName(gate.B), gate.Depth)
+		}
+	}
+	code.WriteString("\n")
+	for i, s := range p.Outputs {
+		if s < 0 {
+			fmt.Fprintf(&code, "    assign y%d = 1'b0;\n", i)
+		} else {
+			fmt.Fprintf(&code, "    assign y%d = %s;\n", i, p.SignalName(s))
+		}
+	}
+	code.WriteString("endmodule\n")
+
+	vectors, expected := u.testVectors()
+	var tb strings.Builder
+	fmt.Fprintf(&tb, "// Self-checking testbench for %s: random vectors multiplied by the matrix\n", module)
+	tb.WriteString("`timescale 1ns / 1ps\n\n")
+	fmt.Fprintf(&tb, "module %s_tb;\n", module)
+	fmt.Fprintf(&tb, "    reg  [%d:0] x;\n", n-1)
+	fmt.Fprintf(&tb, "    wire [%d:0] y;\n", m-1)
+	fmt.Fprintf(&tb, "    reg  [%d:0] vectors [0:%d];\n", n-1, len(vectors)-1)
+	fmt.Fprintf(&tb, "    reg  [%d:0] expected [0:%d];\n", m-1, len(vectors)-1)
+	tb.WriteString("    integer i, errors;\n\n")
+	fmt.Fprintf(&tb, "    %s dut (\n", module)
+	connections := make([]string, 0, n+m)
+	for j, name := range inputs {
+		connections = append(connections, fmt.Sprintf("        .%s(x[%d])", name, j))
+	}
+	for i, name := range outputs {
+		connections = append(connections, fmt.Sprintf("        .%s(y[%d])", name, i))
+	}
+	tb.WriteString(strings.Join(connections, ",\n"))
+	tb.WriteString("\n    );\n\n    initial begin\n")
+	for v := range vectors {
+		fmt.Fprintf(&tb, "        vectors[%d] = %d'b%s; expected[%d] = %d'b%s;\n", v, n, hdlBits(vectors[v], n), v, m, hdlBits(expected[v], m))
+	}
+	tb.WriteString("\n        errors = 0;\n")
+	fmt.Fprintf(&tb, "        for (i = 0; i < %d; i = i + 1) begin\n", len(vectors))
+	tb.WriteString("            x = vectors[i];\n            #1;\n")
+	tb.WriteString("            if (y !== expected[i]) begin\n")
+	tb.WriteString("                $display(\"FAIL: vector %0d x=%b y=%b expected=%b\", i, x, y, expected[i]);\n")
+	tb.WriteString("                errors = errors + 1;\n            end\n        end\n")
+	tb.WriteString("        if (errors == 0)\n")
+	fmt.Fprintf(&tb, "            $display(\"OK: %d vectors\");\n", len(vectors))
+	tb.WriteString("        else\n            $display(\"FAILED: %0d errors\", errors);\n")
+	tb.WriteString("        $finish;\n    end\nendmodule\n")
+
+	return []GeneratedFile{
+		{Name: module + ".v", Content: code.String()},
+		{Name: module + "_tb.v", Content: tb.String()},
+	}
+}
+
+// vhdlXor2 is the two-input XOR cell the structural VHDL architecture instantiates
+const vhdlXor2 = `library ieee;
+use ieee.std_logic_1164.all;
+
+entity xor_opt_xor2 is
+    port (
+        a : in  std_logic;
+        b : in  std_logic;
+        y : out std_logic
+    );
+end entity xor_opt_xor2;
+
+architecture rtl of xor_opt_xor2 is
+begin
+    y <= a xor b;
+end architecture rtl;
+`
+
+// generateVHDL emits a structural entity with one XOR cell instance per gate
+// and a self-checking testbench
+func generateVHDL(u *codegenUnit) []GeneratedFile {
+	entity := fmt.Sprintf("xor_opt_%d", u.ID)
+	m, n := len(u.Rows), u.NumInputs
+	p := u.Program
+	inputs, outputs := u.hdlPorts()
+
+	var code strings.Builder
+	for _, line := range u.summary() {
+		fmt.Fprintf(&code, "-- %s\n", line)
+	}
+	code.WriteString("\n")
+	code.WriteString(vhdlXor2)
+	code.WriteString("\nlibrary ieee;\nuse ieee.std_logic_1164.all;\n\n")
+	fmt.Fprintf(&code, "entity %s is\n    port (\n", entity)
+	ports := make([]string, 0, n+m)
+	for _, name := range inputs {
+		ports = append(ports, fmt.Sprintf("        %s : in  std_logic", name))
+	}
+	for _, name := range outputs {
+		ports = append(ports, fmt.Sprintf("        %s : out std_logic", name))
+	}
+	code.WriteString(strings.Join(ports, ";\n"))
+	fmt.Fprintf(&code, "\n    );\nend entity %s;\n\n", entity)
+	fmt.Fprintf(&code, "architecture structural of %s is\n", entity)
+	for k := range p.Gates {
+		fmt.Fprintf(&code, "    signal t%d : std_logic;\n", k)
+	}
+	code.WriteString("begin\n")
+	for k, gate := range p.Gates {
+		fmt.Fprintf(&code, "    g%d: entity work.xor_opt_xor2 port map (a => %s, b => %s, y => t%d); -- depth %d\n",
+			k, p.SignalName(gate.A), p.SignalName(gate.B), k, gate.Depth)
+	}
+	for i, s := range p.Outputs {
+		if s < 0 {
+			fmt.Fprintf(&code, "    y%d <= '0';\n", i)
+		} else {
+			fmt.Fprintf(&code, "    y%d <= %s;\n", i, p.SignalName(s))
+		}
+	}
+	code.WriteString("end architecture structural;\n")
+
+	vectors, expected := u.testVectors()
+	var tb strings.Builder
+	fmt.Fprintf(&tb, "-- Self-checking testbench for %s: random vectors multiplied by the matrix\n", entity)
+	tb.WriteString("library ieee;\nuse ieee.std_logic_1164.all;\n\n")
+	fmt.Fprintf(&tb, "entity %s_tb is\nend entity %s_tb;\n\n", entity, entity)
+	fmt.Fprintf(&tb, "architecture sim of %s_tb is\n", entity)
+	fmt.Fprintf(&tb, "    subtype input_word is std_logic_vector(%d downto 0);\n", n-1)
+	fmt.Fprintf(&tb, "    subtype output_word is std_logic_vector(%d downto 0);\n", m-1)
+	tb.WriteString("    type input_array is array (natural range <>) of input_word;\n")
+	tb.WriteString("    type output_array is array (natural range <>) of output_word;\n\n")
+	vectorLines := make([]string, len(vectors))
+	expectedLines := make([]string, len(vectors))
+	for v := range vectors {
+		vectorLines[v] = fmt.Sprintf("        %d => \"%s\"", v, hdlBits(vectors[v], n))
+		expectedLines[v] = fmt.Sprintf("        %d => \"%s\"", v, hdlBits(expected[v], m))
+	}
+	fmt.Fprintf(&tb, "    constant vectors : input_array(0 to %d) := (\n%s\n    );\n", len(vectors)-1, strings.Join(vectorLines, ",\n"))
+	fmt.Fprintf(&tb, "    constant expected : output_array(0 to %d) := (\n%s\n    );\n\n", len(vectors)-1, strings.Join(expectedLines, ",\n"))
+	tb.WriteString("    signal x : input_word;\n    signal y : output_word;\nbegin\n")
+	fmt.Fprintf(&tb, "    dut: entity work.%s port map (\n", entity)
+	connections := make([]string, 0, n+m)
+	for j, name := range inputs {
+		connections = append(connections, fmt.Sprintf("        %s => x(%d)", name, j))
+	}
+	for i, name := range outputs {
+		connections = append(connections, fmt.Sprintf("        %s => y(%d)", name, i))
+	}
+	tb.WriteString(strings.Join(connections, ",\n"))
+	tb.WriteString("\n    );\n\n    check: process\n        variable errors : natural := 0;\n    begin\n")
+	tb.WriteString("        for i in vectors'range loop\n")
+	tb.WriteString("            x <= vectors(i);\n            wait for 1 ns;\n")
+	tb.WriteString("            if y /= expected(i) then\n")
+	tb.WriteString("                report \"FAIL: vector \" & integer'image(i) severity error;\n")
+	tb.WriteString("                errors := errors + 1;\n            end if;\n        end loop;\n")
+	tb.WriteString("        if errors = 0 then\n")
+	fmt.Fprintf(&tb, "            report \"OK: %d vectors\";\n", len(vectors))
+	tb.WriteString("        else\n            report \"FAILED: \" & integer'image(errors) & \" errors\" severity failure;\n")
+	tb.WriteString("        end if;\n        wait;\n    end process check;\nend architecture sim;\n")
+
+	return []GeneratedFile{
+		{Name: entity + ".vhd", Content: code.String()},
+		{Name: entity + "_tb.vhd", Content: tb.String()},
+	}
+}
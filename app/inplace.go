@@ -0,0 +1,544 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// inPlaceConvertBudget bounds how long the s-XOR program of a stored matrix
+// is searched for after its results change; the best program found by then
+// is stored
+const inPlaceConvertBudget = 5 * time.Second
+
+// XOR metrics of a matrix:
+//
+//	d-XOR  every row computed on its own: ones - rows, ham_xor_count for square matrices
+//	g-XOR  two-input gates with a fresh output each (Program), smallest_xor
+//	s-XOR  in-place operations x_i ^= x_j on the input registers (InPlaceProgram), sxor_count
+//
+// An s-XOR program is also a g-XOR program of the same length, so g-XOR never
+// exceeds s-XOR. In-place operations are invertible, so s-XOR is only defined
+// for square invertible matrices.
+
+// InPlaceOp is the in-place operation x_Target ^= x_Source
+type InPlaceOp struct {
+	Target int `json:"target"`
+	Source int `json:"source"`
+}
+
+// InPlaceProgram is an s-XOR program: a sequence of in-place operations on
+// the input registers, after which every matrix row is held by a register
+type InPlaceProgram struct {
+	NumRegisters int         `json:"num_registers"`
+	Ops          []InPlaceOp `json:"ops"`
+	Outputs      []int       `json:"outputs"` // Register holding matrix row i at the end
+}
+
+// XorCount returns the number of in-place operations
+func (p *InPlaceProgram) XorCount() int {
+	return len(p.Ops)
+}
+
+// Program returns the equivalent g-XOR program: every operation becomes a
+// gate whose output replaces the target register
+func (p *InPlaceProgram) Program() *Program {
+	program := NewProgram(p.NumRegisters, len(p.Outputs))
+	signals := make([]int, p.NumRegisters)
+	for i := range signals {
+		signals[i] = i
+	}
+	for _, op := range p.Ops {
+		signals[op.Target] = program.AddGate(signals[op.Target], signals[op.Source])
+	}
+	for i, register := range p.Outputs {
+		program.Outputs[i] = signals[register]
+	}
+	return program
+}
+
+// Lines returns the text form: one "x<i> ^= x<j>" line per operation followed
+// by one "y<i> = x<j>" line per output
+func (p *InPlaceProgram) Lines() []string {
+	lines := make([]string, 0, len(p.Ops)+len(p.Outputs))
+	for _, op := range p.Ops {
+		lines = append(lines, fmt.Sprintf("x%d ^= x%d", op.Target, op.Source))
+	}
+	for i, register := range p.Outputs {
+		lines = append(lines, fmt.Sprintf("y%d = x%d", i, register))
+	}
+	return lines
+}
+
+// MarshalJSON adds the derived text form to the structured program
+func (p *InPlaceProgram) MarshalJSON() ([]byte, error) {
+	type program InPlaceProgram
+	return json.Marshal(struct {
+		*program
+		Lines []string `json:"lines"`
+	}{(*program)(p), p.Lines()})
+}
+
+// inPlaceRows parses a matrix for the s-XOR metric, which needs it square
+// and invertible
+func inPlaceRows(matrix Matrix) ([]BitVector, error) {
+	rows, numInputs, err := parseBinaryRows(matrix)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) != numInputs {
+		return nil, fmt.Errorf("s-XOR için matris kare olmalı: %dx%d", len(rows), numInputs)
+	}
+	return rows, nil
+}
+
+// SolveInPlace finds an s-XOR program for a square invertible matrix by
+// reducing M to a permutation matrix with row operations r_i ^= r_j. Two
+// reductions are tried: plain Gauss-Jordan elimination, and greedy steps
+// that lower the total row weight as much as possible before Gauss-Jordan
+// finishes. Reading the shorter reduction backwards builds M from the inputs.
+// Once ctx is done the greedy steps stop and Gauss-Jordan finishes, so the
+// result is always a valid program.
+func SolveInPlace(ctx context.Context, matrix Matrix) (*InPlaceProgram, error) {
+	rows, err := inPlaceRows(matrix)
+	if err != nil {
+		return nil, err
+	}
+	ops, err := buildRowsInPlace(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	program := &InPlaceProgram{NumRegisters: len(rows), Ops: ops}
+	if err := program.resolveOutputs(rows); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+// buildRowsInPlace returns the shorter of the two reductions of rows, read
+// backwards: in-place operations that leave every row in some register when
+// started from the unit vectors
+func buildRowsInPlace(ctx context.Context, rows []BitVector) ([]InPlaceOp, error) {
+	var best []InPlaceOp
+	for _, greedy := range []bool{false, true} {
+		work := make([]BitVector, len(rows))
+		for i, row := range rows {
+			work[i] = row.Clone()
+		}
+		ops, err := reduceToPermutation(ctx, work, greedy)
+		if err != nil {
+			return nil, err
+		}
+
+		// E_k...E_1 M = P, so P^-1 M = (P^-1 E_1 P)...(P^-1 E_k P); conjugating
+		// r_i ^= r_j by P renames it to r_p(i) ^= r_p(j), where row r of P
+		// has its one in column p(r)
+		position := make([]int, len(work))
+		for r, row := range work {
+			for c := range rows {
+				if row.Test(c) {
+					position[r] = c
+				}
+			}
+		}
+		built := make([]InPlaceOp, 0, len(ops))
+		for k := len(ops) - 1; k >= 0; k-- {
+			built = append(built, InPlaceOp{Target: position[ops[k].Target], Source: position[ops[k].Source]})
+		}
+		if best == nil || len(built) < len(best) {
+			best = built
+		}
+	}
+	return best, nil
+}
+
+// reduceToPermutation applies row operations to rows until it is a
+// permutation matrix and returns them in order. It fails if the matrix is
+// singular. Greedy steps are taken only while ctx is not done.
+func reduceToPermutation(ctx context.Context, rows []BitVector, greedy bool) ([]InPlaceOp, error) {
+	var ops []InPlaceOp
+	apply := func(target, source int) {
+		rows[target] = rows[target].Xor(rows[source])
+		ops = append(ops, InPlaceOp{Target: target, Source: source})
+	}
+
+	// Greedy: the operation that removes the most ones, while one helps
+	for greedy && ctx.Err() == nil {
+		bestGain, bestTarget, bestSource := 0, -1, -1
+		for i := range rows {
+			weight := rows[i].PopCount()
+			for j := range rows {
+				if i == j {
+					continue
+				}
+				if gain := weight - rows[i].Xor(rows[j]).PopCount(); gain > bestGain {
+					bestGain, bestTarget, bestSource = gain, i, j
+				}
+			}
+		}
+		if bestTarget < 0 {
+			break
+		}
+		apply(bestTarget, bestSource)
+	}
+
+	// Gauss-Jordan without swaps: the lightest unused row with the column's
+	// bit becomes the pivot and clears the bit from every other row
+	used := make([]bool, len(rows))
+	for c := range rows {
+		pivot := -1
+		for r, row := range rows {
+			if !used[r] && row.Test(c) && (pivot < 0 || row.PopCount() < rows[pivot].PopCount()) {
+				pivot = r
+			}
+		}
+		if pivot < 0 {
+			return nil, fmt.Errorf("s-XOR için matris tersinir olmalı")
+		}
+		used[pivot] = true
+		for r, row := range rows {
+			if r != pivot && row.Test(c) {
+				apply(r, pivot)
+			}
+		}
+	}
+	return ops, nil
+}
+
+// resolveOutputs runs the operations and records the register holding each
+// row; it fails if a row is not held at the end
+func (p *InPlaceProgram) resolveOutputs(rows []BitVector) error {
+	registers := make([]BitVector, p.NumRegisters)
+	for i := range registers {
+		registers[i] = UnitBitVector(p.NumRegisters, i)
+	}
+	for _, op := range p.Ops {
+		registers[op.Target] = registers[op.Target].Xor(registers[op.Source])
+	}
+	p.Outputs = make([]int, len(rows))
+	for i, row := range rows {
+		p.Outputs[i] = -1
+		for r, register := range registers {
+			if register.Equal(row) {
+				p.Outputs[i] = r
+				break
+			}
+		}
+		if p.Outputs[i] < 0 {
+			return fmt.Errorf("s-XOR programı satır %d'i hesaplamıyor", i)
+		}
+	}
+	return nil
+}
+
+// inPlaceMachine tracks the registers of an s-XOR program under
+// construction together with the coordinates of every unit vector in the
+// register basis, so any value can be rewritten as a sum of registers
+type inPlaceMachine struct {
+	program   *InPlaceProgram
+	registers []BitVector
+	coords    []BitVector // coords[m]: registers summing to unit vector m
+	holds     []int       // Program signal each register holds, -1 if none
+}
+
+func newInPlaceMachine(n int) *inPlaceMachine {
+	m := &inPlaceMachine{
+		program:   &InPlaceProgram{NumRegisters: n},
+		registers: make([]BitVector, n),
+		coords:    make([]BitVector, n),
+		holds:     make([]int, n),
+	}
+	for i := 0; i < n; i++ {
+		m.registers[i] = UnitBitVector(n, i)
+		m.coords[i] = UnitBitVector(n, i)
+		m.holds[i] = i
+	}
+	return m
+}
+
+// apply performs x_target ^= x_source. Old register target is now the sum
+// of the new registers target and source, so every coordinate vector that
+// uses target also flips source.
+func (m *inPlaceMachine) apply(target, source int) {
+	m.registers[target] = m.registers[target].Xor(m.registers[source])
+	for _, c := range m.coords {
+		if c.Test(target) {
+			if c.Test(source) {
+				c.Clear(source)
+			} else {
+				c.Set(source)
+			}
+		}
+	}
+	m.holds[target] = -1
+	m.program.Ops = append(m.program.Ops, InPlaceOp{Target: target, Source: source})
+}
+
+// coordinates returns the registers that sum to value
+func (m *inPlaceMachine) coordinates(value BitVector) BitVector {
+	c := NewBitVector(len(m.registers))
+	for i := range m.registers {
+		if value.Test(i) {
+			c = c.Xor(m.coords[i])
+		}
+	}
+	return c
+}
+
+// holder returns the register holding value, or -1
+func (m *inPlaceMachine) holder(value BitVector) int {
+	for r, register := range m.registers {
+		if register.Equal(value) {
+			return r
+		}
+	}
+	return -1
+}
+
+// place computes value into register target, which must be one of its
+// coordinates, by adding the other registers of the sum
+func (m *inPlaceMachine) place(value BitVector, target int) {
+	c := m.coordinates(value)
+	for r := range m.registers {
+		if r != target && c.Test(r) {
+			m.apply(target, r)
+		}
+	}
+}
+
+// ConvertToInPlace rewrites a g-XOR program for a square invertible matrix
+// as an s-XOR program. Gates are replayed in order: a gate value is built in
+// a register that appears in its sum over the current registers, preferring
+// registers whose value is no longer needed, so a gate whose operand is dead
+// costs one operation. The rows are then finished like SolveInPlace does,
+// with the rows written in the basis of the current registers. Late gates may
+// cost more than they save, so the finish is tried after every gate and the
+// cheapest prefix is kept. Once ctx is done no further prefixes are tried and
+// the cheapest one so far is finished.
+func ConvertToInPlace(ctx context.Context, matrix Matrix, program *Program) (*InPlaceProgram, error) {
+	rows, err := inPlaceRows(matrix)
+	if err != nil {
+		return nil, err
+	}
+	if program.NumInputs != len(rows) || len(program.Outputs) != len(rows) {
+		return nil, fmt.Errorf("program matrisle uyuşmuyor")
+	}
+
+	replay := newGateReplay(program)
+	finish := func(m *inPlaceMachine) ([]InPlaceOp, error) {
+		coordinates := make([]BitVector, len(rows))
+		for i, row := range rows {
+			coordinates[i] = m.coordinates(row)
+		}
+		return buildRowsInPlace(ctx, coordinates)
+	}
+
+	// Cheapest prefix: replayed operations plus the finish after them
+	m := newInPlaceMachine(len(rows))
+	prefix, bestCount := 0, -1
+	for k := 0; ; k++ {
+		ops, err := finish(m)
+		if err != nil {
+			return nil, err
+		}
+		if count := len(m.program.Ops) + len(ops); bestCount < 0 || count < bestCount {
+			prefix, bestCount = k, count
+		}
+		if k == len(program.Gates) || ctx.Err() != nil {
+			break
+		}
+		replay.gate(m, k)
+	}
+
+	m = newInPlaceMachine(len(rows))
+	for k := 0; k < prefix; k++ {
+		replay.gate(m, k)
+	}
+	ops, err := finish(m)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		m.apply(op.Target, op.Source)
+	}
+
+	if err := m.program.resolveOutputs(rows); err != nil {
+		return nil, err
+	}
+	return m.program, nil
+}
+
+// gateReplay replays the gates of a g-XOR program on an inPlaceMachine
+type gateReplay struct {
+	program *Program
+	values  []BitVector
+	uses    [][]int // uses[s]: gates reading signal s, len(Gates) for an output
+}
+
+func newGateReplay(program *Program) *gateReplay {
+	r := &gateReplay{
+		program: program,
+		values:  program.Evaluate(),
+		uses:    make([][]int, program.NumSignals()),
+	}
+	for k, gate := range program.Gates {
+		r.uses[gate.A] = append(r.uses[gate.A], k)
+		r.uses[gate.B] = append(r.uses[gate.B], k)
+	}
+	for _, s := range program.Outputs {
+		if s >= 0 {
+			r.uses[s] = append(r.uses[s], len(program.Gates))
+		}
+	}
+	return r
+}
+
+// nextUse returns the first gate after k reading signal s, or -1
+func (r *gateReplay) nextUse(s, k int) int {
+	if s < 0 {
+		return -1
+	}
+	i := sort.SearchInts(r.uses[s], k+1)
+	if i == len(r.uses[s]) {
+		return -1
+	}
+	return r.uses[s][i]
+}
+
+// gate builds the value of gate k in a register, overwriting the register
+// needed last among those in its sum, dead ones first
+func (r *gateReplay) gate(m *inPlaceMachine, k int) {
+	signal := r.program.NumInputs + k
+	if len(r.uses[signal]) == 0 {
+		return
+	}
+	value := r.values[signal]
+	if holder := m.holder(value); holder >= 0 {
+		m.holds[holder] = signal
+		return
+	}
+
+	c := m.coordinates(value)
+	target, targetUse := -1, 0
+	for reg := range m.registers {
+		if !c.Test(reg) {
+			continue
+		}
+		use := r.nextUse(m.holds[reg], k)
+		if use < 0 {
+			target = reg
+			break
+		}
+		if target < 0 || use > targetUse {
+			target, targetUse = reg, use
+		}
+	}
+	m.place(value, target)
+	m.holds[target] = signal
+}
+
+// SourceInPlaceSolver is the s-XOR source of a program found by SolveInPlace;
+// converted programs use the name of the solver they came from
+const SourceInPlaceSolver = "sxor"
+
+// BestInPlace returns the shortest s-XOR program for the matrix of record:
+// the in-place solver's own program or the conversion of a stored solver
+// program, together with its source. Programs not yet converted when ctx is
+// done are skipped.
+func BestInPlace(ctx context.Context, matrix Matrix, record *MatrixRecord) (*InPlaceProgram, string, error) {
+	best, err := SolveInPlace(ctx, matrix)
+	if err != nil {
+		return nil, "", err
+	}
+	source := SourceInPlaceSolver
+
+	for _, info := range persistedSolvers() {
+		if ctx.Err() != nil {
+			break
+		}
+		program := record.StoredProgram(info.Name)
+		if program == nil {
+			continue
+		}
+		converted, err := ConvertToInPlace(ctx, matrix, program)
+		if err != nil {
+			log.Printf("⚠️  [SXOR] %s programı dönüştürülemedi (ID %d): %v", info.Name, record.ID, err)
+			continue
+		}
+		if converted.XorCount() < best.XorCount() {
+			best, source = converted, info.Name
+		}
+	}
+	return best, source, nil
+}
+
+// XorMetrics reports the three XOR counts of a stored matrix
+type XorMetrics struct {
+	MatrixID    int             `json:"matrix_id"`
+	DXor        int             `json:"d_xor"`
+	GXor        *int            `json:"g_xor,omitempty"`
+	SXor        *int            `json:"s_xor,omitempty"`
+	SXorSource  *string         `json:"s_xor_source,omitempty"`
+	SXorProgram *InPlaceProgram `json:"s_xor_program,omitempty"`
+}
+
+func newXorMetrics(record *MatrixRecord) *XorMetrics {
+	return &XorMetrics{
+		MatrixID:    record.ID,
+		DXor:        record.HamXorCount,
+		GXor:        record.SmallestXor,
+		SXor:        record.SXorCount,
+		SXorSource:  record.SXorSource,
+		SXorProgram: record.SXorProgram,
+	}
+}
+
+// InPlaceSolver is the registered s-XOR solver; its Program is the g-XOR
+// form of the in-place operations, which are returned in InPlace
+type InPlaceSolver struct{}
+
+// Name returns the registry name of the solver
+func (s *InPlaceSolver) Name() string {
+	return "sxor"
+}
+
+// Parameters returns the effective solver parameters
+func (s *InPlaceSolver) Parameters() SolverParams {
+	return SolverParams{}
+}
+
+// Solve runs SolveInPlace. A run cut short by its deadline keeps the valid
+// program found and is reported as timed out; a canceled run fails.
+func (s *InPlaceSolver) Solve(ctx context.Context, matrix Matrix) (AlgResult, error) {
+	program, err := SolveInPlace(ctx, matrix)
+	if err != nil {
+		return AlgResult{}, err
+	}
+	status := StatusCompleted
+	if err := ctx.Err(); err != nil {
+		if !deadlineExceeded(err) {
+			return AlgResult{}, err
+		}
+		status = StatusTimedOut
+		log.Printf("[SXOR] Süre doldu, Gauss-Jordan sonucu döndürülüyor: %d XOR", program.XorCount())
+	}
+	return AlgResult{
+		XorCount: program.XorCount(),
+		Program:  program.Program(),
+		InPlace:  program,
+		Status:   status,
+	}, nil
+}
+
+func init() {
+	RegisterSolver(SolverInfo{
+		Name:  "sxor",
+		Label: "SXOR",
+		Factory: func(params SolverParams) (Solver, error) {
+			return &InPlaceSolver{}, nil
+		},
+	})
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// chainMatrix is y0 = x0, y1 = x0 + x1, y2 = x0 + x1 + x2
+var chainMatrix = Matrix{{"1", "0", "0"}, {"1", "1", "0"}, {"1", "1", "1"}}
+
+// chainProgram builds chainMatrix with two gates, each reusing the last one
+func chainProgram() *Program {
+	p := NewProgram(3, 3)
+	p.Outputs[0] = 0
+	p.Outputs[1] = p.AddGate(0, 1)
+	p.Outputs[2] = p.AddGate(p.Outputs[1], 2)
+	return p
+}
+
+// invertibleMatrices returns random invertible square matrices of the given sizes
+func invertibleMatrices(t *testing.T, rng *rand.Rand, sizes ...int) []Matrix {
+	t.Helper()
+	var matrices []Matrix
+	for _, n := range sizes {
+		for {
+			matrix := randomMatrix(rng, n, n, 0.5)
+			if _, err := SolveInPlace(context.Background(), matrix); err == nil {
+				matrices = append(matrices, matrix)
+				break
+			}
+		}
+	}
+	return matrices
+}
+
+// checkInPlace fails unless program computes matrix with as many gates as operations
+func checkInPlace(t *testing.T, name string, matrix Matrix, program *InPlaceProgram) {
+	t.Helper()
+	g := program.Program()
+	if verification := VerifyProgram(matrix, g); !verification.Valid {
+		t.Fatalf("%s: s-XOR programı geçersiz: %v", name, verification.Errors)
+	}
+	if g.XorCount() != program.XorCount() {
+		t.Errorf("%s: g-XOR formu %d kapı, s-XOR %d işlem", name, g.XorCount(), program.XorCount())
+	}
+}
+
+func TestSolveInPlace(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for _, matrix := range invertibleMatrices(t, rng, 2, 4, 6, 8, 12) {
+		program, err := SolveInPlace(context.Background(), matrix)
+		if err != nil {
+			t.Fatalf("%dx%d: beklenmeyen hata: %v", len(matrix), len(matrix), err)
+		}
+		checkInPlace(t, "SolveInPlace", matrix, program)
+	}
+
+	program, err := SolveInPlace(context.Background(), chainMatrix)
+	if err != nil {
+		t.Fatalf("zincir: beklenmeyen hata: %v", err)
+	}
+	checkInPlace(t, "zincir", chainMatrix, program)
+	if program.XorCount() != 2 {
+		t.Errorf("zincir: %d işlem, beklenen 2", program.XorCount())
+	}
+}
+
+func TestSolveInPlaceRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix Matrix
+	}{
+		{"kare değil", Matrix{{"1", "1", "0"}, {"0", "1", "1"}}},
+		{"tekil", Matrix{{"1", "1"}, {"1", "1"}}},
+		{"sıfır satır", Matrix{{"1", "0", "0"}, {"0", "0", "0"}, {"0", "0", "1"}}},
+	}
+	for _, tt := range tests {
+		if _, err := SolveInPlace(context.Background(), tt.matrix); err == nil {
+			t.Errorf("%s: hata bekleniyordu", tt.name)
+		}
+		if _, err := ConvertToInPlace(context.Background(), tt.matrix, verifyProgram()); err == nil {
+			t.Errorf("%s: ConvertToInPlace hata bekleniyordu", tt.name)
+		}
+	}
+}
+
+func TestInPlaceProgramLines(t *testing.T) {
+	program := &InPlaceProgram{
+		NumRegisters: 3,
+		Ops:          []InPlaceOp{{Target: 1, Source: 0}, {Target: 2, Source: 1}},
+		Outputs:      []int{0, 1, 2},
+	}
+	want := []string{"x1 ^= x0", "x2 ^= x1", "y0 = x0", "y1 = x1", "y2 = x2"}
+	if got := program.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() = %v, beklenen %v", got, want)
+	}
+	checkInPlace(t, "Lines", chainMatrix, program)
+}
+
+func TestConvertToInPlace(t *testing.T) {
+	// Every gate overwrites an operand that is not needed again
+	program, err := ConvertToInPlace(context.Background(), chainMatrix, chainProgram())
+	if err != nil {
+		t.Fatalf("zincir: beklenmeyen hata: %v", err)
+	}
+	checkInPlace(t, "zincir", chainMatrix, program)
+	if program.XorCount() != 2 {
+		t.Errorf("zincir: %d işlem, beklenen 2 (kapı başına bir)", program.XorCount())
+	}
+
+	rng := rand.New(rand.NewSource(11))
+	for _, matrix := range invertibleMatrices(t, rng, 4, 6, 8) {
+		for _, name := range []string{"paar", "slp"} {
+			result, err := runSolver(context.Background(), name, nil, matrix)
+			if err != nil {
+				t.Fatalf("%s: beklenmeyen hata: %v", name, err)
+			}
+			converted, err := ConvertToInPlace(context.Background(), matrix, result.Program)
+			if err != nil {
+				t.Fatalf("%s: dönüştürme hatası: %v", name, err)
+			}
+			checkInPlace(t, name, matrix, converted)
+		}
+	}
+}
+
+func TestConvertToInPlaceMismatch(t *testing.T) {
+	if _, err := ConvertToInPlace(context.Background(), chainMatrix, NewProgram(2, 3)); err == nil {
+		t.Error("giriş sayısı uyuşmayan program için hata bekleniyordu")
+	}
+}
+
+// A done ctx cuts the search short but the programs stay valid
+func TestInPlaceDoneContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rng := rand.New(rand.NewSource(17))
+	for _, matrix := range invertibleMatrices(t, rng, 6, 10) {
+		program, err := SolveInPlace(canceled, matrix)
+		if err != nil {
+			t.Fatalf("SolveInPlace: beklenmeyen hata: %v", err)
+		}
+		checkInPlace(t, "SolveInPlace", matrix, program)
+
+		result, err := runSolver(context.Background(), "paar", nil, matrix)
+		if err != nil {
+			t.Fatalf("paar: beklenmeyen hata: %v", err)
+		}
+		converted, err := ConvertToInPlace(canceled, matrix, result.Program)
+		if err != nil {
+			t.Fatalf("ConvertToInPlace: beklenmeyen hata: %v", err)
+		}
+		checkInPlace(t, "ConvertToInPlace", matrix, converted)
+	}
+}
+
+func TestInPlaceSolverContext(t *testing.T) {
+	matrix := invertibleMatrices(t, rand.New(rand.NewSource(23)), 8)[0]
+
+	result, err := runSolver(context.Background(), "sxor", nil, matrix)
+	if err != nil {
+		t.Fatalf("beklenmeyen hata: %v", err)
+	}
+	if result.Status != StatusCompleted || result.InPlace == nil {
+		t.Fatalf("durum %q, in_place %v; beklenen completed ve program", result.Status, result.InPlace)
+	}
+	checkInPlace(t, "sxor", matrix, result.InPlace)
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	result, err = runSolver(expired, "sxor", nil, matrix)
+	if err != nil {
+		t.Fatalf("süre dolunca hata yerine sonuç bekleniyordu: %v", err)
+	}
+	if result.Status != StatusTimedOut {
+		t.Errorf("durum %q, beklenen %q", result.Status, StatusTimedOut)
+	}
+	checkInPlace(t, "sxor (süre doldu)", matrix, result.InPlace)
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := runSolver(canceled, "sxor", nil, matrix); err == nil {
+		t.Error("iptal edilen çalışma için hata bekleniyordu")
+	}
+}
+
+func TestBestInPlace(t *testing.T) {
+	matrix := invertibleMatrices(t, rand.New(rand.NewSource(29)), 8)[0]
+	result, err := runSolver(context.Background(), "paar", nil, matrix)
+	if err != nil {
+		t.Fatalf("paar: beklenmeyen hata: %v", err)
+	}
+	record := &MatrixRecord{Results: map[string]*SolverResult{"paar": {Program: result.Program}}}
+
+	solved, err := SolveInPlace(context.Background(), matrix)
+	if err != nil {
+		t.Fatalf("SolveInPlace: beklenmeyen hata: %v", err)
+	}
+	converted, err := ConvertToInPlace(context.Background(), matrix, result.Program)
+	if err != nil {
+		t.Fatalf("ConvertToInPlace: beklenmeyen hata: %v", err)
+	}
+
+	best, source, err := BestInPlace(context.Background(), matrix, record)
+	if err != nil {
+		t.Fatalf("BestInPlace: beklenmeyen hata: %v", err)
+	}
+	checkInPlace(t, "BestInPlace", matrix, best)
+	want, wantSource := solved.XorCount(), SourceInPlaceSolver
+	if converted.XorCount() < want {
+		want, wantSource = converted.XorCount(), "paar"
+	}
+	if best.XorCount() != want || source != wantSource {
+		t.Errorf("BestInPlace = %d (%s), beklenen %d (%s)", best.XorCount(), source, want, wantSource)
+	}
+
+	// Once ctx is done stored programs are no longer converted
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	best, source, err = BestInPlace(canceled, matrix, record)
+	if err != nil {
+		t.Fatalf("BestInPlace (iptal): beklenmeyen hata: %v", err)
+	}
+	checkInPlace(t, "BestInPlace (iptal)", matrix, best)
+	if source != SourceInPlaceSolver {
+		t.Errorf("iptal edilen ctx ile kaynak %q, beklenen %q", source, SourceInPlaceSolver)
+	}
+
+	if _, _, err := BestInPlace(context.Background(), Matrix{{"1", "1"}, {"1", "1"}}, &MatrixRecord{}); err == nil {
+		t.Error("tekil matris için hata bekleniyordu")
+	}
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Algorithm job statuses. A job is queued until a worker leases it, running
+// while the lease lasts, and either completed, queued again for a retry or
+// dead once it used up its attempts.
+const (
+	AlgorithmJobQueued    = "queued"
+	AlgorithmJobRunning   = "running"
+	AlgorithmJobCompleted = "completed"
+	AlgorithmJobDead      = "dead"
+)
+
+// Algorithm job sources, the code path that queued the job
+const (
+	JobSourceImport      = "import"
+	JobSourceGenerator   = "generator"
+	JobSourceInverse     = "inverse"
+	JobSourceRecalculate = "recalculate"
+	JobSourceBulk        = "bulk"
+	JobSourceDepthSweep  = "depth_sweep" // Runs the depth sweep of paretoAlgorithm instead of Algorithms
+)
+
+const (
+	defaultJobMaxAttempts = 5
+	jobLeaseDuration      = 2 * time.Minute  // A running job whose lease is not renewed within this is queued again
+	jobHeartbeatInterval  = 30 * time.Second // How often a worker renews the lease of its job
+	jobPollInterval       = 5 * time.Second  // How often an idle worker looks for jobs queued by other instances
+	jobRetryBaseDelay     = 30 * time.Second // Wait before the second attempt, doubled for every further one
+	jobRetryMaxDelay      = 30 * time.Minute
+	jobPriorityUser       = 10 // Jobs a user is waiting for run before imports and generator jobs
+)
+
+// AlgorithmJob is one queued run of solvers on a stored matrix. Jobs live in
+// algorithm_jobs, so a job survives overload and restarts until a worker
+// completed it or it was dead-lettered.
+type AlgorithmJob struct {
+	ID               int                     `json:"id"`
+	MatrixID         int                     `json:"matrix_id"`
+	Source           string                  `json:"source"`
+	Algorithms       []string                `json:"algorithms,omitempty"` // Empty for the algorithms of the worker pool
+	Params           map[string]SolverParams `json:"params,omitempty"`     // Solver parameters keyed by name
+	ReuseEquivalents bool                    `json:"reuse_equivalents"`    // Take results of stored row/column permutations first
+	Timeout          time.Duration           `json:"-"`                    // Deadline of each solver run, 0 uses import.solver_timeout_seconds
+	Priority         int                     `json:"priority"`             // Higher priorities are leased first
+	Status           string                  `json:"status"`
+	Attempts         int                     `json:"attempts"` // Leases so far, including the running one
+	MaxAttempts      int                     `json:"max_attempts"`
+	RunAfter         time.Time               `json:"run_after"` // Not leased before this, set by retries
+	LeaseOwner       *string                 `json:"lease_owner,omitempty"`
+	LeaseExpiresAt   *time.Time              `json:"lease_expires_at,omitempty"`
+	HeartbeatAt      *time.Time              `json:"heartbeat_at,omitempty"`
+	LastError        *string                 `json:"last_error,omitempty"`
+	CreatedAt        time.Time               `json:"created_at"`
+	UpdatedAt        time.Time               `json:"updated_at"`
+	FinishedAt       *time.Time              `json:"finished_at,omitempty"`
+}
+
+// snapshot returns a copy of the job that later changes to job do not modify
+func (job *AlgorithmJob) snapshot() *AlgorithmJob {
+	copied := *job
+	copied.Algorithms = append([]string(nil), job.Algorithms...)
+	if job.Params != nil {
+		copied.Params = make(map[string]SolverParams, len(job.Params))
+		for name, params := range job.Params {
+			copied.Params[name] = params
+		}
+	}
+	copied.LeaseOwner = copyString(job.LeaseOwner)
+	copied.LeaseExpiresAt = copyTime(job.LeaseExpiresAt)
+	copied.HeartbeatAt = copyTime(job.HeartbeatAt)
+	copied.LastError = copyString(job.LastError)
+	copied.FinishedAt = copyTime(job.FinishedAt)
+	return &copied
+}
+
+// EnqueueAlgorithmJob stores job as queued and wakes an idle worker. The job
+// runs once a worker leases it, possibly after a restart.
+func EnqueueAlgorithmJob(store MatrixStore, job *AlgorithmJob) error {
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = defaultJobMaxAttempts
+	}
+	if err := store.EnqueueAlgorithmJob(job); err != nil {
+		return fmt.Errorf("algoritma işi kuyruğa eklenemedi: %v", err)
+	}
+	if algorithmWorkerPool != nil {
+		algorithmWorkerPool.notify()
+	}
+	return nil
+}
+
+// RetryAlgorithmJob queues a dead job again with fresh attempts; false means
+// the job is not dead
+func RetryAlgorithmJob(store MatrixStore, id int) (bool, error) {
+	retried, err := store.RetryAlgorithmJob(id)
+	if retried && algorithmWorkerPool != nil {
+		algorithmWorkerPool.notify()
+	}
+	return retried, err
+}
+
+// completeAlgorithmJob marks a leased job completed; false means the lease
+// was lost to another worker
+func completeAlgorithmJob(store MatrixStore, job *AlgorithmJob) (bool, error) {
+	now := time.Now().UTC()
+	job.Status, job.LastError, job.FinishedAt = AlgorithmJobCompleted, nil, &now
+	return store.FinishAlgorithmJob(job)
+}
+
+// errJobInterrupted stops a job whose worker is shutting down; the job is
+// released to the queue instead of failing
+var errJobInterrupted = errors.New("sunucu kapatılıyor")
+
+// releaseAlgorithmJob hands a leased job back to the queue when the server
+// stops. remaining are the algorithms the next attempt still has to run.
+func releaseAlgorithmJob(store MatrixStore, job *AlgorithmJob, remaining []string) (bool, error) {
+	if len(remaining) > 0 {
+		job.Algorithms = remaining
+	}
+	return store.ReleaseAlgorithmJob(job)
+}
+
+// permanentJobError marks a job failure a retry cannot fix: the matrix is
+// gone or unreadable, or a solver rejected its algorithm name, parameters or
+// input. Solvers are deterministic in these checks.
+type permanentJobError struct {
+	err error
+}
+
+func (e *permanentJobError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentJobError) Unwrap() error {
+	return e.err
+}
+
+// isPermanentJobError reports whether err should dead-letter a job at once
+func isPermanentJobError(err error) bool {
+	var permanent *permanentJobError
+	return errors.As(err, &permanent)
+}
+
+// failAlgorithmJob queues a leased job again after jobRetryBackoff, or
+// dead-letters it once it used up its attempts or failed permanently.
+// remaining are the algorithms the retry still has to run.
+func failAlgorithmJob(store MatrixStore, job *AlgorithmJob, remaining []string, cause error) (bool, error) {
+	message := cause.Error()
+	now := time.Now().UTC()
+	job.LastError = &message
+	if len(remaining) > 0 {
+		job.Algorithms = remaining
+	}
+	if job.Attempts >= job.MaxAttempts || isPermanentJobError(cause) {
+		job.Status, job.FinishedAt = AlgorithmJobDead, &now
+	} else {
+		job.Status, job.RunAfter = AlgorithmJobQueued, now.Add(jobRetryBackoff(job.Attempts))
+	}
+	return store.FinishAlgorithmJob(job)
+}
+
+// jobRetryBackoff is the wait after a job failed its attempts-th attempt
+func jobRetryBackoff(attempts int) time.Duration {
+	delay := jobRetryBaseDelay
+	for i := 1; i < attempts && delay < jobRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > jobRetryMaxDelay {
+		delay = jobRetryMaxDelay
+	}
+	return delay
+}
+
+// AlgorithmWorker runs the algorithm jobs queued in store with maxWorkers
+// workers. Workers lease one job at a time and renew the lease while the
+// solvers run; the jobs of a crashed instance are queued again once their
+// lease expires.
+type AlgorithmWorker struct {
+	store      MatrixStore    // Where jobs are leased and results are stored
+	owner      string         // Lease owner of this instance
+	wake       chan struct{}  // Signalled when a job is queued in this instance
+	quit       chan bool      // Closed by Stop
+	algorithms []string       // Algorithms run for jobs that do not name their own
+	running    sync.WaitGroup // Workers and the requeue loop, waited for by Stop
+}
+
+var (
+	algorithmWorkerPool *AlgorithmWorker
+	maxWorkers          = 8 // 4-core 16GB sunucu için optimize edildi (2x core count)
+)
+
+// InitAlgorithmWorkerPool starts the workers consuming the jobs of store
+func InitAlgorithmWorkerPool(store MatrixStore, algorithms []string) {
+	hostname, _ := os.Hostname()
+	algorithmWorkerPool = &AlgorithmWorker{
+		store:      store,
+		owner:      fmt.Sprintf("%s/%d/%d", hostname, os.Getpid(), time.Now().Unix()),
+		wake:       make(chan struct{}, maxWorkers),
+		quit:       make(chan bool),
+		algorithms: algorithms,
+	}
+
+	// Start workers
+	algorithmWorkerPool.running.Add(maxWorkers + 1)
+	for i := 0; i < maxWorkers; i++ {
+		go algorithmWorkerPool.worker(i)
+	}
+
+	// Return the jobs of crashed instances to the queue
+	go algorithmWorkerPool.requeueExpired()
+}
+
+// Stop shuts the workers down: idle workers exit, running jobs are cancelled
+// and released to the queue with the algorithms they did not finish. It
+// returns once every worker has exited.
+func (w *AlgorithmWorker) Stop() {
+	close(w.quit)
+	w.running.Wait()
+}
+
+// stopping reports whether Stop was called
+func (w *AlgorithmWorker) stopping() bool {
+	select {
+	case <-w.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// notify wakes an idle worker; the job itself is already stored, so the
+// signal may be dropped when every worker is busy
+func (w *AlgorithmWorker) notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *AlgorithmWorker) worker(id int) {
+	defer w.running.Done()
+	log.Printf("🔧 [WORKER-%d] Algorithm worker başlatıldı", id)
+	for !w.stopping() {
+		job, err := w.store.LeaseAlgorithmJob(w.owner, jobLeaseDuration)
+		if err != nil {
+			log.Printf("❌ [WORKER-%d] Kuyruktan iş alınamadı: %v", id, err)
+		}
+		if job != nil {
+			w.process(id, job)
+			continue
+		}
+
+		select {
+		case <-w.wake:
+		case <-time.After(jobPollInterval):
+		case <-w.quit:
+		}
+	}
+	log.Printf("🔧 [WORKER-%d] Kapatılıyor", id)
+}
+
+// process runs a leased job and records its outcome
+func (w *AlgorithmWorker) process(id int, job *AlgorithmJob) {
+	ctx, cancel := context.WithCancel(context.Background())
+	heartbeatDone := make(chan struct{})
+	go w.heartbeat(ctx, cancel, id, job, heartbeatDone)
+
+	remaining, err := w.run(ctx, id, job)
+	cancel()
+	<-heartbeatDone
+
+	var kept bool
+	var finishErr error
+	switch {
+	case err == nil:
+		kept, finishErr = completeAlgorithmJob(w.store, job)
+	case errors.Is(err, errJobInterrupted):
+		kept, finishErr = releaseAlgorithmJob(w.store, job, remaining)
+	default:
+		kept, finishErr = failAlgorithmJob(w.store, job, remaining, err)
+	}
+	switch {
+	case finishErr != nil:
+		log.Printf("❌ [WORKER-%d] İş %d durumu kaydedilemedi: %v", id, job.ID, finishErr)
+	case !kept:
+		log.Printf("⚠️  [WORKER-%d] İş %d kirası kaybedildi, sonuç başka bir worker'a bırakıldı", id, job.ID)
+	case errors.Is(err, errJobInterrupted):
+		log.Printf("🔄 [WORKER-%d] İş %d kapatma nedeniyle kuyruğa geri bırakıldı: %s", id, job.ID, strings.Join(job.Algorithms, ", "))
+	case job.Status == AlgorithmJobDead && isPermanentJobError(err):
+		log.Printf("❌ [WORKER-%d] İş %d tekrar denenemeyecek bir hatayla başarısız, dead-letter'a alındı: %v", id, job.ID, err)
+	case job.Status == AlgorithmJobDead:
+		log.Printf("❌ [WORKER-%d] İş %d %d denemeden sonra başarısız, dead-letter'a alındı: %v", id, job.ID, job.Attempts, err)
+	case job.Status == AlgorithmJobQueued:
+		log.Printf("🔄 [WORKER-%d] İş %d başarısız (deneme %d/%d), %s sonra tekrar denenecek: %v",
+			id, job.ID, job.Attempts, job.MaxAttempts, time.Until(job.RunAfter).Round(time.Second), err)
+	}
+}
+
+// run calculates the algorithms of a job and stores their results. On
+// failure it also returns the algorithms that still have to run.
+func (w *AlgorithmWorker) run(ctx context.Context, id int, job *AlgorithmJob) ([]string, error) {
+	record, err := w.store.GetMatrixByID(job.MatrixID)
+	if err != nil {
+		return nil, fmt.Errorf("matris alınamadı: %v", err)
+	}
+	if record == nil {
+		return nil, &permanentJobError{fmt.Errorf("matris bulunamadı: %d", job.MatrixID)}
+	}
+	matrix, err := parseMatrixFromBinary(record.MatrixBinary)
+	if err != nil {
+		return nil, &permanentJobError{fmt.Errorf("matris parse edilemedi: %v", err)}
+	}
+	log.Printf("🔧 [WORKER-%d] İşleniyor: %s (iş %d, deneme %d/%d)", id, record.Title, job.ID, job.Attempts, job.MaxAttempts)
+	if job.Source == JobSourceDepthSweep {
+		return nil, w.sweep(ctx, record, matrix, job.Params[paretoAlgorithm])
+	}
+
+	algorithms := job.Algorithms
+	if len(algorithms) == 0 {
+		algorithms = w.algorithms
+	}
+
+	// Reuse the programs of stored row/column permutations of the matrix
+	results := make(map[string]*AlgResult)
+	if job.ReuseEquivalents {
+		results = reuseEquivalentResults(w.store, job.MatrixID, matrix, algorithms)
+		for name, result := range results {
+			log.Printf("♻️  [WORKER-%d] %s eşdeğer matristen yeniden kullanıldı - XOR: %d", id, name, result.XorCount)
+		}
+	}
+
+	// Calculate the remaining algorithms
+	var failed, failures []string
+	retryable, interrupted := false, false
+	for _, name := range algorithms {
+		if results[name] != nil {
+			continue
+		}
+		solverCtx, cancelSolver := withSolverDeadline(ctx, job.Timeout)
+		result, err := runSolver(solverCtx, name, job.Params[name], matrix)
+		cancelSolver()
+		if ctx.Err() != nil {
+			if !w.stopping() {
+				return nil, fmt.Errorf("iş kirası kaybedildi")
+			}
+			interrupted = true
+			break
+		}
+		if err != nil {
+			log.Printf("❌ [WORKER-%d] %s hatası: %v", id, name, err)
+			failed = append(failed, name)
+			failures = append(failures, fmt.Sprintf("%s=%v", name, err))
+			retryable = retryable || deadlineExceeded(err)
+			continue
+		}
+		if result.Status == StatusTimedOut {
+			log.Printf("⏱️  [WORKER-%d] %s süre sınırına ulaştı - en iyi XOR: %d", id, name, result.XorCount)
+		} else {
+			log.Printf("✅ [WORKER-%d] %s tamamlandı - XOR: %d", id, name, result.XorCount)
+		}
+		results[name] = result
+	}
+
+	// Keep what succeeded; a retry only runs the failed algorithms. Solver
+	// errors other than a deadline would fail the same way again.
+	if len(results) > 0 {
+		if err := UpdateMatrixResults(w.store, job.MatrixID, results); err != nil {
+			return nil, fmt.Errorf("sonuçlar kaydedilemedi: %v", err)
+		}
+	}
+	if interrupted {
+		var pending []string
+		for _, name := range algorithms {
+			if results[name] == nil {
+				pending = append(pending, name)
+			}
+		}
+		return pending, errJobInterrupted
+	}
+	if len(failures) > 0 {
+		err := fmt.Errorf("algoritma hataları: %s", strings.Join(failures, ", "))
+		if !retryable {
+			err = &permanentJobError{err}
+		}
+		return failed, err
+	}
+	log.Printf("✅ [WORKER-%d] Tamamlandı: %s", id, record.Title)
+	return nil, nil
+}
+
+// sweep runs the depth sweep of a JobSourceDepthSweep job. An interrupted
+// sweep starts over on the next attempt; the runs it stored are kept.
+func (w *AlgorithmWorker) sweep(ctx context.Context, record *MatrixRecord, matrix Matrix, params SolverParams) error {
+	log.Printf("🔄 [PARETO] %s için derinlik taraması başlıyor", record.Title)
+	err := runDepthSweep(ctx, matrix, params, func(result *AlgResult) error {
+		if _, err := w.store.SaveAlgorithmRun(record.ID, paretoAlgorithm, result, false); err != nil {
+			return err
+		}
+		return RefreshParetoFront(w.store, record)
+	})
+	if ctx.Err() != nil {
+		if !w.stopping() {
+			return fmt.Errorf("iş kirası kaybedildi")
+		}
+		return errJobInterrupted
+	}
+	if err != nil {
+		return fmt.Errorf("derinlik taraması durdu: %v", err)
+	}
+	log.Printf("✅ [PARETO] %s derinlik taraması tamamlandı", record.Title)
+	return nil
+}
+
+// heartbeat renews the lease of job until ctx is done. It cancels the job
+// when the lease was lost, e.g. because it expired and another worker took
+// the job over, or when the worker is stopped.
+func (w *AlgorithmWorker) heartbeat(ctx context.Context, cancel context.CancelFunc, id int, job *AlgorithmJob, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(jobHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.quit:
+			cancel()
+			return
+		case <-ticker.C:
+			renewed, err := w.store.RenewAlgorithmJobLease(job, jobLeaseDuration)
+			if err != nil {
+				log.Printf("⚠️  [WORKER-%d] İş %d kirası yenilenemedi: %v", id, job.ID, err)
+				continue
+			}
+			if !renewed {
+				log.Printf("⚠️  [WORKER-%d] İş %d kirası kaybedildi, hesaplama durduruluyor", id, job.ID)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// requeueExpired queues the jobs whose lease expired again, at start and then
+// periodically; these were left running by a crashed or stalled worker
+func (w *AlgorithmWorker) requeueExpired() {
+	defer w.running.Done()
+	ticker := time.NewTicker(jobLeaseDuration / 2)
+	defer ticker.Stop()
+	for {
+		requeued, err := w.store.RequeueExpiredAlgorithmJobs()
+		if err != nil {
+			log.Printf("❌ [QUEUE] Süresi dolan işler kuyruğa geri alınamadı: %v", err)
+		} else if requeued > 0 {
+			log.Printf("♻️  [QUEUE] Kirası dolan %d iş kuyruğa geri alındı", requeued)
+			for i := 0; i < requeued; i++ {
+				w.notify()
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-w.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// enqueueTestJob queues job on a new matrix of store and returns it
+func enqueueTestJob(t *testing.T, store MatrixStore, job *AlgorithmJob) *AlgorithmJob {
+	t.Helper()
+	if job.MatrixID == 0 {
+		record, err := SaveMatrix(store, fmt.Sprintf("kuyruk %d", time.Now().UnixNano()), chainMatrix, "")
+		if err != nil {
+			t.Fatalf("SaveMatrix: %v", err)
+		}
+		job.MatrixID = record.ID
+	}
+	if job.Source == "" {
+		job.Source = JobSourceImport
+	}
+	if err := EnqueueAlgorithmJob(store, job); err != nil {
+		t.Fatalf("EnqueueAlgorithmJob: %v", err)
+	}
+	return job
+}
+
+// leaseTestJob leases the next due job for owner and fails unless it is want
+func leaseTestJob(t *testing.T, store MatrixStore, owner string, lease time.Duration, want *AlgorithmJob) *AlgorithmJob {
+	t.Helper()
+	job, err := store.LeaseAlgorithmJob(owner, lease)
+	if err != nil {
+		t.Fatalf("LeaseAlgorithmJob: %v", err)
+	}
+	if job == nil || job.ID != want.ID {
+		t.Fatalf("kiralanan iş %+v, beklenen %d", job, want.ID)
+	}
+	if job.Status != AlgorithmJobRunning || job.LeaseOwner == nil || *job.LeaseOwner != owner {
+		t.Fatalf("kiralanan iş %s/%v, beklenen running/%s", job.Status, job.LeaseOwner, owner)
+	}
+	return job
+}
+
+// storedJob reads a job back from store
+func storedJob(t *testing.T, store MatrixStore, id int) *AlgorithmJob {
+	t.Helper()
+	job, err := store.GetAlgorithmJob(id)
+	if err != nil || job == nil {
+		t.Fatalf("GetAlgorithmJob(%d) = %v, %v", id, job, err)
+	}
+	return job
+}
+
+// expectNoDueJob fails if a job can be leased
+func expectNoDueJob(t *testing.T, store MatrixStore) {
+	t.Helper()
+	if job, err := store.LeaseAlgorithmJob("test", time.Minute); err != nil || job != nil {
+		t.Fatalf("kiralanabilir iş kalmamalıydı: %+v, %v", job, err)
+	}
+}
+
+func TestJobRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, jobRetryBaseDelay},
+		{2, 2 * jobRetryBaseDelay},
+		{3, 4 * jobRetryBaseDelay},
+		{6, 32 * jobRetryBaseDelay},
+		{7, jobRetryMaxDelay},
+		{50, jobRetryMaxDelay},
+	}
+	for _, tt := range tests {
+		if got := jobRetryBackoff(tt.attempts); got != tt.want {
+			t.Errorf("jobRetryBackoff(%d) = %v, beklenen %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestStoreAlgorithmJobQueue(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store MatrixStore) {
+		imported := enqueueTestJob(t, store, &AlgorithmJob{ReuseEquivalents: true})
+		user := enqueueTestJob(t, store, &AlgorithmJob{
+			MatrixID:   imported.MatrixID,
+			Source:     JobSourceRecalculate,
+			Algorithms: []string{"boyar", "paar"},
+			Params:     map[string]SolverParams{"boyar": {"depth_limit": 3}},
+			Priority:   jobPriorityUser,
+		})
+		if imported.Status != AlgorithmJobQueued || imported.MaxAttempts != defaultJobMaxAttempts {
+			t.Fatalf("kuyruğa eklenen iş %s, %d deneme hakkı", imported.Status, imported.MaxAttempts)
+		}
+
+		// Higher priorities are leased first; the job comes back as stored
+		leased := leaseTestJob(t, store, "a", time.Minute, user)
+		if leased.Attempts != 1 || fmt.Sprint(leased.Algorithms) != "[boyar paar]" || leased.Params["boyar"].Int("depth_limit", 0) != 3 {
+			t.Errorf("kiralanan iş %d deneme, %v, %v", leased.Attempts, leased.Algorithms, leased.Params)
+		}
+		second := leaseTestJob(t, store, "a", time.Minute, imported)
+		if !second.ReuseEquivalents || len(second.Algorithms) != 0 {
+			t.Errorf("import işi %v, %v", second.ReuseEquivalents, second.Algorithms)
+		}
+		expectNoDueJob(t, store)
+
+		if kept, err := completeAlgorithmJob(store, leased); err != nil || !kept {
+			t.Fatalf("completeAlgorithmJob = %v, %v", kept, err)
+		}
+		done := storedJob(t, store, user.ID)
+		if done.Status != AlgorithmJobCompleted || done.FinishedAt == nil || done.LeaseOwner != nil || done.LastError != nil {
+			t.Errorf("tamamlanan iş %s, bitiş %v, kira %v, hata %v", done.Status, done.FinishedAt, done.LeaseOwner, done.LastError)
+		}
+
+		jobs, err := store.GetAlgorithmJobs("", imported.MatrixID, 10)
+		if err != nil || len(jobs) != 2 || jobs[0].ID != user.ID {
+			t.Errorf("matrisin işleri %v, %v; beklenen yeniden eskiye 2 iş", jobs, err)
+		}
+		jobs, err = store.GetAlgorithmJobs(AlgorithmJobRunning, 0, 10)
+		if err != nil || len(jobs) != 1 || jobs[0].ID != imported.ID {
+			t.Errorf("çalışan işler %v, %v", jobs, err)
+		}
+	})
+}
+
+func TestStoreAlgorithmJobLeaseExpiry(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store MatrixStore) {
+		job := enqueueTestJob(t, store, &AlgorithmJob{MaxAttempts: 2})
+
+		// An expired lease queues the job again
+		first := leaseTestJob(t, store, "a", -time.Second, job)
+		if requeued, err := store.RequeueExpiredAlgorithmJobs(); err != nil || requeued != 1 {
+			t.Fatalf("RequeueExpiredAlgorithmJobs = %d, %v; beklenen 1", requeued, err)
+		}
+		if stored := storedJob(t, store, job.ID); stored.Status != AlgorithmJobQueued || stored.LastError == nil || stored.LeaseOwner != nil {
+			t.Fatalf("süresi dolan iş %s, hata %v, kira %v", stored.Status, stored.LastError, stored.LeaseOwner)
+		}
+
+		// The stale lease of the same owner is fenced by the attempt number
+		second := leaseTestJob(t, store, "a", -time.Second, job)
+		if second.Attempts != 2 {
+			t.Fatalf("ikinci kira %d. deneme", second.Attempts)
+		}
+		if renewed, err := store.RenewAlgorithmJobLease(first, time.Minute); err != nil || renewed {
+			t.Errorf("eski kira yenilendi: %v, %v", renewed, err)
+		}
+		if kept, err := completeAlgorithmJob(store, first); err != nil || kept {
+			t.Errorf("eski kira işi tamamladı: %v, %v", kept, err)
+		}
+		if released, err := releaseAlgorithmJob(store, first, nil); err != nil || released {
+			t.Errorf("eski kira işi bıraktı: %v, %v", released, err)
+		}
+		if stored := storedJob(t, store, job.ID); stored.Status != AlgorithmJobRunning || stored.Attempts != 2 {
+			t.Fatalf("iş eski kirayla değişti: %s, %d deneme", stored.Status, stored.Attempts)
+		}
+
+		// Once the attempts are used up an expired lease dead-letters the job
+		if requeued, err := store.RequeueExpiredAlgorithmJobs(); err != nil || requeued != 0 {
+			t.Fatalf("RequeueExpiredAlgorithmJobs = %d, %v; beklenen 0", requeued, err)
+		}
+		dead := storedJob(t, store, job.ID)
+		if dead.Status != AlgorithmJobDead || dead.FinishedAt == nil || dead.LastError == nil {
+			t.Fatalf("deneme hakkı biten iş %s, bitiş %v, hata %v", dead.Status, dead.FinishedAt, dead.LastError)
+		}
+		if kept, err := completeAlgorithmJob(store, second); err != nil || kept {
+			t.Errorf("süresi dolan kira işi tamamladı: %v, %v", kept, err)
+		}
+		expectNoDueJob(t, store)
+
+		// A retry starts over with fresh attempts and no error
+		if retried, err := RetryAlgorithmJob(store, job.ID); err != nil || !retried {
+			t.Fatalf("RetryAlgorithmJob = %v, %v", retried, err)
+		}
+		if retried, err := RetryAlgorithmJob(store, job.ID); err != nil || retried {
+			t.Errorf("dead olmayan iş tekrar denendi: %v, %v", retried, err)
+		}
+		if stored := storedJob(t, store, job.ID); stored.Status != AlgorithmJobQueued || stored.Attempts != 0 || stored.LastError != nil || stored.FinishedAt != nil {
+			t.Fatalf("tekrar denenen iş %s, %d deneme, hata %v, bitiş %v", stored.Status, stored.Attempts, stored.LastError, stored.FinishedAt)
+		}
+
+		// Another owner's copy of a lease is fenced by the owner
+		third := leaseTestJob(t, store, "b", time.Minute, job)
+		impostor := third.snapshot()
+		owner := "a"
+		impostor.LeaseOwner = &owner
+		if renewed, err := store.RenewAlgorithmJobLease(impostor, time.Minute); err != nil || renewed {
+			t.Errorf("başka sahibin kirası yenilendi: %v, %v", renewed, err)
+		}
+		if renewed, err := store.RenewAlgorithmJobLease(third, time.Hour); err != nil || !renewed {
+			t.Fatalf("geçerli kira yenilenemedi: %v, %v", renewed, err)
+		}
+		if third.LeaseExpiresAt == nil || time.Until(*third.LeaseExpiresAt) < 59*time.Minute {
+			t.Errorf("yenilenen kira %v", third.LeaseExpiresAt)
+		}
+		if requeued, err := store.RequeueExpiredAlgorithmJobs(); err != nil || requeued != 0 {
+			t.Errorf("geçerli kira kuyruğa geri alındı: %d, %v", requeued, err)
+		}
+	})
+}
+
+func TestStoreAlgorithmJobFailures(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store MatrixStore) {
+		// A failed attempt is retried after the backoff with the failed algorithms
+		retried := enqueueTestJob(t, store, &AlgorithmJob{Algorithms: []string{"boyar", "paar"}, MaxAttempts: 3})
+		leased := leaseTestJob(t, store, "a", time.Minute, retried)
+		if kept, err := failAlgorithmJob(store, leased, []string{"paar"}, errors.New("geçici hata")); err != nil || !kept {
+			t.Fatalf("failAlgorithmJob = %v, %v", kept, err)
+		}
+		stored := storedJob(t, store, retried.ID)
+		if stored.Status != AlgorithmJobQueued || fmt.Sprint(stored.Algorithms) != "[paar]" || stored.LastError == nil || *stored.LastError != "geçici hata" {
+			t.Fatalf("başarısız iş %s, %v, hata %v", stored.Status, stored.Algorithms, stored.LastError)
+		}
+		if wait := time.Until(stored.RunAfter); wait < jobRetryBaseDelay-5*time.Second || wait > jobRetryBaseDelay {
+			t.Errorf("ilk tekrar %v sonra, beklenen %v", wait, jobRetryBaseDelay)
+		}
+		expectNoDueJob(t, store)
+
+		// Failures a retry cannot fix dead-letter the job at once
+		permanent := enqueueTestJob(t, store, &AlgorithmJob{})
+		leased = leaseTestJob(t, store, "a", time.Minute, permanent)
+		cause := &permanentJobError{errors.New("matris bulunamadı")}
+		if kept, err := failAlgorithmJob(store, leased, nil, cause); err != nil || !kept {
+			t.Fatalf("failAlgorithmJob = %v, %v", kept, err)
+		}
+		if stored := storedJob(t, store, permanent.ID); stored.Status != AlgorithmJobDead || stored.Attempts != 1 || stored.FinishedAt == nil {
+			t.Errorf("kalıcı hatalı iş %s, %d deneme, bitiş %v", stored.Status, stored.Attempts, stored.FinishedAt)
+		}
+		if !isPermanentJobError(fmt.Errorf("sarılmış: %w", cause)) || isPermanentJobError(errors.New("geçici")) {
+			t.Error("isPermanentJobError sarılmış hataları ayırt etmiyor")
+		}
+
+		// So does the failure of the last attempt
+		last := enqueueTestJob(t, store, &AlgorithmJob{MaxAttempts: 1})
+		leased = leaseTestJob(t, store, "a", time.Minute, last)
+		if kept, err := failAlgorithmJob(store, leased, nil, errors.New("geçici hata")); err != nil || !kept {
+			t.Fatalf("failAlgorithmJob = %v, %v", kept, err)
+		}
+		if stored := storedJob(t, store, last.ID); stored.Status != AlgorithmJobDead {
+			t.Errorf("son denemesi başarısız iş %s", stored.Status)
+		}
+
+		// A job released on shutdown is due at once and keeps its attempts
+		released := enqueueTestJob(t, store, &AlgorithmJob{Algorithms: []string{"boyar", "slp"}, MaxAttempts: 1})
+		leased = leaseTestJob(t, store, "a", time.Minute, released)
+		if kept, err := releaseAlgorithmJob(store, leased, []string{"slp"}); err != nil || !kept {
+			t.Fatalf("releaseAlgorithmJob = %v, %v", kept, err)
+		}
+		stored = storedJob(t, store, released.ID)
+		if stored.Status != AlgorithmJobQueued || stored.Attempts != 0 || fmt.Sprint(stored.Algorithms) != "[slp]" || stored.LeaseOwner != nil {
+			t.Fatalf("bırakılan iş %s, %d deneme, %v, kira %v", stored.Status, stored.Attempts, stored.Algorithms, stored.LeaseOwner)
+		}
+		if again := leaseTestJob(t, store, "b", time.Minute, released); again.Attempts != 1 {
+			t.Errorf("bırakılan iş %d. denemede", again.Attempts)
+		}
+	})
+}
+
+func TestAlgorithmWorkerReleasesJobsOnStop(t *testing.T) {
+	store := NewMemoryStore()
+	record, err := SaveMatrix(store, "kapatma", randomMatrix(rand.New(rand.NewSource(5)), 14, 14, 0.5), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// paar finishes quickly, the unbounded exact search runs until Stop
+	InitAlgorithmWorkerPool(store, DefaultAlgorithms())
+	job := enqueueTestJob(t, store, &AlgorithmJob{
+		MatrixID:   record.ID,
+		Algorithms: []string{"paar", "exact"},
+		Params:     map[string]SolverParams{"exact": {"max_nodes": 1 << 40}},
+	})
+	deadline := time.Now().Add(30 * time.Second)
+	for storedJob(t, store, job.ID).Status != AlgorithmJobRunning {
+		if time.Now().After(deadline) {
+			t.Fatal("iş kiralanmadı")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(500 * time.Millisecond)
+	algorithmWorkerPool.Stop()
+	algorithmWorkerPool = nil
+
+	stored := storedJob(t, store, job.ID)
+	if stored.Status != AlgorithmJobQueued || stored.Attempts != 0 || fmt.Sprint(stored.Algorithms) != "[exact]" || stored.LastError != nil {
+		t.Fatalf("kapatmada bırakılan iş %s, %d deneme, %v, hata %v", stored.Status, stored.Attempts, stored.Algorithms, stored.LastError)
+	}
+	if updated, err := store.GetMatrixByID(record.ID); err != nil || updated.Result("paar") == nil {
+		t.Errorf("biten paar sonucu kaydedilmedi: %v", err)
+	}
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"math"
+	"math/bits"
+	"math/rand"
 	"net/http"
-	"strconv"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -31,10 +36,19 @@ type Response struct {
 
 // AlgResult represents result for one matrix
 type AlgResult struct {
-	MatrixIndex int      `json:"matrix_index"`
-	XorCount    int      `json:"xor_count"`
-	Program     []string `json:"program"`
-	Depth       int      `json:"depth,omitempty"`
+	MatrixIndex int             `json:"matrix_index"`
+	XorCount    int             `json:"xor_count"`
+	Program     *Program        `json:"program"`
+	Depth       int             `json:"depth,omitempty"`
+	Seed        *int64          `json:"seed,omitempty"`        // Seed of the winning start in randomized mode
+	Status      string          `json:"status,omitempty"`      // StatusCompleted or StatusTimedOut
+	DepthLimit  int             `json:"depth_limit,omitempty"` // Depth bound of solvers with depth, 0 for the others
+	PeakLive    int             `json:"peak_live"`             // Temporaries live at once after register-pressure scheduling
+	Origin      string          `json:"origin,omitempty"`      // OriginDirect or OriginTranspose when solved with the transpose option
+	LowerBound  int             `json:"lower_bound,omitempty"` // XOR count the exact search proved no program can go below
+	InPlace     *InPlaceProgram `json:"in_place,omitempty"`    // s-XOR form of solvers with in-place programs
+	Parameters  SolverParams    `json:"parameters,omitempty"`  // Effective solver parameters, set by runSolver
+	DurationMs  int64           `json:"duration_ms"`           // Wall-clock time of the run, set by runSolver
 }
 
 // Constants for array sizes - optimized for 4-core 16GB server
@@ -43,33 +57,98 @@ const (
 	MAX_ITERATIONS = 50000 // Increased for more thorough calculations
 )
 
+// defaultBoyarDepthLimit is the depth bound used when neither the request nor
+// import.boyar_depth_limit sets one
+const defaultBoyarDepthLimit = 10
+
+// maxBoyarDepthLimit keeps the 2^depth path budgets of reachable within uint64
+const maxBoyarDepthLimit = 63
+
+// boyarDepthLimit is the depth bound of runs without a depth_limit parameter,
+// set from import.boyar_depth_limit
+var boyarDepthLimit = defaultBoyarDepthLimit
+
+// minimumDepth returns the smallest depth any XOR circuit computing rows can
+// have: a row of weight w needs ceil(log2(w)) levels of two-input gates
+func minimumDepth(rows []BitVector) int {
+	depth := 0
+	for _, row := range rows {
+		if w := row.PopCount(); w > 1 {
+			if d := bits.Len(uint(w - 1)); d > depth {
+				depth = d
+			}
+		}
+	}
+	return depth
+}
+
+// reachableCheckInterval is how many reachable calls run between two polls of
+// the solve context, so one expensive distance computation can still be stopped
+const reachableCheckInterval = 1 << 16
+
 // BoyarSLP implementation
 type BoyarSLP struct {
-	NumInputs    int
-	DepthLimit   int
-	NumTargets   int
-	ProgramSize  int
-	Target       []uint64
-	Dist         []int
-	NDist        []int
-	Base         []uint64
-	BaseSize     int
-	TargetsFound int
-	Result       []string
-	Depth        []int
-	MaxDepth     int
+	NumInputs     int
+	DepthLimit    int
+	NumTargets    int
+	ProgramSize   int
+	Target        []BitVector
+	Dist          []int
+	NDist         []int
+	Base          []BitVector
+	BaseSize      int
+	TargetsFound  int
+	Depth         []int
+	MaxDepth      int
+	MaxIterations int               // Iteration budget of the main loop
+	MaxGates      int               // Abort once the program has more gates, 0 for no bound
+	Random        *RandomizedSearch // Randomized multi-start mode, nil for the deterministic solver
+
+	program *Program    // Gate list; base element i is signal i
+	scratch []BitVector // Per-level buffers for reachable, indexed by K
+	tmp     BitVector   // Buffer for Target ^ newBase in NewDistance
+	cand    BitVector   // Buffer for candidate base elements
+	rng     *rand.Rand  // Random tie-breaking within a single start, nil for first-found
+
+	ctx     context.Context // Context of the running Solve
+	steps   int             // reachable calls since the start of Solve
+	stopped bool            // Set once ctx is done; reachable then fails fast
 }
 
 func NewBoyarSLP(depthLimit int) *BoyarSLP {
 	return &BoyarSLP{
-		DepthLimit: depthLimit,
-		Target:     make([]uint64, MAX_ARRAY_SIZE),
-		Dist:       make([]int, MAX_ARRAY_SIZE),
-		NDist:      make([]int, MAX_ARRAY_SIZE),
-		Base:       make([]uint64, MAX_ARRAY_SIZE),
-		Result:     make([]string, MAX_ARRAY_SIZE),
-		Depth:      make([]int, MAX_ARRAY_SIZE),
+		DepthLimit:    depthLimit,
+		MaxIterations: MAX_ITERATIONS,
+		Target:        make([]BitVector, MAX_ARRAY_SIZE),
+		Dist:          make([]int, MAX_ARRAY_SIZE),
+		NDist:         make([]int, MAX_ARRAY_SIZE),
+		Base:          make([]BitVector, MAX_ARRAY_SIZE),
+		Depth:         make([]int, MAX_ARRAY_SIZE),
+	}
+}
+
+// newStart returns a fresh deterministic solver with every setting of b for
+// one randomized start
+func (b *BoyarSLP) newStart(rng *rand.Rand) *BoyarSLP {
+	start := NewBoyarSLP(b.DepthLimit)
+	start.MaxIterations = b.MaxIterations
+	start.MaxGates = b.MaxGates
+	start.rng = rng
+	return start
+}
+
+// Name returns the registry name of the solver
+func (b *BoyarSLP) Name() string {
+	return "boyar"
+}
+
+// Parameters returns the effective solver parameters
+func (b *BoyarSLP) Parameters() SolverParams {
+	params := SolverParams{"depth_limit": b.DepthLimit, "max_iterations": b.MaxIterations}
+	if b.MaxGates > 0 {
+		params["max_gates"] = b.MaxGates
 	}
+	return b.Random.addParameters(params)
 }
 
 func (b *BoyarSLP) ReadTargetMatrix(matrix Matrix) error {
@@ -80,23 +159,22 @@ func (b *BoyarSLP) ReadTargetMatrix(matrix Matrix) error {
 	if b.NumTargets >= MAX_ARRAY_SIZE {
 		return fmt.Errorf("matris çok büyük: %d >= %d", b.NumTargets, MAX_ARRAY_SIZE)
 	}
-	b.NumInputs = len(matrix[0])
+
+	rows, numInputs, err := parseBinaryRows(matrix)
+	if err != nil {
+		return err
+	}
+	b.NumInputs = numInputs
 	if b.NumInputs >= MAX_ARRAY_SIZE {
 		return fmt.Errorf("matris genişliği çok büyük: %d >= %d", b.NumInputs, MAX_ARRAY_SIZE)
 	}
 
 	for i := 0; i < b.NumTargets; i++ {
-		var powerOfTwo uint64 = 1
-		b.Target[i] = 0
-		b.Dist[i] = -1
-
-		for j := 0; j < b.NumInputs; j++ {
-			bit, _ := strconv.Atoi(strings.TrimSpace(matrix[i][j]))
-			if bit == 1 {
-				b.Dist[i]++
-				b.Target[i] = b.Target[i] + powerOfTwo
-			}
-			powerOfTwo = powerOfTwo * 2
+		b.Target[i] = rows[i]
+		// Zero rows need no gate and count as found from the start
+		b.Dist[i] = b.Target[i].PopCount() - 1
+		if b.Dist[i] < 0 {
+			b.Dist[i] = 0
 		}
 	}
 	return nil
@@ -105,49 +183,55 @@ func (b *BoyarSLP) ReadTargetMatrix(matrix Matrix) error {
 func (b *BoyarSLP) InitBase() error {
 	b.TargetsFound = 0
 	b.ProgramSize = 0
-	b.Result = make([]string, MAX_ARRAY_SIZE)
-	b.Base[0] = 1
-	b.Depth[0] = 0
 	b.MaxDepth = 0
+	b.program = NewProgram(b.NumInputs, b.NumTargets)
 
-	for i := 1; i < b.NumInputs; i++ {
+	for i := 0; i < b.NumInputs; i++ {
 		if i >= MAX_ARRAY_SIZE {
 			return fmt.Errorf("base array overflow: %d >= %d", i, MAX_ARRAY_SIZE)
 		}
-		b.Base[i] = 2 * b.Base[i-1]
+		b.Base[i] = UnitBitVector(b.NumInputs, i)
 		b.Depth[i] = 0
 	}
 	b.BaseSize = b.NumInputs
 
+	b.scratch = make([]BitVector, b.NumInputs+1)
+	for i := range b.scratch {
+		b.scratch[i] = NewBitVector(b.NumInputs)
+	}
+	b.tmp = NewBitVector(b.NumInputs)
+	b.cand = NewBitVector(b.NumInputs)
+
+	b.TargetsFound = b.countFound()
+	return nil
+}
+
+// countFound returns the number of targets that are already in the base
+func (b *BoyarSLP) countFound() int {
+	found := 0
 	for i := 0; i < b.NumTargets; i++ {
 		if b.Dist[i] == 0 {
-			b.TargetsFound++
-			for j := 0; j < b.NumInputs; j++ {
-				if b.Base[j] == b.Target[i] {
-					b.Result = append(b.Result, fmt.Sprintf("y%d = x%d", i, j))
-					break
-				}
-			}
+			found++
 		}
 	}
-	return nil
+	return found
 }
 
-func (b *BoyarSLP) isTarget(x uint64) bool {
+func (b *BoyarSLP) isTarget(x BitVector) bool {
 	for i := 0; i < b.NumTargets; i++ {
-		if x == b.Target[i] {
+		if x.Equal(b.Target[i]) {
 			return true
 		}
 	}
 	return false
 }
 
-func (b *BoyarSLP) isBase(x uint64) bool {
-	if x == 0 {
+func (b *BoyarSLP) isBase(x BitVector) bool {
+	if x.IsZero() {
 		return false
 	}
 	for i := 0; i < b.BaseSize; i++ {
-		if x == b.Base[i] {
+		if x.Equal(b.Base[i]) {
 			return true
 		}
 	}
@@ -161,26 +245,44 @@ func (b *BoyarSLP) max(a, c int) int {
 	return c
 }
 
-func (b *BoyarSLP) reachable(T uint64, K, S int, L uint64) bool {
+// interrupted polls the solve context every reachableCheckInterval calls
+func (b *BoyarSLP) interrupted() bool {
+	if b.stopped {
+		return true
+	}
+	b.steps++
+	if b.steps%reachableCheckInterval == 0 && b.ctx != nil && b.ctx.Err() != nil {
+		b.stopped = true
+	}
+	return b.stopped
+}
+
+func (b *BoyarSLP) reachable(T BitVector, K, S int, L uint64) bool {
+	if b.interrupted() {
+		return false
+	}
 	if (b.BaseSize-S) < K {
 		return false
 	}
 	if L < 1 {
 		return false
 	}
-	if K == 0 {
+	if K <= 0 {
 		return false
 	}
 	if K == 1 {
 		for i := S; i < b.BaseSize; i++ {
-			if T == b.Base[i] && uint64(math.Pow(2, float64(b.Depth[i]))) <= L {
+			if T.Equal(b.Base[i]) && uint64(math.Pow(2, float64(b.Depth[i]))) <= L {
 				return true
 			}
 		}
 		return false
 	}
 
-	if b.reachable(T^b.Base[S], K-1, S+1, L-uint64(math.Pow(2, float64(b.Depth[S])))) {
+	// scratch[K-1] is only used by the K-1 subtree, so T (scratch[K]) stays intact
+	next := b.scratch[K-1]
+	next.XorInto(T, b.Base[S])
+	if b.reachable(next, K-1, S+1, L-uint64(math.Pow(2, float64(b.Depth[S])))) {
 		return true
 	}
 	if b.reachable(T, K, S+1, L) {
@@ -189,20 +291,21 @@ func (b *BoyarSLP) reachable(T uint64, K, S int, L uint64) bool {
 	return false
 }
 
-func (b *BoyarSLP) NewDistance(u int, newBase uint64, depthNewBase uint64) int {
-	if b.Target[u] == 0 {
+func (b *BoyarSLP) NewDistance(u int, newBase BitVector, depthNewBase uint64) int {
+	if b.Target[u].IsZero() {
 		return 0
 	}
-	if b.isBase(b.Target[u]) || newBase == b.Target[u] {
+	if b.isBase(b.Target[u]) || newBase.Equal(b.Target[u]) {
 		return 0
 	}
-	if b.reachable(b.Target[u]^newBase, b.Dist[u]-1, 0, uint64(math.Pow(2, float64(b.DepthLimit)))-depthNewBase) {
+	b.tmp.XorInto(b.Target[u], newBase)
+	if b.reachable(b.tmp, b.Dist[u]-1, 0, uint64(math.Pow(2, float64(b.DepthLimit)))-depthNewBase) {
 		return b.Dist[u] - 1
 	}
 	return b.Dist[u]
 }
 
-func (b *BoyarSLP) TotalDistance(newBase uint64, depthNewBase uint64) int {
+func (b *BoyarSLP) TotalDistance(newBase BitVector, depthNewBase uint64) int {
 	D := 0
 	for i := 0; i < b.NumTargets; i++ {
 		t := b.NewDistance(i, newBase, depthNewBase)
@@ -214,10 +317,18 @@ func (b *BoyarSLP) TotalDistance(newBase uint64, depthNewBase uint64) int {
 
 func (b *BoyarSLP) EasyMove() bool {
 	t := -1
+	candidates := 0
 	for i := 0; i < b.NumTargets; i++ {
 		if b.Dist[i] == 1 {
-			t = i
-			break
+			if b.rng == nil {
+				t = i
+				break
+			}
+			// Pick uniformly among all targets at distance one
+			candidates++
+			if b.rng.Intn(candidates) == 0 {
+				t = i
+			}
 		}
 	}
 	if t == -1 {
@@ -229,14 +340,14 @@ func (b *BoyarSLP) EasyMove() bool {
 		return false
 	}
 
-	newBase := b.Target[t]
+	newBase := b.Target[t].Clone()
 	b.Base[b.BaseSize] = newBase
 	b.BaseSize++
 
 	depthNewBase := uint64(math.Pow(2, float64(b.DepthLimit)))
 	for i := 0; i < b.BaseSize; i++ {
 		for j := i + 1; j < b.BaseSize; j++ {
-			if (b.Base[i]^b.Base[j]) == b.Base[b.BaseSize-1] {
+			if b.Base[b.BaseSize-1].XorEquals(b.Base[i], b.Base[j]) {
 				newDepth := uint64(math.Pow(2, float64(b.max(b.Depth[i], b.Depth[j])+1)))
 				if depthNewBase > newDepth {
 					depthNewBase = newDepth
@@ -249,28 +360,17 @@ func (b *BoyarSLP) EasyMove() bool {
 		b.Dist[u] = b.NewDistance(u, newBase, depthNewBase)
 	}
 	b.ProgramSize++
-	b.TargetsFound++
+	b.TargetsFound = b.countFound()
 
 	// Find which bases created this target
 	for i := 0; i < b.BaseSize; i++ {
 		for j := i + 1; j < b.BaseSize; j++ {
-			if (b.Base[i]^b.Base[j]) == b.Base[b.BaseSize-1] {
+			if b.Base[b.BaseSize-1].XorEquals(b.Base[i], b.Base[j]) {
 				b.Depth[b.BaseSize-1] = b.max(b.Depth[i], b.Depth[j]) + 1
 				if b.Depth[b.BaseSize-1] > b.MaxDepth {
 					b.MaxDepth = b.Depth[b.BaseSize-1]
 				}
-				var iStr, jStr string
-				if i < b.NumInputs {
-					iStr = fmt.Sprintf("x%d", i)
-				} else {
-					iStr = fmt.Sprintf("t%d", i-b.NumInputs+1)
-				}
-				if j < b.NumInputs {
-					jStr = fmt.Sprintf("x%d", j)
-				} else {
-					jStr = fmt.Sprintf("t%d", j-b.NumInputs+1)
-				}
-				b.Result = append(b.Result, fmt.Sprintf("t%d = %s + %s * y%d (%d)", b.ProgramSize, iStr, jStr, t, b.Depth[b.BaseSize-1]))
+				b.program.Outputs[t] = b.program.AddGate(i, j)
 				return true
 			}
 		}
@@ -287,10 +387,14 @@ func (b *BoyarSLP) PickNewBaseElement() bool {
 	minDistance := b.BaseSize * b.NumTargets
 	oldNorm := 0
 	var bestI, bestJ int
-	var theBest uint64
+	var theBest BitVector
 	bestDist := make([]int, b.NumTargets)
+	ties := 0
 
 	for i := 0; i < b.BaseSize-1; i++ {
+		if b.stopped {
+			return false
+		}
 		if b.Depth[i]+1 >= b.DepthLimit {
 			continue
 		}
@@ -298,8 +402,9 @@ func (b *BoyarSLP) PickNewBaseElement() bool {
 			if b.Depth[j]+1 >= b.DepthLimit {
 				continue
 			}
-			newBase := b.Base[i] ^ b.Base[j]
-			if newBase == 0 || b.isBase(newBase) {
+			newBase := b.cand
+			newBase.XorInto(b.Base[i], b.Base[j])
+			if newBase.IsZero() || b.isBase(newBase) {
 				continue
 			}
 
@@ -313,10 +418,19 @@ func (b *BoyarSLP) PickNewBaseElement() bool {
 					thisNorm = thisNorm + d*d
 				}
 
-				if thisDist < minDistance || thisNorm > oldNorm {
+				better := thisDist < minDistance || thisNorm > oldNorm
+				if better {
+					ties = 1
+				} else if b.rng != nil && thisNorm == oldNorm {
+					// Random tie-break: keep each equally good candidate with equal probability
+					ties++
+					better = b.rng.Intn(ties) == 0
+				}
+
+				if better {
 					bestI = i
 					bestJ = j
-					theBest = newBase
+					theBest = newBase.Clone()
 					copy(bestDist, b.NDist[:b.NumTargets])
 					minDistance = thisDist
 					oldNorm = thisNorm
@@ -325,6 +439,16 @@ func (b *BoyarSLP) PickNewBaseElement() bool {
 		}
 	}
 
+	// Distances are incomplete once the context is done
+	if b.stopped {
+		return false
+	}
+
+	// No admissible pair left within the depth limit
+	if theBest == nil {
+		return false
+	}
+
 	for i := 0; i < b.NumTargets; i++ {
 		b.Dist[i] = bestDist[i]
 	}
@@ -336,71 +460,173 @@ func (b *BoyarSLP) PickNewBaseElement() bool {
 	}
 	b.BaseSize++
 	b.ProgramSize++
+	b.program.AddGate(bestI, bestJ)
 
-	var iStr, jStr string
-	if bestI < b.NumInputs {
-		iStr = fmt.Sprintf("x%d", bestI)
-	} else {
-		iStr = fmt.Sprintf("t%d", bestI-b.NumInputs+1)
-	}
-	if bestJ < b.NumInputs {
-		jStr = fmt.Sprintf("x%d", bestJ)
-	} else {
-		jStr = fmt.Sprintf("t%d", bestJ-b.NumInputs+1)
-	}
-	b.Result = append(b.Result, fmt.Sprintf("t%d = %s + %s (%d)", b.ProgramSize, iStr, jStr, b.Depth[b.BaseSize-1]))
-
-	if b.isTarget(theBest) {
-		b.TargetsFound++
-	}
+	b.TargetsFound = b.countFound()
 	return true
 }
 
-func (b *BoyarSLP) Solve(matrix Matrix) (AlgResult, error) {
+func (b *BoyarSLP) Solve(ctx context.Context, matrix Matrix) (AlgResult, error) {
+	if b.Random != nil {
+		return b.Random.run(ctx, "BOYAR", func(rng *rand.Rand) (AlgResult, error) {
+			return b.newStart(rng).Solve(ctx, matrix)
+		})
+	}
+
 	err := b.ReadTargetMatrix(matrix)
 	if err != nil {
 		return AlgResult{}, err
 	}
+	if need := minimumDepth(b.Target[:b.NumTargets]); b.DepthLimit < need {
+		return AlgResult{}, fmt.Errorf("derinlik sınırı %d yetersiz: en ağır satır en az %d derinlik gerektiriyor", b.DepthLimit, need)
+	}
 	
 	err = b.InitBase()
 	if err != nil {
 		return AlgResult{}, err
 	}
 
+	b.ctx = ctx
+	status := StatusCompleted
 	iterations := 0
-	for b.TargetsFound < b.NumTargets && iterations < MAX_ITERATIONS {
+	for b.TargetsFound < b.NumTargets {
+		if iterations >= b.MaxIterations {
+			status = StatusTimedOut
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			if !deadlineExceeded(err) {
+				return AlgResult{}, err
+			}
+			status = StatusTimedOut
+			break
+		}
 		if !b.EasyMove() {
 			if !b.PickNewBaseElement() {
-				break // Array sınırına ulaşıldı
+				if b.stopped {
+					continue // The context check above ends the loop
+				}
+				break // Array sınırına veya derinlik sınırına ulaşıldı
 			}
 		}
 		iterations++
+		if b.MaxGates > 0 && b.ProgramSize > b.MaxGates {
+			return b.aborted(), nil
+		}
 	}
 
-	var program []string
-	for _, res := range b.Result {
-		if res != "" {
-			program = append(program, res)
+	if status == StatusTimedOut {
+		log.Printf("[BOYAR] Bütçe doldu (%d iterasyon), kalan %d hedef doğrudan hesaplanıyor", iterations, b.NumTargets-b.TargetsFound)
+		if err := b.completeNaively(); err != nil {
+			return AlgResult{}, err
+		}
+		if b.MaxGates > 0 && b.ProgramSize > b.MaxGates {
+			return b.aborted(), nil
 		}
 	}
 
+	if b.TargetsFound < b.NumTargets {
+		return AlgResult{}, fmt.Errorf("program tamamlanamadı: %d/%d hedef bulundu (derinlik sınırı %d)", b.TargetsFound, b.NumTargets, b.DepthLimit)
+	}
+
+	b.program.ResolveOutputs(b.Target[:b.NumTargets])
+
 	return AlgResult{
-		XorCount: b.ProgramSize,
-		Program:  program,
-		Depth:    b.MaxDepth,
+		XorCount:   b.ProgramSize,
+		Program:    b.program,
+		Depth:      b.MaxDepth,
+		Status:     status,
+		DepthLimit: b.DepthLimit,
 	}, nil
 }
 
+// aborted returns the result of a run stopped by the max_gates budget: the
+// gate count reached so far and no program
+func (b *BoyarSLP) aborted() AlgResult {
+	log.Printf("[BOYAR] Kapı bütçesi aşıldı (%d > %d), arama durduruldu", b.ProgramSize, b.MaxGates)
+	return AlgResult{
+		XorCount:   b.ProgramSize,
+		Depth:      b.MaxDepth,
+		Status:     StatusAborted,
+		DepthLimit: b.DepthLimit,
+	}
+}
+
+// completeNaively computes every target that is not in the base yet as a
+// balanced XOR tree over its inputs, so an interrupted run still yields a
+// valid (if longer) program
+func (b *BoyarSLP) completeNaively() error {
+	for t := 0; t < b.NumTargets; t++ {
+		if b.Dist[t] == 0 {
+			continue
+		}
+		if b.isBase(b.Target[t]) {
+			b.Dist[t] = 0
+			continue
+		}
+
+		var operands []int
+		for j := 0; j < b.NumInputs; j++ {
+			if b.Target[t].Test(j) {
+				operands = append(operands, j)
+			}
+		}
+
+		for len(operands) > 1 {
+			var next []int
+			for k := 0; k+1 < len(operands); k += 2 {
+				if b.BaseSize >= MAX_ARRAY_SIZE {
+					return fmt.Errorf("base array overflow: %d >= %d", b.BaseSize, MAX_ARRAY_SIZE)
+				}
+				i, j := operands[k], operands[k+1]
+				b.Base[b.BaseSize] = b.Base[i].Xor(b.Base[j])
+				b.Depth[b.BaseSize] = b.max(b.Depth[i], b.Depth[j]) + 1
+				if b.Depth[b.BaseSize] > b.MaxDepth {
+					b.MaxDepth = b.Depth[b.BaseSize]
+				}
+				b.BaseSize++
+				b.ProgramSize++
+
+				signal := b.program.AddGate(i, j)
+				if len(operands) == 2 {
+					b.program.Outputs[t] = signal
+				}
+				next = append(next, signal)
+			}
+			if len(operands)%2 == 1 {
+				next = append(next, operands[len(operands)-1])
+			}
+			operands = next
+		}
+		b.Dist[t] = 0
+	}
+
+	b.TargetsFound = b.countFound()
+	return nil
+}
+
 // Paar Algorithm implementation
 type PaarAlgorithm struct {
-	NumInputs int
-	Dim       int
+	NumInputs     int
+	Dim           int
+	Columns       []BitVector // Column j holds the rows that use input j
+	MaxIterations int         // Maximum number of merged column pairs, 0 for no limit
 }
 
 func NewPaarAlgorithm() *PaarAlgorithm {
 	return &PaarAlgorithm{}
 }
 
+// Name returns the registry name of the solver
+func (p *PaarAlgorithm) Name() string {
+	return "paar"
+}
+
+// Parameters returns the effective solver parameters
+func (p *PaarAlgorithm) Parameters() SolverParams {
+	return SolverParams{"max_iterations": p.MaxIterations}
+}
+
 func (p *PaarAlgorithm) ReadTargetMatrix(matrix Matrix) error {
 	p.Dim = len(matrix)
 	if p.Dim == 0 {
@@ -409,10 +635,26 @@ func (p *PaarAlgorithm) ReadTargetMatrix(matrix Matrix) error {
 	if p.Dim >= MAX_ARRAY_SIZE {
 		return fmt.Errorf("matris çok büyük: %d >= %d", p.Dim, MAX_ARRAY_SIZE)
 	}
-	p.NumInputs = len(matrix[0])
+
+	rows, numInputs, err := parseBinaryRows(matrix)
+	if err != nil {
+		return err
+	}
+	p.NumInputs = numInputs
 	if p.NumInputs >= MAX_ARRAY_SIZE {
 		return fmt.Errorf("matris genişliği çok büyük: %d >= %d", p.NumInputs, MAX_ARRAY_SIZE)
 	}
+
+	// Convert matrix to column vectors (column-wise like C++)
+	p.Columns = make([]BitVector, p.NumInputs)
+	for j := 0; j < p.NumInputs; j++ {
+		p.Columns[j] = NewBitVector(p.Dim)
+		for i := 0; i < p.Dim; i++ {
+			if rows[i].Test(j) {
+				p.Columns[j].Set(i)
+			}
+		}
+	}
 	return nil
 }
 
@@ -421,16 +663,7 @@ func (p *PaarAlgorithm) InitBase() error {
 	return nil
 }
 
-func (p *PaarAlgorithm) hammingWeight(input uint64) int {
-	count := 0
-	for input != 0 {
-		count += int(input & 1)
-		input >>= 1
-	}
-	return count
-}
-
-func (p *PaarAlgorithm) Solve(matrix Matrix) (AlgResult, error) {
+func (p *PaarAlgorithm) Solve(ctx context.Context, matrix Matrix) (AlgResult, error) {
 	err := p.ReadTargetMatrix(matrix)
 	if err != nil {
 		return AlgResult{}, err
@@ -441,37 +674,34 @@ func (p *PaarAlgorithm) Solve(matrix Matrix) (AlgResult, error) {
 		return AlgResult{}, err
 	}
 
-	// Convert matrix to uint64 array (column-wise like C++)
-	inputMatrix := make([]uint64, p.Dim+200)
-	for i := 0; i < p.Dim; i++ {
-		var val uint64 = 0
-		for j := 0; j < p.NumInputs; j++ {
-			bit, _ := strconv.Atoi(strings.TrimSpace(matrix[j][i]))
-			if bit == 1 {
-				val |= (1 << (p.Dim - 1 - j))
-			}
-		}
-		inputMatrix[i] = val & ((1 << p.Dim) - 1)
-	}
+	inputMatrix := make([]BitVector, p.NumInputs)
+	copy(inputMatrix, p.Columns)
 
-	xorCount := 0
-	numberOfColumns := p.Dim
-	var program []string
-
-	// Compute naive xor count
-	for i := 0; i < p.Dim; i++ {
-		xorCount += p.hammingWeight(inputMatrix[i])
-	}
-	xorCount -= p.Dim
+	xorCount := p.naiveXorCount()
+	var pairs [][2]int
+	status := StatusCompleted
 
 	for {
+		// Every intermediate state is a valid program, so a budget stop keeps it
+		if err := ctx.Err(); err != nil {
+			if !deadlineExceeded(err) {
+				return AlgResult{}, err
+			}
+			status = StatusTimedOut
+			break
+		}
+		if p.MaxIterations > 0 && len(pairs) >= p.MaxIterations {
+			status = StatusTimedOut
+			break
+		}
+
 		hwMax := 0
 		var iMax, jMax int
+		numberOfColumns := len(inputMatrix)
 
 		for i := 0; i < numberOfColumns; i++ {
 			for j := i + 1; j < numberOfColumns; j++ {
-				tmp := inputMatrix[i] & inputMatrix[j]
-				hw := p.hammingWeight(tmp)
+				hw := inputMatrix[i].AndPopCount(inputMatrix[j])
 				if hw > hwMax {
 					hwMax = hw
 					iMax = i
@@ -484,64 +714,112 @@ func (p *PaarAlgorithm) Solve(matrix Matrix) (AlgResult, error) {
 			break
 		}
 
-		newColumn := inputMatrix[iMax] & inputMatrix[jMax]
-		inputMatrix[numberOfColumns] = newColumn
-		inputMatrix[iMax] = (newColumn ^ ((1 << p.Dim) - 1)) & inputMatrix[iMax]
-		inputMatrix[jMax] = (newColumn ^ ((1 << p.Dim) - 1)) & inputMatrix[jMax]
+		newColumn := inputMatrix[iMax].And(inputMatrix[jMax])
+		inputMatrix = append(inputMatrix, newColumn)
+		inputMatrix[iMax] = inputMatrix[iMax].AndNot(newColumn)
+		inputMatrix[jMax] = inputMatrix[jMax].AndNot(newColumn)
 		xorCount -= (hwMax - 1)
-		numberOfColumns++
-		program = append(program, fmt.Sprintf("x%d = x%d + x%d", numberOfColumns-1, iMax, jMax))
+		pairs = append(pairs, [2]int{iMax, jMax})
+	}
+
+	return AlgResult{
+		XorCount: xorCount,
+		Program:  p.buildProgram(pairs, inputMatrix),
+		Status:   status,
+	}, nil
+}
+
+// naiveXorCount returns the XOR count of evaluating every row on its own
+func (p *PaarAlgorithm) naiveXorCount() int {
+	xorCount := 0
+	for i := 0; i < p.NumInputs; i++ {
+		xorCount += p.Columns[i].PopCount()
+	}
+	return xorCount - p.Dim
+}
+
+// buildProgram turns the chosen column pairs and the final columns into a
+// Program. Column k >= NumInputs is the gate of pair k-NumInputs, and every
+// output XORs the columns of its row as a balanced tree.
+func (p *PaarAlgorithm) buildProgram(pairs [][2]int, columns []BitVector) *Program {
+	program := NewProgram(p.NumInputs, p.Dim)
+	for _, pair := range pairs {
+		program.AddGate(pair[0], pair[1])
 	}
 
-	// Generate output equations
 	for i := 0; i < p.Dim; i++ {
-		var equation strings.Builder
-		equation.WriteString(fmt.Sprintf("y%d = ", i))
-		first := true
-		for j := 0; j < numberOfColumns; j++ {
-			if (inputMatrix[j] & (1 << (p.Dim - 1 - i))) != 0 {
-				if !first {
-					equation.WriteString(" + ")
-				}
-				equation.WriteString(fmt.Sprintf("x%d", j))
-				first = false
+		var signals []int
+		for j := 0; j < len(columns); j++ {
+			if columns[j].Test(i) {
+				signals = append(signals, j)
 			}
 		}
-		if !first {
-			program = append(program, equation.String())
-		}
+		program.Outputs[i] = program.AddXorTree(signals)
 	}
 
-	return AlgResult{
-		XorCount: xorCount,
-		Program:  program,
-	}, nil
+	return program
 }
 
 // SLP Heuristic implementation
 type SLPHeuristic struct {
-	NumInputs    int
-	NumTargets   int
-	XorCount     int
-	Target       []uint64
-	Dist         []int
-	NDist        []int
-	Base         []uint64
-	Program      []string
-	BaseSize     int
-	TargetsFound int
+	NumInputs     int
+	NumTargets    int
+	XorCount      int
+	Target        []BitVector
+	Dist          []int
+	NDist         []int
+	Base          []BitVector
+	BaseSize      int
+	TargetsFound  int
+	MaxIterations int               // Iteration budget of the main loop
+	MaxGates      int               // Abort once the program has more gates, 0 for no bound
+	Random        *RandomizedSearch // Randomized multi-start mode, nil for the deterministic solver
+
+	program *Program    // Gate list; base element i is signal i
+	scratch []BitVector // Per-level buffers for reachable, indexed by K
+	tmp     BitVector   // Buffer for Target ^ newBase in NewDistance
+	cand    BitVector   // Buffer for candidate base elements
+	rng     *rand.Rand  // Random tie-breaking within a single start, nil for first-found
+
+	ctx     context.Context // Context of the running Solve
+	steps   int             // reachable calls since the start of Solve
+	stopped bool            // Set once ctx is done; reachable then fails fast
 }
 
 func NewSLPHeuristic() *SLPHeuristic {
 	return &SLPHeuristic{
-		Target:  make([]uint64, MAX_ARRAY_SIZE),
-		Dist:    make([]int, MAX_ARRAY_SIZE),
-		NDist:   make([]int, MAX_ARRAY_SIZE),
-		Base:    make([]uint64, MAX_ARRAY_SIZE),
-		Program: make([]string, MAX_ARRAY_SIZE),
+		MaxIterations: MAX_ITERATIONS,
+		Target:        make([]BitVector, MAX_ARRAY_SIZE),
+		Dist:          make([]int, MAX_ARRAY_SIZE),
+		NDist:         make([]int, MAX_ARRAY_SIZE),
+		Base:          make([]BitVector, MAX_ARRAY_SIZE),
 	}
 }
 
+// newStart returns a fresh deterministic solver with every setting of s for
+// one randomized start
+func (s *SLPHeuristic) newStart(rng *rand.Rand) *SLPHeuristic {
+	start := NewSLPHeuristic()
+	start.MaxIterations = s.MaxIterations
+	start.MaxGates = s.MaxGates
+	start.rng = rng
+	return start
+}
+
+// Name returns the registry name of the solver
+func (s *SLPHeuristic) Name() string {
+	return "slp"
+}
+
+// Parameters returns the effective solver parameters
+func (s *SLPHeuristic) Parameters() SolverParams {
+	params := SolverParams{"max_iterations": s.MaxIterations}
+	if s.MaxGates > 0 {
+		params["max_gates"] = s.MaxGates
+	}
+	return s.Random.addParameters(params)
+}
+
 func (s *SLPHeuristic) ReadTargetMatrix(matrix Matrix) error {
 	s.NumTargets = len(matrix)
 	if s.NumTargets == 0 {
@@ -550,23 +828,22 @@ func (s *SLPHeuristic) ReadTargetMatrix(matrix Matrix) error {
 	if s.NumTargets >= MAX_ARRAY_SIZE {
 		return fmt.Errorf("matris çok büyük: %d >= %d", s.NumTargets, MAX_ARRAY_SIZE)
 	}
-	s.NumInputs = len(matrix[0])
+
+	rows, numInputs, err := parseBinaryRows(matrix)
+	if err != nil {
+		return err
+	}
+	s.NumInputs = numInputs
 	if s.NumInputs >= MAX_ARRAY_SIZE {
 		return fmt.Errorf("matris genişliği çok büyük: %d >= %d", s.NumInputs, MAX_ARRAY_SIZE)
 	}
 
 	for i := 0; i < s.NumTargets; i++ {
-		var powerOfTwo uint64 = 1
-		s.Target[i] = 0
-		s.Dist[i] = -1
-
-		for j := 0; j < s.NumInputs; j++ {
-			bit, _ := strconv.Atoi(strings.TrimSpace(matrix[i][j]))
-			if bit == 1 {
-				s.Dist[i]++
-				s.Target[i] = s.Target[i] + powerOfTwo
-			}
-			powerOfTwo = powerOfTwo * 2
+		s.Target[i] = rows[i]
+		// Zero rows need no gate and count as found from the start
+		s.Dist[i] = s.Target[i].PopCount() - 1
+		if s.Dist[i] < 0 {
+			s.Dist[i] = 0
 		}
 	}
 	return nil
@@ -574,63 +851,93 @@ func (s *SLPHeuristic) ReadTargetMatrix(matrix Matrix) error {
 
 func (s *SLPHeuristic) InitBase() error {
 	s.TargetsFound = 0
-	s.Base[0] = 1
-	s.Program[0] = "x0"
-	for i := 1; i < s.NumInputs; i++ {
+	for i := 0; i < s.NumInputs; i++ {
 		if i >= MAX_ARRAY_SIZE {
 			return fmt.Errorf("base array overflow: %d >= %d", i, MAX_ARRAY_SIZE)
 		}
-		s.Base[i] = 2 * s.Base[i-1]
-		s.Program[i] = fmt.Sprintf("x%d", i)
+		s.Base[i] = UnitBitVector(s.NumInputs, i)
 	}
 	s.BaseSize = s.NumInputs
+	s.program = NewProgram(s.NumInputs, s.NumTargets)
 
+	s.scratch = make([]BitVector, s.NumInputs+1)
+	for i := range s.scratch {
+		s.scratch[i] = NewBitVector(s.NumInputs)
+	}
+	s.tmp = NewBitVector(s.NumInputs)
+	s.cand = NewBitVector(s.NumInputs)
+
+	s.TargetsFound = s.countFound()
+	return nil
+}
+
+// countFound returns the number of targets that are already in the base
+func (s *SLPHeuristic) countFound() int {
+	found := 0
 	for i := 0; i < s.NumTargets; i++ {
 		if s.Dist[i] == 0 {
-			s.TargetsFound++
+			found++
 		}
 	}
-	return nil
+	return found
 }
 
-func (s *SLPHeuristic) isTarget(x uint64) bool {
+func (s *SLPHeuristic) isTarget(x BitVector) bool {
 	for i := 0; i < s.NumTargets; i++ {
-		if x == s.Target[i] {
+		if x.Equal(s.Target[i]) {
 			return true
 		}
 	}
 	return false
 }
 
-func (s *SLPHeuristic) isBase(x uint64) bool {
-	if x == 0 {
+func (s *SLPHeuristic) isBase(x BitVector) bool {
+	if x.IsZero() {
 		return false
 	}
 	for i := 0; i < s.BaseSize; i++ {
-		if x == s.Base[i] {
+		if x.Equal(s.Base[i]) {
 			return true
 		}
 	}
 	return false
 }
 
-func (s *SLPHeuristic) reachable(T uint64, K, S int) bool {
+// interrupted polls the solve context every reachableCheckInterval calls
+func (s *SLPHeuristic) interrupted() bool {
+	if s.stopped {
+		return true
+	}
+	s.steps++
+	if s.steps%reachableCheckInterval == 0 && s.ctx != nil && s.ctx.Err() != nil {
+		s.stopped = true
+	}
+	return s.stopped
+}
+
+func (s *SLPHeuristic) reachable(T BitVector, K, S int) bool {
+	if s.interrupted() {
+		return false
+	}
 	if (s.BaseSize-S) < K {
 		return false
 	}
-	if K == 0 {
+	if K <= 0 {
 		return false
 	}
 	if K == 1 {
 		for i := S; i < s.BaseSize; i++ {
-			if T == s.Base[i] {
+			if T.Equal(s.Base[i]) {
 				return true
 			}
 		}
 		return false
 	}
 
-	if s.reachable(T^s.Base[S], K-1, S+1) {
+	// scratch[K-1] is only used by the K-1 subtree, so T (scratch[K]) stays intact
+	next := s.scratch[K-1]
+	next.XorInto(T, s.Base[S])
+	if s.reachable(next, K-1, S+1) {
 		return true
 	}
 	if s.reachable(T, K, S+1) {
@@ -639,17 +946,21 @@ func (s *SLPHeuristic) reachable(T uint64, K, S int) bool {
 	return false
 }
 
-func (s *SLPHeuristic) NewDistance(u int, newBase uint64) int {
-	if s.isBase(s.Target[u]) || newBase == s.Target[u] {
+func (s *SLPHeuristic) NewDistance(u int, newBase BitVector) int {
+	if s.Target[u].IsZero() {
 		return 0
 	}
-	if s.reachable(s.Target[u]^newBase, s.Dist[u]-1, 0) {
+	if s.isBase(s.Target[u]) || newBase.Equal(s.Target[u]) {
+		return 0
+	}
+	s.tmp.XorInto(s.Target[u], newBase)
+	if s.reachable(s.tmp, s.Dist[u]-1, 0) {
 		return s.Dist[u] - 1
 	}
 	return s.Dist[u]
 }
 
-func (s *SLPHeuristic) TotalDistance(newBase uint64) int {
+func (s *SLPHeuristic) TotalDistance(newBase BitVector) int {
 	D := 0
 	for i := 0; i < s.NumTargets; i++ {
 		t := s.NewDistance(i, newBase)
@@ -661,10 +972,18 @@ func (s *SLPHeuristic) TotalDistance(newBase uint64) int {
 
 func (s *SLPHeuristic) EasyMove() bool {
 	t := -1
+	candidates := 0
 	for i := 0; i < s.NumTargets; i++ {
 		if s.Dist[i] == 1 {
-			t = i
-			break
+			if s.rng == nil {
+				t = i
+				break
+			}
+			// Pick uniformly among all targets at distance one
+			candidates++
+			if s.rng.Intn(candidates) == 0 {
+				t = i
+			}
 		}
 	}
 	if t == -1 {
@@ -676,7 +995,7 @@ func (s *SLPHeuristic) EasyMove() bool {
 		return false
 	}
 
-	newBase := s.Target[t]
+	newBase := s.Target[t].Clone()
 	for u := 0; u < s.NumTargets; u++ {
 		s.Dist[u] = s.NewDistance(u, newBase)
 	}
@@ -684,21 +1003,21 @@ func (s *SLPHeuristic) EasyMove() bool {
 	s.Base[s.BaseSize] = newBase
 
 	// Find which lines in Base caused this
-	var a, b string
+	var a, b int
 	for i := 0; i < s.BaseSize; i++ {
 		for j := i + 1; j < s.BaseSize; j++ {
-			if (s.Base[i] ^ s.Base[j]) == s.Target[t] {
-				a = strings.Split(s.Program[i], " ")[0]
-				b = strings.Split(s.Program[j], " ")[0]
+			if s.Target[t].XorEquals(s.Base[i], s.Base[j]) {
+				a = i
+				b = j
 				break
 			}
 		}
 	}
 
-	s.Program[s.BaseSize] = fmt.Sprintf("y%d = %s + %s", t, a, b)
+	s.program.Outputs[t] = s.program.AddGate(a, b)
 	s.BaseSize++
 	s.XorCount++
-	s.TargetsFound++
+	s.TargetsFound = s.countFound()
 	return true
 }
 
@@ -711,13 +1030,18 @@ func (s *SLPHeuristic) PickNewBaseElement() bool {
 	minDistance := s.BaseSize * s.NumTargets
 	oldNorm := 0
 	var bestI, bestJ int
-	var theBest uint64
+	var theBest BitVector
 	bestDist := make([]int, s.NumTargets)
+	ties := 0
 
 	for i := 0; i < s.BaseSize-1; i++ {
+		if s.stopped {
+			return false
+		}
 		for j := i + 1; j < s.BaseSize; j++ {
-			newBase := s.Base[i] ^ s.Base[j]
-			if newBase == 0 || s.isBase(newBase) {
+			newBase := s.cand
+			newBase.XorInto(s.Base[i], s.Base[j])
+			if newBase.IsZero() || s.isBase(newBase) {
 				continue
 			}
 
@@ -729,10 +1053,19 @@ func (s *SLPHeuristic) PickNewBaseElement() bool {
 					thisNorm = thisNorm + d*d
 				}
 
-				if thisDist < minDistance || thisNorm > oldNorm {
+				better := thisDist < minDistance || thisNorm > oldNorm
+				if better {
+					ties = 1
+				} else if s.rng != nil && thisNorm == oldNorm {
+					// Random tie-break: keep each equally good candidate with equal probability
+					ties++
+					better = s.rng.Intn(ties) == 0
+				}
+
+				if better {
 					bestI = i
 					bestJ = j
-					theBest = newBase
+					theBest = newBase.Clone()
 					copy(bestDist, s.NDist[:s.NumTargets])
 					minDistance = thisDist
 					oldNorm = thisNorm
@@ -741,24 +1074,36 @@ func (s *SLPHeuristic) PickNewBaseElement() bool {
 		}
 	}
 
+	// Distances are incomplete once the context is done
+	if s.stopped {
+		return false
+	}
+
+	// No new base element can be formed
+	if theBest == nil {
+		return false
+	}
+
 	for i := 0; i < s.NumTargets; i++ {
 		s.Dist[i] = bestDist[i]
 	}
 
 	s.Base[s.BaseSize] = theBest
-	a := strings.Split(s.Program[bestI], " ")[0]
-	b := strings.Split(s.Program[bestJ], " ")[0]
-	s.Program[s.BaseSize] = fmt.Sprintf("t%d = %s + %s", s.XorCount, a, b)
+	s.program.AddGate(bestI, bestJ)
 	s.BaseSize++
 	s.XorCount++
 
-	if s.isTarget(theBest) {
-		s.TargetsFound++
-	}
+	s.TargetsFound = s.countFound()
 	return true
 }
 
-func (s *SLPHeuristic) Solve(matrix Matrix) (AlgResult, error) {
+func (s *SLPHeuristic) Solve(ctx context.Context, matrix Matrix) (AlgResult, error) {
+	if s.Random != nil {
+		return s.Random.run(ctx, "SLP", func(rng *rand.Rand) (AlgResult, error) {
+			return s.newStart(rng).Solve(ctx, matrix)
+		})
+	}
+
 	err := s.ReadTargetMatrix(matrix)
 	if err != nil {
 		return AlgResult{}, err
@@ -770,344 +1115,233 @@ func (s *SLPHeuristic) Solve(matrix Matrix) (AlgResult, error) {
 	}
 	s.XorCount = 0
 
+	s.ctx = ctx
+	status := StatusCompleted
 	iterations := 0
-	for s.TargetsFound < s.NumTargets && iterations < MAX_ITERATIONS {
+	for s.TargetsFound < s.NumTargets {
+		if iterations >= s.MaxIterations {
+			status = StatusTimedOut
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			if !deadlineExceeded(err) {
+				return AlgResult{}, err
+			}
+			status = StatusTimedOut
+			break
+		}
 		if !s.EasyMove() {
 			if !s.PickNewBaseElement() {
+				if s.stopped {
+					continue // The context check above ends the loop
+				}
 				break // Array sınırına ulaşıldı
 			}
 		}
 		iterations++
+		if s.MaxGates > 0 && s.XorCount > s.MaxGates {
+			return s.aborted(), nil
+		}
 	}
 
-	var program []string
-	for j := 0; j < s.XorCount; j++ {
-		if s.NumInputs+j < MAX_ARRAY_SIZE && s.Program[s.NumInputs+j] != "" {
-			program = append(program, s.Program[s.NumInputs+j])
+	if status == StatusTimedOut {
+		log.Printf("[SLP] Bütçe doldu (%d iterasyon), kalan %d hedef doğrudan hesaplanıyor", iterations, s.NumTargets-s.TargetsFound)
+		if err := s.completeNaively(); err != nil {
+			return AlgResult{}, err
 		}
+		if s.MaxGates > 0 && s.XorCount > s.MaxGates {
+			return s.aborted(), nil
+		}
+	}
+
+	if s.TargetsFound < s.NumTargets {
+		return AlgResult{}, fmt.Errorf("program tamamlanamadı: %d/%d hedef bulundu", s.TargetsFound, s.NumTargets)
 	}
 
+	s.program.ResolveOutputs(s.Target[:s.NumTargets])
+
 	return AlgResult{
 		XorCount: s.XorCount,
-		Program:  program,
+		Program:  s.program,
+		Status:   status,
 	}, nil
 }
 
-// API Handlers
-func boyarHandler(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
-	log.Printf("[BOYAR] İstek başladı - Method: %s, URL: %s", r.Method, r.URL.Path)
-	
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	if r.Method == "OPTIONS" {
-		log.Printf("[BOYAR] OPTIONS isteği işlendi")
-		return
-	}
-
-	if r.Method != "POST" {
-		log.Printf("[BOYAR] HATA: Geçersiz method: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var request struct {
-		Matrices [][][]string `json:"matrices"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Printf("[BOYAR] HATA: JSON decode hatası: %v", err)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Invalid JSON: " + err.Error(),
-		})
-		return
+// aborted returns the result of a run stopped by the max_gates budget: the
+// gate count reached so far and no program
+func (s *SLPHeuristic) aborted() AlgResult {
+	log.Printf("[SLP] Kapı bütçesi aşıldı (%d > %d), arama durduruldu", s.XorCount, s.MaxGates)
+	return AlgResult{
+		XorCount: s.XorCount,
+		Status:   StatusAborted,
 	}
+}
 
-	log.Printf("[BOYAR] %d matris alındı", len(request.Matrices))
-
-	var results []map[string]interface{}
-	for i, matrix := range request.Matrices {
-		log.Printf("[BOYAR] Matris %d işleniyor (%dx%d)", i+1, len(matrix), len(matrix[0]))
-		
-		if len(matrix) == 0 {
-			log.Printf("[BOYAR] HATA: Matris %d boş", i+1)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        "Empty matrix",
-				"xor_count":    0,
-				"depth":        0,
-				"program":      []string{},
-			})
+// completeNaively computes every target that is not in the base yet as a
+// balanced XOR tree over its inputs, so an interrupted run still yields a
+// valid (if longer) program
+func (s *SLPHeuristic) completeNaively() error {
+	for t := 0; t < s.NumTargets; t++ {
+		if s.Dist[t] == 0 {
 			continue
 		}
-
-		boyar := NewBoyarSLP(10)
-		err := boyar.ReadTargetMatrix(matrix)
-		if err != nil {
-			log.Printf("[BOYAR] HATA: Matris %d okuma hatası: %v", i+1, err)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        err.Error(),
-				"xor_count":    0,
-				"depth":        0,
-				"program":      []string{},
-			})
+		if s.isBase(s.Target[t]) {
+			s.Dist[t] = 0
 			continue
 		}
 
-		err = boyar.InitBase()
-		if err != nil {
-			log.Printf("[BOYAR] HATA: Matris %d init hatası: %v", i+1, err)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        err.Error(),
-				"xor_count":    0,
-				"depth":        0,
-				"program":      []string{},
-			})
-			continue
+		var operands []int
+		for j := 0; j < s.NumInputs; j++ {
+			if s.Target[t].Test(j) {
+				operands = append(operands, j)
+			}
 		}
 
-		result, err := boyar.Solve(matrix)
-		if err != nil {
-			log.Printf("[BOYAR] HATA: Matris %d solve hatası: %v", i+1, err)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        err.Error(),
-				"xor_count":    0,
-				"depth":        0,
-				"program":      []string{},
-			})
-			continue
-		}
+		for len(operands) > 1 {
+			var next []int
+			for k := 0; k+1 < len(operands); k += 2 {
+				if s.BaseSize >= MAX_ARRAY_SIZE {
+					return fmt.Errorf("base array overflow: %d >= %d", s.BaseSize, MAX_ARRAY_SIZE)
+				}
+				i, j := operands[k], operands[k+1]
 
-		log.Printf("[BOYAR] Matris %d başarıyla işlendi - XOR: %d, Derinlik: %d", i+1, result.XorCount, result.Depth)
-		results = append(results, map[string]interface{}{
-			"matrix_index": i,
-			"xor_count":    result.XorCount,
-			"depth":        result.Depth,
-			"program":      result.Program,
-		})
+				s.Base[s.BaseSize] = s.Base[i].Xor(s.Base[j])
+				signal := s.program.AddGate(i, j)
+				if len(operands) == 2 {
+					s.program.Outputs[t] = signal
+				}
+				next = append(next, s.BaseSize)
+				s.BaseSize++
+				s.XorCount++
+			}
+			if len(operands)%2 == 1 {
+				next = append(next, operands[len(operands)-1])
+			}
+			operands = next
+		}
+		s.Dist[t] = 0
 	}
 
-	duration := time.Since(startTime)
-	log.Printf("[BOYAR] İstek tamamlandı - Süre: %v, Sonuç sayısı: %d", duration, len(results))
-
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"algorithm": "BoyarSLP",
-		"results":   results,
-	})
+	s.TargetsFound = s.countFound()
+	return nil
 }
 
-func paarHandler(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
-	log.Printf("[PAAR] İstek başladı - Method: %s, URL: %s", r.Method, r.URL.Path)
-	
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	if r.Method == "OPTIONS" {
-		log.Printf("[PAAR] OPTIONS isteği işlendi")
-		return
-	}
-
-	if r.Method != "POST" {
-		log.Printf("[PAAR] HATA: Geçersiz method: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+// API Handlers
 
-	var request struct {
-		Matrices [][][]string `json:"matrices"`
-	}
+// solverHandler returns the HTTP handler that runs the given registered solver
+// on every matrix of the request
+func solverHandler(info *SolverInfo) http.HandlerFunc {
+	tag := strings.ToUpper(info.Name)
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Printf("[PAAR] HATA: JSON decode hatası: %v", err)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Invalid JSON: " + err.Error(),
-		})
-		return
-	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+		log.Printf("[%s] İstek başladı - Method: %s, URL: %s", tag, r.Method, r.URL.Path)
 
-	log.Printf("[PAAR] %d matris alındı", len(request.Matrices))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
-	var results []map[string]interface{}
-	for i, matrix := range request.Matrices {
-		log.Printf("[PAAR] Matris %d işleniyor (%dx%d)", i+1, len(matrix), len(matrix[0]))
-		
-		if len(matrix) == 0 {
-			log.Printf("[PAAR] HATA: Matris %d boş", i+1)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        "Empty matrix",
-				"xor_count":    0,
-				"program":      []string{},
-			})
-			continue
+		if r.Method == "OPTIONS" {
+			log.Printf("[%s] OPTIONS isteği işlendi", tag)
+			return
 		}
 
-		paar := NewPaarAlgorithm()
-		err := paar.ReadTargetMatrix(matrix)
-		if err != nil {
-			log.Printf("[PAAR] HATA: Matris %d okuma hatası: %v", i+1, err)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        err.Error(),
-				"xor_count":    0,
-				"program":      []string{},
-			})
-			continue
+		if r.Method != "POST" {
+			log.Printf("[%s] HATA: Geçersiz method: %s", tag, r.Method)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
 		}
 
-		err = paar.InitBase()
-		if err != nil {
-			log.Printf("[PAAR] HATA: Matris %d init hatası: %v", i+1, err)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        err.Error(),
-				"xor_count":    0,
-				"program":      []string{},
-			})
-			continue
+		var request struct {
+			Matrices [][][]string `json:"matrices"`
+			Params   SolverParams `json:"params"` // Optional solver parameters, e.g. {"time_budget_ms": 5000} or {"seed": 42}
 		}
 
-		result, err := paar.Solve(matrix)
-		if err != nil {
-			log.Printf("[PAAR] HATA: Matris %d solve hatası: %v", i+1, err)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        err.Error(),
-				"xor_count":    0,
-				"program":      []string{},
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			log.Printf("[%s] HATA: JSON decode hatası: %v", tag, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": "Invalid JSON: " + err.Error(),
 			})
-			continue
+			return
 		}
 
-		log.Printf("[PAAR] Matris %d başarıyla işlendi - XOR: %d", i+1, result.XorCount)
-		results = append(results, map[string]interface{}{
-			"matrix_index": i,
-			"xor_count":    result.XorCount,
-			"program":      result.Program,
-		})
-	}
-
-	duration := time.Since(startTime)
-	log.Printf("[PAAR] İstek tamamlandı - Süre: %v, Sonuç sayısı: %d", duration, len(results))
-
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"algorithm": "PAAR",
-		"results":   results,
-	})
-}
-
-func slpHandler(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
-	log.Printf("[SLP] İstek başladı - Method: %s, URL: %s", r.Method, r.URL.Path)
-	
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	if r.Method == "OPTIONS" {
-		log.Printf("[SLP] OPTIONS isteği işlendi")
-		return
-	}
-
-	if r.Method != "POST" {
-		log.Printf("[SLP] HATA: Geçersiz method: %s", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var request struct {
-		Matrices [][][]string `json:"matrices"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Printf("[SLP] HATA: JSON decode hatası: %v", err)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Invalid JSON: " + err.Error(),
-		})
-		return
-	}
-
-	log.Printf("[SLP] %d matris alındı", len(request.Matrices))
+		log.Printf("[%s] %d matris alındı", tag, len(request.Matrices))
 
-	var results []map[string]interface{}
-	for i, matrix := range request.Matrices {
-		log.Printf("[SLP] Matris %d işleniyor (%dx%d)", i+1, len(matrix), len(matrix[0]))
-		
-		if len(matrix) == 0 {
-			log.Printf("[SLP] HATA: Matris %d boş", i+1)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        "Empty matrix",
+		// errorResult builds the per-matrix error entry in the solver's response shape
+		errorResult := func(index int, message string) map[string]interface{} {
+			result := map[string]interface{}{
+				"matrix_index": index,
+				"error":        message,
 				"xor_count":    0,
-				"program":      []string{},
-			})
-			continue
+				"program":      nil,
+				"peak_live":    0,
+			}
+			if info.HasDepth {
+				result["depth"] = 0
+			}
+			return result
 		}
 
-		slp := NewSLPHeuristic()
-		err := slp.ReadTargetMatrix(matrix)
-		if err != nil {
-			log.Printf("[SLP] HATA: Matris %d okuma hatası: %v", i+1, err)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        err.Error(),
-				"xor_count":    0,
-				"program":      []string{},
-			})
-			continue
-		}
+		var results []map[string]interface{}
+		for i, matrix := range request.Matrices {
+			if len(matrix) == 0 {
+				log.Printf("[%s] HATA: Matris %d boş", tag, i+1)
+				results = append(results, errorResult(i, "Empty matrix"))
+				continue
+			}
 
-		err = slp.InitBase()
-		if err != nil {
-			log.Printf("[SLP] HATA: Matris %d init hatası: %v", i+1, err)
-			results = append(results, map[string]interface{}{
-				"matrix_index": i,
-				"error":        err.Error(),
-				"xor_count":    0,
-				"program":      []string{},
-			})
-			continue
-		}
+			log.Printf("[%s] Matris %d işleniyor (%dx%d)", tag, i+1, len(matrix), len(matrix[0]))
+
+			result, err := runSolver(r.Context(), info.Name, request.Params, matrix)
+			if err != nil {
+				log.Printf("[%s] HATA: Matris %d solve hatası: %v", tag, i+1, err)
+				results = append(results, errorResult(i, err.Error()))
+				continue
+			}
 
-		result, err := slp.Solve(matrix)
-		if err != nil {
-			log.Printf("[SLP] HATA: Matris %d solve hatası: %v", i+1, err)
-			results = append(results, map[string]interface{}{
+			entry := map[string]interface{}{
 				"matrix_index": i,
-				"error":        err.Error(),
-				"xor_count":    0,
-				"program":      []string{},
-			})
-			continue
+				"xor_count":    result.XorCount,
+				"program":      result.Program,
+				"status":       result.Status,
+				"peak_live":    result.PeakLive,
+			}
+			if result.Seed != nil {
+				entry["seed"] = *result.Seed
+			}
+			if info.HasDepth {
+				entry["depth"] = result.Depth
+				entry["depth_limit"] = result.DepthLimit
+				log.Printf("[%s] Matris %d başarıyla işlendi - XOR: %d, Derinlik: %d", tag, i+1, result.XorCount, result.Depth)
+			} else {
+				log.Printf("[%s] Matris %d başarıyla işlendi - XOR: %d", tag, i+1, result.XorCount)
+			}
+			results = append(results, entry)
 		}
 
-		log.Printf("[SLP] Matris %d başarıyla işlendi - XOR: %d", i+1, result.XorCount)
-		results = append(results, map[string]interface{}{
-			"matrix_index": i,
-			"xor_count":    result.XorCount,
-			"program":      result.Program,
+		duration := time.Since(startTime)
+		log.Printf("[%s] İstek tamamlandı - Süre: %v, Sonuç sayısı: %d", tag, duration, len(results))
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"algorithm": info.Label,
+			"results":   results,
 		})
 	}
+}
 
-	duration := time.Since(startTime)
-	log.Printf("[SLP] İstek tamamlandı - Süre: %v, Sonuç sayısı: %d", duration, len(results))
-
+// algorithmsHandler lists the registered solvers
+func algorithmsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"algorithm": "SLP_Heuristic",
-		"results":   results,
+		"algorithms": RegisteredSolvers(),
+		"defaults":   DefaultAlgorithms(),
 	})
 }
 
+// shutdownTimeout bounds the wait for open requests on SIGINT/SIGTERM
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	log.Println("=== XOR Optimizasyon Backend Başlatılıyor ===")
@@ -1120,6 +1354,14 @@ func main() {
 	}
 	
 	log.Printf("Config yüklendi: %+v", config)
+
+	// "migrate status|up|down" manages the schema without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(config, os.Args[2:]); err != nil {
+			log.Fatal("Migration komutu başarısız: ", err)
+		}
+		return
+	}
 	
 	// Initialize database
 	if err := InitDatabase(config); err != nil {
@@ -1156,19 +1398,8 @@ func main() {
 		http.ServeFile(w, r, staticDir+"/index.html")
 	})
 
-	// Original API endpoints
-	r.HandleFunc("/boyar", boyarHandler).Methods("POST", "OPTIONS")
-	r.HandleFunc("/paar", paarHandler).Methods("POST", "OPTIONS")
-	r.HandleFunc("/slp", slpHandler).Methods("POST", "OPTIONS")
-
-	// New database API endpoints
-	r.HandleFunc("/api/matrices", getMatricesHandler).Methods("GET")
-	r.HandleFunc("/api/matrices", saveMatrixHandler).Methods("POST")
-	r.HandleFunc("/api/matrices/{id:[0-9]+}", getMatrixHandler).Methods("GET")
-	r.HandleFunc("/api/matrices/{id:[0-9]+}/inverse", calculateInverseHandler).Methods("POST")
-	r.HandleFunc("/api/matrices/process", processAndSaveMatrixHandler).Methods("POST")
-	r.HandleFunc("/api/matrices/recalculate", recalculateHandler).Methods("POST")
-	r.HandleFunc("/api/matrices/bulk-recalculate", bulkRecalculateHandler).Methods("POST")
+	// Solver, matrix and generator endpoints on the configured store
+	NewServer(db).RegisterRoutes(r)
 
 	// Config API endpoints
 	r.HandleFunc("/api/config", func(w http.ResponseWriter, r *http.Request) {
@@ -1198,21 +1429,47 @@ func main() {
 	log.Printf("Data dizini: %s", config.Import.DataDirectory)
 	log.Printf("Otomatik import: %v", config.Import.Enabled)
 	log.Printf("API Endpoints:")
-	log.Printf("  POST /boyar - BoyarSLP algorithm")
-	log.Printf("  POST /paar  - Paar algorithm")
-	log.Printf("  POST /slp   - SLP Heuristic algorithm")
+	for _, info := range RegisteredSolvers() {
+		log.Printf("  POST /%s - %s algorithm", info.Name, info.Label)
+	}
+	log.Printf("  GET  /api/algorithms - List registered algorithms")
 	log.Printf("  GET  /api/matrices - Get matrices with pagination")
 	log.Printf("  POST /api/matrices - Save matrix")
 	log.Printf("  GET  /api/matrices/{id} - Get matrix by ID")
 	log.Printf("  POST /api/matrices/process - Process and save matrix")
 	log.Printf("  POST /api/matrices/recalculate - Recalculate algorithms")
 	log.Printf("  POST /api/matrices/bulk-recalculate - Bulk recalculate algorithms")
+	log.Printf("  GET  /api/jobs - List queued, running, completed and dead algorithm jobs")
+	log.Printf("  POST /api/jobs/{id}/retry - Retry a dead algorithm job")
 	log.Printf("  GET  /api/config - Get current configuration")
 	log.Printf("  POST /api/config/import - Trigger manual import")
 	log.Printf("=== Backend hazır, istekleri bekleniyor ===")
 
 	handler := c.Handler(r)
-	if err := http.ListenAndServe(port, handler); err != nil {
+	server := &http.Server{Addr: port, Handler: handler}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	// On SIGINT/SIGTERM finish the open requests, then release the running
+	// algorithm jobs to the queue before the database is closed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case err := <-serveErr:
 		log.Fatal("Server başlatılamadı:", err)
+	case <-ctx.Done():
+	}
+
+	log.Println("🔄 Kapatma sinyali alındı, sunucu durduruluyor...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️  Açık istekler beklenirken süre doldu: %v", err)
+	}
+	if algorithmWorkerPool != nil {
+		algorithmWorkerPool.Stop()
 	}
+	log.Println("✅ Sunucu durduruldu")
 }
@@ -0,0 +1,1169 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a MatrixStore kept in process memory. It follows the
+// semantics of the SQL queries of Database (filters, ordering, pagination,
+// best-run selection), so the API behaves the same on it; nothing survives a
+// restart. It backs DB_DRIVER=memory and httptest runs of the API.
+//
+// Rows are updated by replacing their fields, never by writing through the
+// pointers they hold, so returned records can share those pointers.
+type MemoryStore struct {
+	mu                 sync.RWMutex
+	matrices           map[int]*memoryMatrix
+	byHash             map[string]int
+	runs               []*memoryRun         // In ID order; run IDs start at 1 and are never reused
+	matrixRuns         map[int][]*memoryRun // Runs of each matrix in ID order
+	paretoPoints       map[int][]*memoryParetoPoint
+	generatorJobs      map[int]*GeneratorJob
+	algorithmJobs      map[int]*AlgorithmJob
+	lastMatrixID       int
+	lastJobID          int
+	lastAlgorithmJobID int
+}
+
+// memoryMatrix is a matrix_records row. Programs and the field matrix are
+// kept in their stored text form and decoded on every read, as Database does.
+type memoryMatrix struct {
+	record      MatrixRecord // Columns of matrix_records but the s-XOR program and the field matrix
+	fieldMatrix *string
+	sxorProgram *string
+}
+
+// memoryRun is an algorithm_runs row
+type memoryRun struct {
+	run        AlgorithmRun // Without Parameters and Program
+	parameters string
+	program    *string
+}
+
+// memoryParetoPoint is a pareto_points row
+type memoryParetoPoint struct {
+	point   ParetoPoint // Without Program
+	program string
+}
+
+var _ MatrixStore = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		matrices:      make(map[int]*memoryMatrix),
+		byHash:        make(map[string]int),
+		matrixRuns:    make(map[int][]*memoryRun),
+		paretoPoints:  make(map[int][]*memoryParetoPoint),
+		generatorJobs: make(map[int]*GeneratorJob),
+		algorithmJobs: make(map[int]*AlgorithmJob),
+	}
+}
+
+// Close releases nothing; the data is dropped with the store
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// analysisFlag returns the analysis flag stored under one of analysisFlagColumns
+func (r *MatrixRecord) analysisFlag(column string) *bool {
+	switch column {
+	case "is_mds":
+		return r.IsMDS
+	case "is_near_mds":
+		return r.IsNearMDS
+	case "is_involutory":
+		return r.IsInvolutory
+	case "is_semi_involutory":
+		return r.IsSemiInvolutory
+	case "is_orthogonal":
+		return r.IsOrthogonal
+	case "is_circulant":
+		return r.IsCirculant
+	}
+	return nil
+}
+
+// storedText converts an optional stored text column for scanProgram
+func storedText(text *string) sql.NullString {
+	if text == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *text, Valid: true}
+}
+
+// touch sets updated_at, as the update trigger of matrix_records does
+func (row *memoryMatrix) touch() {
+	row.record.UpdatedAt = time.Now()
+}
+
+// listing returns a row as GetMatrices lists it: results without programs,
+// no s-XOR program and source, the long representations cut
+func (m *MemoryStore) listing(row *memoryMatrix) *MatrixRecord {
+	record := row.record
+	if len(record.MatrixBinary) > 100 {
+		record.MatrixBinary = record.MatrixBinary[:100] + "..."
+	}
+	if len(record.MatrixHex) > 50 {
+		record.MatrixHex = record.MatrixHex[:50] + "..."
+	}
+	record.SXorSource = nil
+	if row.fieldMatrix != nil {
+		record.FieldMatrix = parseStoredFieldMatrix(*row.fieldMatrix)
+	}
+	m.loadResults(&record, false)
+	return &record
+}
+
+// full returns a row with its results and programs, without depth results
+func (m *MemoryStore) full(row *memoryMatrix) *MatrixRecord {
+	record := row.record
+	if row.fieldMatrix != nil {
+		record.FieldMatrix = parseStoredFieldMatrix(*row.fieldMatrix)
+	}
+	if row.sxorProgram != nil {
+		var program InPlaceProgram
+		if err := json.Unmarshal([]byte(*row.sxorProgram), &program); err != nil {
+			log.Printf("⚠️ Matris %d sxor programı okunamadı: %v", record.ID, err)
+		} else {
+			record.SXorProgram = &program
+		}
+	}
+	m.loadResults(&record, true)
+	return &record
+}
+
+// loadResults sets SmallestXor from the best run of record and Results from
+// the best runs of the persisted solvers, like the join and loadResults of
+// Database
+func (m *MemoryStore) loadResults(record *MatrixRecord, programs bool) {
+	record.SmallestXor = nil
+	if record.BestRunID != nil {
+		if best := m.runByID(*record.BestRunID); best != nil {
+			record.SmallestXor = copyInt(best.run.XorCount)
+		}
+	}
+
+	record.Results = make(map[string]*SolverResult)
+	for _, stored := range m.matrixRuns[record.ID] {
+		run := &stored.run
+		info, ok := GetSolverInfo(run.Algorithm)
+		if !run.Best || !ok || !info.Persisted {
+			continue
+		}
+		status := run.Status
+		result := &SolverResult{
+			RunID:      run.ID,
+			Depth:      copyInt(run.Depth),
+			Seed:       copyInt64(run.Seed),
+			Status:     &status,
+			Origin:     copyString(run.Origin),
+			Verified:   copyBool(run.Verified),
+			DepthLimit: run.DepthLimit,
+			Transpose:  run.Transpose,
+		}
+		// Aborted runs only report their status
+		if run.Status != StatusAborted {
+			result.XorCount = copyInt(run.XorCount)
+		}
+		if programs {
+			result.Program = scanProgram(storedText(stored.program), record, info.Name)
+		}
+		record.Results[info.Name] = result
+	}
+}
+
+// runByID returns the run with the given ID, or nil
+func (m *MemoryStore) runByID(id int) *memoryRun {
+	if id < 1 || id > len(m.runs) {
+		return nil
+	}
+	return m.runs[id-1]
+}
+
+// InsertMatrix stores a new matrix from the title, group, representation and
+// hash fields of record and returns its ID
+func (m *MemoryStore) InsertMatrix(record *MatrixRecord) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.byHash[record.MatrixHash]; ok {
+		return 0, fmt.Errorf("matrix_hash zaten kayıtlı: %s", record.MatrixHash)
+	}
+	m.lastMatrixID++
+	now := time.Now()
+	row := &memoryMatrix{
+		record: MatrixRecord{
+			ID:            m.lastMatrixID,
+			Title:         record.Title,
+			Group:         record.Group,
+			MatrixBinary:  record.MatrixBinary,
+			MatrixHex:     record.MatrixHex,
+			HamXorCount:   record.HamXorCount,
+			MatrixHash:    record.MatrixHash,
+			CanonicalHash: copyString(record.CanonicalHash),
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		},
+	}
+	m.matrices[row.record.ID] = row
+	m.byHash[row.record.MatrixHash] = row.record.ID
+	return row.record.ID, nil
+}
+
+// GetMatrixByID retrieves a matrix by its ID, or nil if it does not exist
+func (m *MemoryStore) GetMatrixByID(id int) (*MatrixRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.matrixByID(id), nil
+}
+
+// matrixByID is GetMatrixByID for callers holding the lock
+func (m *MemoryStore) matrixByID(id int) *MatrixRecord {
+	row := m.matrices[id]
+	if row == nil {
+		return nil
+	}
+	record := m.full(row)
+	record.DepthResults = m.depthResultsOf(record)
+	return record
+}
+
+// GetMatrixByHash retrieves a matrix by its hash, or nil if it does not exist
+func (m *MemoryStore) GetMatrixByHash(hash string) (*MatrixRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.byHash[hash]
+	if !ok {
+		return nil, nil
+	}
+	return m.full(m.matrices[id]), nil
+}
+
+// GetEquivalentMatrices returns the other stored matrices with the canonical
+// hash of record, i.e. its row and column permutations
+func (m *MemoryStore) GetEquivalentMatrices(record *MatrixRecord) ([]*MatrixRecord, error) {
+	if record.CanonicalHash == nil {
+		return nil, nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var equivalents []*MatrixRecord
+	for _, id := range m.sortedMatrixIDs() {
+		row := m.matrices[id]
+		if id != record.ID && row.record.CanonicalHash != nil && *row.record.CanonicalHash == *record.CanonicalHash {
+			equivalents = append(equivalents, m.matrixByID(id))
+		}
+	}
+	return equivalents, nil
+}
+
+// sortedMatrixIDs returns the IDs of the stored matrices in ascending order
+func (m *MemoryStore) sortedMatrixIDs() []int {
+	ids := make([]int, 0, len(m.matrices))
+	for id := range m.matrices {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// GetMatrices retrieves matrices with pagination and filtering, ordered like
+// Database.GetMatrices: by the XOR count of the best run (the Hamming count
+// before any solver ran), newest first among equal counts
+func (m *MemoryStore) GetMatrices(page, limit int, filter MatrixFilter) ([]*MatrixRecord, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matches []*MatrixRecord
+	seen := make(map[string]bool)
+	for _, id := range m.sortedMatrixIDs() {
+		record := m.listing(m.matrices[id])
+		if !matchesFilter(record, filter) {
+			continue
+		}
+		// Equivalent matrices are represented by the first stored one that matches the filter
+		if filter.GroupCanonical {
+			key := record.MatrixHash
+			if record.CanonicalHash != nil {
+				key = *record.CanonicalHash
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		matches = append(matches, record)
+	}
+	total := len(matches)
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if ka, kb := listingXor(a), listingXor(b); ka != kb {
+			return ka < kb
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(matches) {
+		offset = len(matches)
+	}
+	end := len(matches)
+	if limit >= 0 && offset+limit < end {
+		end = offset + limit
+	}
+	matrices := matches[offset:end]
+
+	if filter.GroupCanonical {
+		counts := make(map[string]int)
+		for _, row := range m.matrices {
+			if row.record.CanonicalHash != nil {
+				counts[*row.record.CanonicalHash]++
+			}
+		}
+		for _, matrix := range matrices {
+			if matrix.CanonicalHash != nil {
+				count := counts[*matrix.CanonicalHash]
+				matrix.EquivalentCount = &count
+			}
+		}
+	}
+	return matrices, total, nil
+}
+
+// listingXor is the sort key of listings: the XOR count of the best run, or
+// ham_xor_count while no solver has a result
+func listingXor(record *MatrixRecord) int {
+	if record.SmallestXor != nil {
+		return *record.SmallestXor
+	}
+	return record.HamXorCount
+}
+
+// matchesFilter applies the conditions GetMatrices builds from filter to a
+// record with its results. As in SQL, a bound or an equality never matches
+// a missing value.
+func matchesFilter(record *MatrixRecord, filter MatrixFilter) bool {
+	if filter.Title != "" && !likeMatch(strings.ToLower(record.Title), "%"+strings.ToLower(filter.Title)+"%") {
+		return false
+	}
+
+	bounds := []struct {
+		value    *int
+		min, max *int
+	}{
+		{&record.HamXorCount, filter.HamXorMin, filter.HamXorMax},
+		{record.SmallestXor, filter.GXorMin, filter.GXorMax},
+		{record.SXorCount, filter.SXorMin, filter.SXorMax},
+		{record.FieldDegree, filter.FieldDegree, filter.FieldDegree},
+		{record.DifferentialBranch, filter.DifferentialBranchMin, nil},
+		{record.LinearBranch, filter.LinearBranchMin, nil},
+	}
+	for _, info := range persistedSolvers() {
+		if solverBounds, ok := filter.SolverXor[info.Name]; ok {
+			var xorCount *int
+			if result := record.Result(info.Name); result != nil {
+				xorCount = result.XorCount
+			}
+			bounds = append(bounds, struct {
+				value    *int
+				min, max *int
+			}{xorCount, solverBounds.Min, solverBounds.Max})
+		}
+	}
+	for _, bound := range bounds {
+		if bound.min != nil && (bound.value == nil || *bound.value < *bound.min) {
+			return false
+		}
+		if bound.max != nil && (bound.value == nil || *bound.value > *bound.max) {
+			return false
+		}
+	}
+
+	if filter.FieldPolynomial != "" && (record.FieldPolynomial == nil || *record.FieldPolynomial != filter.FieldPolynomial) {
+		return false
+	}
+	if filter.OptimalProven != nil && (record.OptimalXor != nil) != *filter.OptimalProven {
+		return false
+	}
+	for _, column := range analysisFlagColumns {
+		if value, ok := filter.Flags[column]; ok {
+			if flag := record.analysisFlag(column); flag == nil || *flag != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// likeMatch reports whether s matches the SQL LIKE pattern, where % matches
+// any run of characters and _ a single one
+func likeMatch(s, pattern string) bool {
+	text, pat := []rune(s), []rune(pattern)
+	// matched[j] reports whether text[:i] matches pat[:j] for the current i
+	matched := make([]bool, len(pat)+1)
+	matched[0] = true
+	for j := 1; j <= len(pat) && pat[j-1] == '%'; j++ {
+		matched[j] = true
+	}
+	for i := 1; i <= len(text); i++ {
+		previous := matched[0]
+		matched[0] = false
+		for j := 1; j <= len(pat); j++ {
+			current := matched[j]
+			switch pat[j-1] {
+			case '%':
+				matched[j] = matched[j-1] || matched[j]
+			case '_':
+				matched[j] = previous
+			default:
+				matched[j] = previous && pat[j-1] == text[i-1]
+			}
+			previous = current
+		}
+	}
+	return matched[len(pat)]
+}
+
+// GetMatricesWithoutAlgorithms returns matrices missing the result of a
+// default solver, oldest first
+func (m *MemoryStore) GetMatricesWithoutAlgorithms(limit int) ([]*MatrixRecord, error) {
+	var defaults []string
+	for _, info := range persistedSolvers() {
+		if info.Default {
+			defaults = append(defaults, info.Name)
+		}
+	}
+	if len(defaults) == 0 {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matrices []*MatrixRecord
+	for _, id := range m.sortedMatrixIDs() {
+		record := m.full(m.matrices[id])
+		for _, name := range defaults {
+			if result := record.Result(name); result == nil || *result.Status == StatusAborted {
+				matrices = append(matrices, record)
+				break
+			}
+		}
+	}
+	sort.SliceStable(matrices, func(i, j int) bool {
+		return matrices[i].CreatedAt.Before(matrices[j].CreatedAt)
+	})
+	if limit >= 0 && len(matrices) > limit {
+		matrices = matrices[:limit]
+	}
+	return matrices, nil
+}
+
+// GetAllMatrixHashes returns all stored matrix hashes
+func (m *MemoryStore) GetAllMatrixHashes() (map[string]bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	hashes := make(map[string]bool, len(m.byHash))
+	for hash := range m.byHash {
+		hashes[hash] = true
+	}
+	return hashes, nil
+}
+
+// TitleExists reports whether a matrix with the given title is stored
+func (m *MemoryStore) TitleExists(title string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, row := range m.matrices {
+		if row.record.Title == title {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// update runs change on the row of a matrix under the write lock and marks
+// the row updated if change reports a change. Missing matrices are skipped,
+// like an UPDATE that matches no row.
+func (m *MemoryStore) update(id int, change func(row *memoryMatrix) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if row := m.matrices[id]; row != nil && change(row) {
+		row.touch()
+	}
+}
+
+// UpdateHamXorCount stores a recalculated Hamming XOR count
+func (m *MemoryStore) UpdateHamXorCount(id int, hamXor int) error {
+	m.update(id, func(row *memoryMatrix) bool {
+		row.record.HamXorCount = hamXor
+		return true
+	})
+	return nil
+}
+
+// SetFieldRepresentation stores the GF(2^m) form of a matrix and reports
+// whether it did; a matrix that already has one keeps it
+func (m *MemoryStore) SetFieldRepresentation(id int, field *GF2m, elements FieldMatrix) (bool, error) {
+	stored := false
+	m.update(id, func(row *memoryMatrix) bool {
+		if row.fieldMatrix != nil {
+			return false
+		}
+		text, polynomial, degree := field.Format(elements), field.Polynomial(), field.M
+		row.fieldMatrix, row.record.FieldPolynomial, row.record.FieldDegree = &text, &polynomial, &degree
+		stored = true
+		return true
+	})
+	return stored, nil
+}
+
+// SaveAnalysis stores the analysis flags of a matrix
+func (m *MemoryStore) SaveAnalysis(id int, analysis *MatrixAnalysis) error {
+	m.update(id, func(row *memoryMatrix) bool {
+		record := &row.record
+		involutory, semiInvolutory := analysis.Involutory, analysis.SemiInvolutory
+		orthogonal, circulant := analysis.Orthogonal, analysis.Circulant
+		now := time.Now()
+		record.DifferentialBranch, record.LinearBranch = copyInt(analysis.DifferentialBranch), copyInt(analysis.LinearBranch)
+		record.IsMDS, record.IsNearMDS = copyBool(analysis.MDS), copyBool(analysis.NearMDS)
+		record.IsInvolutory, record.IsSemiInvolutory = &involutory, &semiInvolutory
+		record.IsOrthogonal, record.IsCirculant = &orthogonal, &circulant
+		record.AnalyzedAt = &now
+		return true
+	})
+	return nil
+}
+
+// SetInverseReference points the original matrix at its stored inverse
+func (m *MemoryStore) SetInverseReference(originalID, inverseID int, inverseHash string) error {
+	m.update(originalID, func(row *memoryMatrix) bool {
+		row.record.InverseMatrixID, row.record.InverseMatrixHash = &inverseID, &inverseHash
+		return true
+	})
+	return nil
+}
+
+// UpdateVerification stores the verifier verdicts keyed by run ID; runs of
+// other matrices are left alone
+func (m *MemoryStore) UpdateVerification(id int, verdicts map[int]bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for runID, valid := range verdicts {
+		if stored := m.runByID(runID); stored != nil && stored.run.MatrixID == id {
+			stored.run.Verified = copyBool(&valid)
+		}
+	}
+	return nil
+}
+
+// SaveAlgorithmRun appends a solver result to the runs of a matrix and
+// returns the run ID, selecting the solver's result and the best run like
+// Database.SaveAlgorithmRun
+func (m *MemoryStore) SaveAlgorithmRun(matrixID int, algorithm string, result *AlgResult, asResult bool) (int, error) {
+	info, ok := GetSolverInfo(algorithm)
+	if !ok {
+		return 0, fmt.Errorf("desteklenmeyen algoritma: %s", algorithm)
+	}
+
+	parameters := result.Parameters
+	if parameters == nil {
+		parameters = SolverParams{}
+	}
+	parametersJson, err := json.Marshal(parameters)
+	if err != nil {
+		return 0, err
+	}
+	key := resultRunKey(result)
+	durationMs := result.DurationMs
+	stored := &memoryRun{
+		run: AlgorithmRun{
+			MatrixID:      matrixID,
+			Algorithm:     info.Name,
+			DepthLimit:    key.DepthLimit,
+			Transpose:     key.Transpose,
+			XorCount:      copyInt(&result.XorCount),
+			DurationMs:    &durationMs,
+			Seed:          copyInt64(result.Seed),
+			SolverVersion: info.Version,
+			Status:        result.Status,
+			CreatedAt:     time.Now(),
+		},
+		parameters: string(parametersJson),
+	}
+	if result.Status != StatusAborted {
+		if info.HasDepth {
+			stored.run.Depth = copyInt(&result.Depth)
+		}
+		programJson, err := json.Marshal(result.Program)
+		if err != nil {
+			return 0, err
+		}
+		program := string(programJson)
+		stored.program = &program
+	}
+	if result.Origin != "" {
+		origin := result.Origin
+		stored.run.Origin = &origin
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	row := m.matrices[matrixID]
+	if row == nil {
+		return 0, fmt.Errorf("matris bulunamadı: %d", matrixID)
+	}
+	stored.run.ID = len(m.runs) + 1
+	m.runs = append(m.runs, stored)
+	m.matrixRuns[matrixID] = append(m.matrixRuns[matrixID], stored)
+
+	if !asResult {
+		current := m.resultRun(matrixID, info.Name)
+		asResult = current == nil || storedRunKey(&current.run) == key
+	}
+	if asResult {
+		m.selectResultRun(matrixID, info.Name, key)
+	}
+
+	// The earliest of equal runs keeps the pointer
+	var best *memoryRun
+	for _, candidate := range m.matrixRuns[matrixID] {
+		run := &candidate.run
+		if run.Status == StatusAborted || run.XorCount == nil {
+			continue
+		}
+		if best == nil || *run.XorCount < *best.run.XorCount {
+			best = candidate
+		}
+	}
+	row.record.BestRunID = nil
+	if best != nil {
+		row.record.BestRunID = copyInt(&best.run.ID)
+	}
+	row.touch()
+	return stored.run.ID, nil
+}
+
+// resultRun returns the run marked as the solver's result on a matrix, or nil
+func (m *MemoryStore) resultRun(matrixID int, algorithm string) *memoryRun {
+	for _, stored := range m.matrixRuns[matrixID] {
+		if stored.run.Algorithm == algorithm && stored.run.Best {
+			return stored
+		}
+	}
+	return nil
+}
+
+// selectResultRun marks the best run of algorithm with the given key as the
+// solver's result on the matrix and unmarks its other runs
+func (m *MemoryStore) selectResultRun(matrixID int, algorithm string, key runKey) {
+	var best *memoryRun
+	for _, stored := range m.matrixRuns[matrixID] {
+		run := &stored.run
+		if run.Algorithm == algorithm && storedRunKey(run) == key && (best == nil || betterRun(run, &best.run)) {
+			best = stored
+		}
+	}
+	for _, stored := range m.matrixRuns[matrixID] {
+		if stored.run.Algorithm == algorithm {
+			stored.run.Best = stored == best
+		}
+	}
+}
+
+// storedRunKey returns the runKey of a stored run
+func storedRunKey(run *AlgorithmRun) runKey {
+	return runKey{DepthLimit: run.DepthLimit, Transpose: run.Transpose}
+}
+
+// betterRun reports whether a sorts before b in bestRunOrder. A missing
+// depth sorts last, as NULLs do in ascending PostgreSQL order.
+func betterRun(a, b *AlgorithmRun) bool {
+	if abortedA, abortedB := a.Status == StatusAborted, b.Status == StatusAborted; abortedA != abortedB {
+		return abortedB
+	}
+	if (a.XorCount == nil) != (b.XorCount == nil) {
+		return b.XorCount == nil
+	}
+	if a.XorCount != nil && *a.XorCount != *b.XorCount {
+		return *a.XorCount < *b.XorCount
+	}
+	if (a.Depth == nil) != (b.Depth == nil) {
+		return b.Depth == nil
+	}
+	if a.Depth != nil && *a.Depth != *b.Depth {
+		return *a.Depth < *b.Depth
+	}
+	return a.ID > b.ID
+}
+
+// GetAlgorithmRuns returns the runs of record, newest first, optionally only
+// those of one algorithm
+func (m *MemoryStore) GetAlgorithmRuns(record *MatrixRecord, algorithm string) ([]*AlgorithmRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	runs := []*AlgorithmRun{}
+	stored := m.matrixRuns[record.ID]
+	for i := len(stored) - 1; i >= 0; i-- {
+		if algorithm != "" && stored[i].run.Algorithm != algorithm {
+			continue
+		}
+		run := stored[i].run
+		if err := json.Unmarshal([]byte(stored[i].parameters), &run.Parameters); err != nil {
+			log.Printf("⚠️ Matris %d çalıştırma %d parametreleri okunamadı: %v", record.ID, run.ID, err)
+		}
+		run.Program = scanProgram(storedText(stored[i].program), record, fmt.Sprintf("%s (çalıştırma %d)", run.Algorithm, run.ID))
+		run.XorCount, run.Depth = copyInt(run.XorCount), copyInt(run.Depth)
+		run.Verified = copyBool(run.Verified)
+		runs = append(runs, &run)
+	}
+	return runs, nil
+}
+
+// GetDepthResults returns the best run of record per solver with depth, depth
+// limit and transpose option, ordered by algorithm and depth limit
+func (m *MemoryStore) GetDepthResults(record *MatrixRecord) ([]*DepthResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.depthResultsOf(record), nil
+}
+
+// depthResultsOf is GetDepthResults for callers holding the lock
+func (m *MemoryStore) depthResultsOf(record *MatrixRecord) []*DepthResult {
+	type depthKey struct {
+		algorithm string
+		key       runKey
+	}
+	best := make(map[depthKey]*memoryRun)
+	for _, stored := range m.matrixRuns[record.ID] {
+		run := &stored.run
+		if run.Status == StatusAborted || run.XorCount == nil || run.Depth == nil {
+			continue
+		}
+		key := depthKey{run.Algorithm, storedRunKey(run)}
+		if current := best[key]; current == nil || betterRun(run, &current.run) {
+			best[key] = stored
+		}
+	}
+
+	var results []*DepthResult
+	for _, stored := range best {
+		run := &stored.run
+		results = append(results, &DepthResult{
+			RunID:      run.ID,
+			Algorithm:  run.Algorithm,
+			DepthLimit: run.DepthLimit,
+			Transpose:  run.Transpose,
+			XorCount:   *run.XorCount,
+			Depth:      *run.Depth,
+			Program:    scanProgram(storedText(stored.program), record, fmt.Sprintf("%s (derinlik sınırı %d)", run.Algorithm, run.DepthLimit)),
+			Seed:       copyInt64(run.Seed),
+			Status:     run.Status,
+			UpdatedAt:  run.CreatedAt,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Algorithm != b.Algorithm {
+			return a.Algorithm < b.Algorithm
+		}
+		if a.DepthLimit != b.DepthLimit {
+			return a.DepthLimit < b.DepthLimit
+		}
+		return !a.Transpose && b.Transpose
+	})
+	return results
+}
+
+// SaveOptimalResult stores what an exact search proved: the lower bound only
+// grows, and OptimalXor is set only once the bound meets the XOR count
+func (m *MemoryStore) SaveOptimalResult(matrixID int, result *AlgResult) error {
+	m.update(matrixID, func(row *memoryMatrix) bool {
+		record := &row.record
+		if record.XorLowerBound == nil || *record.XorLowerBound < result.LowerBound {
+			record.XorLowerBound = copyInt(&result.LowerBound)
+		}
+		if result.Status == StatusCompleted && result.LowerBound == result.XorCount {
+			record.OptimalXor = copyInt(&result.XorCount)
+		}
+		return true
+	})
+	return nil
+}
+
+// SaveInPlaceResult stores the best s-XOR program of a matrix and the source
+// it came from; a nil program clears the s-XOR fields
+func (m *MemoryStore) SaveInPlaceResult(id int, program *InPlaceProgram, source string) error {
+	var count *int
+	var programStr, sourceStr *string
+	if program != nil {
+		programJson, err := json.Marshal(program)
+		if err != nil {
+			return err
+		}
+		xorCount, str := program.XorCount(), string(programJson)
+		count, programStr, sourceStr = &xorCount, &str, &source
+	}
+
+	m.update(id, func(row *memoryMatrix) bool {
+		row.record.SXorCount, row.sxorProgram, row.record.SXorSource = count, programStr, sourceStr
+		return true
+	})
+	return nil
+}
+
+// ReplaceParetoFront replaces the stored Pareto front of a matrix with points
+func (m *MemoryStore) ReplaceParetoFront(matrixID int, points []*ParetoPoint) error {
+	stored := make([]*memoryParetoPoint, 0, len(points))
+	for _, point := range points {
+		programJson, err := json.Marshal(point.Program)
+		if err != nil {
+			return err
+		}
+		copied := *point
+		copied.Program, copied.Seed = nil, copyInt64(point.Seed)
+		stored = append(stored, &memoryParetoPoint{point: copied, program: string(programJson)})
+	}
+	sort.Slice(stored, func(i, j int) bool {
+		return stored[i].point.Depth < stored[j].point.Depth
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(stored) > 0 && m.matrices[matrixID] == nil {
+		return fmt.Errorf("matris bulunamadı: %d", matrixID)
+	}
+	m.paretoPoints[matrixID] = stored
+	return nil
+}
+
+// GetParetoFront returns the stored Pareto front of record ordered by depth
+func (m *MemoryStore) GetParetoFront(record *MatrixRecord) ([]*ParetoPoint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	points := []*ParetoPoint{}
+	for _, stored := range m.paretoPoints[record.ID] {
+		point := stored.point
+		point.Program = scanProgram(sql.NullString{String: stored.program, Valid: true}, record,
+			fmt.Sprintf("pareto (derinlik %d)", point.Depth))
+		points = append(points, &point)
+	}
+	return points, nil
+}
+
+// CreateGeneratorJob stores a new generator job and sets its ID
+func (m *MemoryStore) CreateGeneratorJob(job *GeneratorJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastJobID++
+	now := time.Now()
+	job.ID, job.CreatedAt, job.UpdatedAt = m.lastJobID, now, now
+	m.generatorJobs[job.ID] = &GeneratorJob{
+		ID:              job.ID,
+		Family:          job.Family,
+		Polynomial:      job.Polynomial,
+		FieldDegree:     job.FieldDegree,
+		Dimension:       job.Dimension,
+		Algorithms:      append([]string(nil), job.Algorithms...),
+		MaxResults:      job.MaxResults,
+		Cursor:          append([]uint32(nil), job.Cursor...),
+		CandidatesTotal: job.CandidatesTotal,
+		Status:          job.Status,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	return nil
+}
+
+// SaveGeneratorProgress stores the cursor, counters, limit and status of a job
+func (m *MemoryStore) SaveGeneratorProgress(job *GeneratorJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := m.generatorJobs[job.ID]
+	if stored == nil {
+		return nil
+	}
+	stored.Cursor = append([]uint32(nil), job.Cursor...)
+	stored.Examined, stored.Found, stored.Inserted, stored.Duplicates = job.Examined, job.Found, job.Inserted, job.Duplicates
+	stored.MaxResults, stored.Status, stored.Error = job.MaxResults, job.Status, copyString(job.Error)
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+// GetGeneratorJob retrieves a generator job by ID, or nil if it does not exist
+func (m *MemoryStore) GetGeneratorJob(id int) (*GeneratorJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if stored := m.generatorJobs[id]; stored != nil {
+		return stored.snapshot(), nil
+	}
+	return nil, nil
+}
+
+// GetGeneratorJobs returns the generator jobs with the given status, or all of
+// them if status is empty, newest first
+func (m *MemoryStore) GetGeneratorJobs(status string) ([]*GeneratorJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jobs := []*GeneratorJob{}
+	for id := m.lastJobID; id > 0; id-- {
+		if stored := m.generatorJobs[id]; stored != nil && (status == "" || stored.Status == status) {
+			jobs = append(jobs, stored.snapshot())
+		}
+	}
+	return jobs, nil
+}
+
+// EnqueueAlgorithmJob stores job as queued and sets its ID
+func (m *MemoryStore) EnqueueAlgorithmJob(job *AlgorithmJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.matrices[job.MatrixID] == nil {
+		return fmt.Errorf("matris bulunamadı: %d", job.MatrixID)
+	}
+	m.lastAlgorithmJobID++
+	now := time.Now()
+	job.ID, job.CreatedAt, job.UpdatedAt = m.lastAlgorithmJobID, now, now
+	job.Status, job.Attempts, job.RunAfter = AlgorithmJobQueued, 0, now.UTC()
+	job.LeaseOwner, job.LeaseExpiresAt, job.HeartbeatAt, job.LastError, job.FinishedAt = nil, nil, nil, nil, nil
+	m.algorithmJobs[job.ID] = job.snapshot()
+	return nil
+}
+
+// LeaseAlgorithmJob marks the next due queued job running under owner until
+// the lease expires and returns it, or nil if no job is due
+func (m *MemoryStore) LeaseAlgorithmJob(owner string, lease time.Duration) (*AlgorithmJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	var next *AlgorithmJob
+	for _, stored := range m.algorithmJobs {
+		if stored.Status != AlgorithmJobQueued || stored.RunAfter.After(now) {
+			continue
+		}
+		if next == nil || stored.Priority > next.Priority ||
+			stored.Priority == next.Priority && (stored.RunAfter.Before(next.RunAfter) ||
+				stored.RunAfter.Equal(next.RunAfter) && stored.ID < next.ID) {
+			next = stored
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	expires := now.Add(lease)
+	next.Status, next.Attempts = AlgorithmJobRunning, next.Attempts+1
+	next.LeaseOwner, next.LeaseExpiresAt, next.HeartbeatAt = &owner, &expires, &now
+	next.UpdatedAt = time.Now()
+	return next.snapshot(), nil
+}
+
+// leased returns the stored job while job still holds its lease, or nil
+func (m *MemoryStore) leased(job *AlgorithmJob) *AlgorithmJob {
+	stored := m.algorithmJobs[job.ID]
+	if stored == nil || stored.Status != AlgorithmJobRunning || stored.Attempts != job.Attempts ||
+		stored.LeaseOwner == nil || job.LeaseOwner == nil || *stored.LeaseOwner != *job.LeaseOwner {
+		return nil
+	}
+	return stored
+}
+
+// RenewAlgorithmJobLease extends the lease of a running job; false means the
+// lease was lost
+func (m *MemoryStore) RenewAlgorithmJobLease(job *AlgorithmJob, lease time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := m.leased(job)
+	if stored == nil {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	expires := now.Add(lease)
+	stored.LeaseExpiresAt, stored.HeartbeatAt, stored.UpdatedAt = &expires, &now, time.Now()
+	job.LeaseExpiresAt, job.HeartbeatAt = copyTime(&expires), copyTime(&now)
+	return true, nil
+}
+
+// FinishAlgorithmJob releases the lease of a running job and stores its
+// status, algorithms, run_after, last_error and finished_at; false means the
+// lease was lost and nothing was stored
+func (m *MemoryStore) FinishAlgorithmJob(job *AlgorithmJob) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := m.leased(job)
+	if stored == nil {
+		return false, nil
+	}
+	stored.Status, stored.Algorithms, stored.RunAfter = job.Status, append([]string(nil), job.Algorithms...), job.RunAfter
+	stored.LastError, stored.FinishedAt = copyString(job.LastError), copyTime(job.FinishedAt)
+	stored.LeaseOwner, stored.LeaseExpiresAt, stored.UpdatedAt = nil, nil, time.Now()
+	job.LeaseOwner, job.LeaseExpiresAt = nil, nil
+	return true, nil
+}
+
+// ReleaseAlgorithmJob queues a running job again at once with its
+// algorithms, without counting the interrupted attempt; false means the lease
+// was lost and nothing was stored
+func (m *MemoryStore) ReleaseAlgorithmJob(job *AlgorithmJob) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := m.leased(job)
+	if stored == nil {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	stored.Status, stored.Algorithms, stored.RunAfter = AlgorithmJobQueued, append([]string(nil), job.Algorithms...), now
+	stored.Attempts--
+	stored.LeaseOwner, stored.LeaseExpiresAt, stored.UpdatedAt = nil, nil, time.Now()
+	job.Status, job.Attempts, job.RunAfter = AlgorithmJobQueued, stored.Attempts, now
+	job.LeaseOwner, job.LeaseExpiresAt = nil, nil
+	return true, nil
+}
+
+// RequeueExpiredAlgorithmJobs queues the running jobs whose lease expired
+// again, or dead-letters those that used up their attempts, and returns the
+// number queued again
+func (m *MemoryStore) RequeueExpiredAlgorithmJobs() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	message := "iş kirası doldu, worker yanıt vermedi"
+	requeued := 0
+	for _, stored := range m.algorithmJobs {
+		if stored.Status != AlgorithmJobRunning || stored.LeaseExpiresAt == nil || !stored.LeaseExpiresAt.Before(now) {
+			continue
+		}
+		if stored.Attempts >= stored.MaxAttempts {
+			stored.Status, stored.FinishedAt = AlgorithmJobDead, copyTime(&now)
+		} else {
+			stored.Status, stored.RunAfter = AlgorithmJobQueued, now
+			requeued++
+		}
+		stored.LastError = copyString(&message)
+		stored.LeaseOwner, stored.LeaseExpiresAt, stored.UpdatedAt = nil, nil, time.Now()
+	}
+	return requeued, nil
+}
+
+// RetryAlgorithmJob queues a dead job again with fresh attempts and no error;
+// false means it does not exist or is not dead
+func (m *MemoryStore) RetryAlgorithmJob(id int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := m.algorithmJobs[id]
+	if stored == nil || stored.Status != AlgorithmJobDead {
+		return false, nil
+	}
+	stored.Status, stored.Attempts, stored.RunAfter = AlgorithmJobQueued, 0, time.Now().UTC()
+	stored.LastError, stored.FinishedAt, stored.UpdatedAt = nil, nil, time.Now()
+	return true, nil
+}
+
+// GetAlgorithmJob retrieves an algorithm job by ID, or nil if it does not exist
+func (m *MemoryStore) GetAlgorithmJob(id int) (*AlgorithmJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if stored := m.algorithmJobs[id]; stored != nil {
+		return stored.snapshot(), nil
+	}
+	return nil, nil
+}
+
+// GetAlgorithmJobs returns at most limit algorithm jobs, newest first,
+// filtered by status and matrix unless these are empty or 0
+func (m *MemoryStore) GetAlgorithmJobs(status string, matrixID int, limit int) ([]*AlgorithmJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jobs := []*AlgorithmJob{}
+	for id := m.lastAlgorithmJobID; id > 0 && len(jobs) < limit; id-- {
+		stored := m.algorithmJobs[id]
+		if stored == nil || status != "" && stored.Status != status || matrixID != 0 && stored.MatrixID != matrixID {
+			continue
+		}
+		jobs = append(jobs, stored.snapshot())
+	}
+	return jobs, nil
+}
+
+// copyInt returns a pointer to a copy of *value, or nil
+func copyInt(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// copyInt64 returns a pointer to a copy of *value, or nil
+func copyInt64(value *int64) *int64 {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// copyBool returns a pointer to a copy of *value, or nil
+func copyBool(value *bool) *bool {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// copyString returns a pointer to a copy of *value, or nil
+func copyString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// copyTime returns a pointer to a copy of *value, or nil
+func copyTime(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
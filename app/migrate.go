@@ -0,0 +1,336 @@
+package main
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Schema migrations live in migrations/<driver>/ as <version>_<name>.up.sql
+// and <version>_<name>.down.sql, versions numbered 1, 2, ... without gaps.
+// Every migration is written once per driver under the same version. A
+// migration that has been released must not be edited; schema changes go
+// into a new one.
+//
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID is the advisory lock held while migrations run, so two
+// instances starting together do not apply the same migration
+const migrationLockID = 7230011
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState is a migration together with whether it is applied
+type MigrationState struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// loadMigrations reads the embedded migrations of driver ordered by version
+func loadMigrations(driver string) ([]*Migration, error) {
+	dir := path.Join("migrations", driver)
+	entries, err := migrationFiles.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("geçersiz migration dosya adı: %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d iki farklı isimle tanımlı: %s, %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migration %d eksik", i+1)
+		}
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d için up ve down dosyaları gerekli", migration.Version)
+		}
+	}
+	return migrations, nil
+}
+
+// ensureMigrationsTable creates schema_migrations if it does not exist
+func ensureMigrationsTable(database *Database) error {
+	_, err := database.db.Exec(`
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	return err
+}
+
+// appliedMigrations returns the applied versions with their apply times
+func appliedMigrations(database *Database) (map[int]time.Time, error) {
+	rows, err := database.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// MigrationStatus lists every known migration and when it was applied.
+// Applied versions this binary does not know are listed without a name.
+func MigrationStatus(database *Database) ([]MigrationState, error) {
+	migrations, err := loadMigrations(database.driver)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(database); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(database)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, migration := range migrations {
+		state := MigrationState{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			state.AppliedAt = &appliedAt
+		}
+		states = append(states, state)
+	}
+	for version, appliedAt := range applied {
+		if version > len(migrations) {
+			appliedAt := appliedAt
+			states = append(states, MigrationState{Version: version, Name: "?", AppliedAt: &appliedAt})
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Version < states[j].Version
+	})
+	return states, nil
+}
+
+// checkSchemaVersion fails if the database has migrations applied that this
+// binary does not know, i.e. it was migrated by a newer release
+func checkSchemaVersion(database *Database) error {
+	migrations, err := loadMigrations(database.driver)
+	if err != nil {
+		return err
+	}
+	if err := ensureMigrationsTable(database); err != nil {
+		return err
+	}
+
+	var current sql.NullInt64
+	if err := database.db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&current); err != nil {
+		return err
+	}
+	if latest := len(migrations); current.Valid && int(current.Int64) > latest {
+		return fmt.Errorf("veritabanı şema sürümü %d, bu sürüm en fazla %d biliyor; daha yeni bir sürümle migrate edilmiş", current.Int64, latest)
+	}
+	return nil
+}
+
+// MigrateUp applies the pending migrations up to target, or all of them when
+// target is 0, and returns the number applied. Each migration runs in its own
+// transaction together with its schema_migrations row.
+func MigrateUp(database *Database, target int) (int, error) {
+	migrations, err := loadMigrations(database.driver)
+	if err != nil {
+		return 0, err
+	}
+	if target == 0 {
+		target = len(migrations)
+	}
+	if target < 0 || target > len(migrations) {
+		return 0, fmt.Errorf("bilinmeyen migration sürümü: %d", target)
+	}
+	if err := checkSchemaVersion(database); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range migrations[:target] {
+		applied, err := runMigration(database, migration, true)
+		if err != nil {
+			return count, fmt.Errorf("migration %d_%s uygulanamadı: %v", migration.Version, migration.Name, err)
+		}
+		if applied {
+			log.Printf("✅ [MIGRATE] %d_%s uygulandı", migration.Version, migration.Name)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MigrateDown reverts the last steps applied migrations and returns the
+// number reverted
+func MigrateDown(database *Database, steps int) (int, error) {
+	migrations, err := loadMigrations(database.driver)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkSchemaVersion(database); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := migrations[i]
+		reverted, err := runMigration(database, migration, false)
+		if err != nil {
+			return count, fmt.Errorf("migration %d_%s geri alınamadı: %v", migration.Version, migration.Name, err)
+		}
+		if reverted {
+			log.Printf("♻️  [MIGRATE] %d_%s geri alındı", migration.Version, migration.Name)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// runMigration applies (up) or reverts (down) one migration unless it is
+// already in that state, and reports whether it ran
+func runMigration(database *Database, migration *Migration, up bool) (bool, error) {
+	tx, err := database.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// SQLite transactions take the write lock when they begin (_txlock=immediate)
+	if database.driver == DriverPostgres {
+		if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+			return false, err
+		}
+	}
+	var applied bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", migration.Version).Scan(&applied); err != nil {
+		return false, err
+	}
+	if applied == up {
+		return false, nil
+	}
+
+	if up {
+		if _, err := tx.Exec(migration.Up); err != nil {
+			return false, err
+		}
+		_, err = tx.Exec("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+	} else {
+		if _, err := tx.Exec(migration.Down); err != nil {
+			return false, err
+		}
+		_, err = tx.Exec("DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// runMigrateCommand implements "migrate status", "migrate up [version]" and
+// "migrate down [steps]"; down reverts one migration by default
+func runMigrateCommand(config *Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("kullanım: migrate status|up [sürüm]|down [adım]")
+	}
+	number := 0
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 0 {
+			return fmt.Errorf("geçersiz sayı: %s", args[1])
+		}
+		number = n
+	}
+
+	database, err := openDatabase(config)
+	if err != nil {
+		return fmt.Errorf("veritabanı bağlantısı kurulamadı: %v", err)
+	}
+	defer database.Close()
+
+	switch args[0] {
+	case "status":
+		states, err := MigrationStatus(database)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			applied := "bekliyor"
+			if state.AppliedAt != nil {
+				applied = state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-24s  %s\n", state.Version, state.Name, applied)
+		}
+		return nil
+	case "up":
+		applied, err := MigrateUp(database, number)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migration uygulandı\n", applied)
+		return nil
+	case "down":
+		if number == 0 {
+			number = 1
+		}
+		reverted, err := MigrateDown(database, number)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migration geri alındı\n", reverted)
+		return nil
+	}
+	return fmt.Errorf("bilinmeyen migrate komutu: %s", args[0])
+}
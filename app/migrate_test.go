@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestDatabase returns a migrated SQLite database in a temporary directory
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	database, err := NewSQLiteDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("SQLite veritabanı açılamadı: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if _, err := MigrateUp(database, 0); err != nil {
+		t.Fatalf("migration hatası: %v", err)
+	}
+	return database
+}
+
+// tableExists reports whether the SQLite database has the named table
+func tableExists(t *testing.T, database *Database, table string) bool {
+	t.Helper()
+	var count int
+	err := database.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = $1", table).Scan(&count)
+	if err != nil {
+		t.Fatalf("sqlite_master okunamadı: %v", err)
+	}
+	return count > 0
+}
+
+func TestLoadMigrations(t *testing.T) {
+	var versions []int
+	for _, driver := range []string{DriverPostgres, DriverSQLite} {
+		migrations, err := loadMigrations(driver)
+		if err != nil {
+			t.Fatalf("%s: gömülü migration'lar okunamadı: %v", driver, err)
+		}
+		if len(migrations) == 0 {
+			t.Fatalf("%s: hiç migration yok", driver)
+		}
+		for i, migration := range migrations {
+			if migration.Version != i+1 {
+				t.Errorf("%s: %d. migration sürümü %d", driver, i+1, migration.Version)
+			}
+			if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+				t.Errorf("%s: migration %d_%s: up ve down boş olamaz", driver, migration.Version, migration.Name)
+			}
+		}
+		versions = append(versions, len(migrations))
+	}
+	if versions[0] != versions[1] {
+		t.Errorf("postgres %d, sqlite %d migration; her sürücüde aynı sürümler olmalı", versions[0], versions[1])
+	}
+}
+
+func TestMigrateUpDownUp(t *testing.T) {
+	database := newTestDatabase(t)
+	migrations, _ := loadMigrations(DriverSQLite)
+
+	record, err := SaveMatrix(database, "zincir", chainMatrix, "test")
+	if err != nil {
+		t.Fatalf("SaveMatrix: %v", err)
+	}
+	result, err := runSolver(context.Background(), "paar", nil, chainMatrix)
+	if err != nil {
+		t.Fatalf("paar: %v", err)
+	}
+	if err := UpdateMatrixResults(database, record.ID, map[string]*AlgResult{"paar": result}); err != nil {
+		t.Fatalf("UpdateMatrixResults: %v", err)
+	}
+
+	// Reverting to the baseline moves the result back into its columns
+	if reverted, err := MigrateDown(database, len(migrations)-1); err != nil || reverted != len(migrations)-1 {
+		t.Fatalf("MigrateDown(%d) = %d, %v", len(migrations)-1, reverted, err)
+	}
+	if tableExists(t, database, "algorithm_runs") || !tableExists(t, database, "depth_results") {
+		t.Fatal("geri alma sonrası algorithm_runs kaldırılmalı, depth_results geri gelmeli")
+	}
+	var paarXor, smallestXor int
+	err = database.db.QueryRow("SELECT paar_xor_count, smallest_xor FROM matrix_records WHERE id = $1", record.ID).Scan(&paarXor, &smallestXor)
+	if err != nil {
+		t.Fatalf("eski sütunlar okunamadı: %v", err)
+	}
+	if paarXor != result.XorCount || smallestXor != result.XorCount {
+		t.Errorf("paar_xor_count %d, smallest_xor %d; beklenen %d", paarXor, smallestXor, result.XorCount)
+	}
+
+	// Applying it again turns the columns into a legacy run
+	if applied, err := MigrateUp(database, 0); err != nil || applied != len(migrations)-1 {
+		t.Fatalf("MigrateUp = %d, %v", applied, err)
+	}
+	stored, err := database.GetMatrixByID(record.ID)
+	if err != nil {
+		t.Fatalf("GetMatrixByID: %v", err)
+	}
+	paar := stored.Result("paar")
+	if paar == nil || paar.XorCount == nil || *paar.XorCount != result.XorCount {
+		t.Fatalf("paar sonucu %+v, beklenen %d XOR", paar, result.XorCount)
+	}
+	if stored.BestRunID == nil || *stored.BestRunID != paar.RunID {
+		t.Errorf("best_run_id %v, beklenen %d", stored.BestRunID, paar.RunID)
+	}
+	runs, err := database.GetAlgorithmRuns(stored, "paar")
+	if err != nil || len(runs) != 1 || runs[0].SolverVersion != "legacy" {
+		t.Errorf("taşınan çalıştırmalar %v (%v), beklenen tek legacy çalıştırma", runs, err)
+	}
+
+	// The whole schema goes away and comes back
+	if reverted, err := MigrateDown(database, len(migrations)); err != nil || reverted != len(migrations) {
+		t.Fatalf("MigrateDown(tümü) = %d, %v", reverted, err)
+	}
+	if tableExists(t, database, "matrix_records") {
+		t.Fatal("tüm migration'lar geri alınınca matrix_records kalmamalı")
+	}
+	if applied, err := MigrateUp(database, 0); err != nil || applied != len(migrations) {
+		t.Fatalf("MigrateUp = %d, %v", applied, err)
+	}
+	states, err := MigrationStatus(database)
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	for _, state := range states {
+		if state.AppliedAt == nil {
+			t.Errorf("migration %d_%s uygulanmamış", state.Version, state.Name)
+		}
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	database := newTestDatabase(t)
+	if err := checkSchemaVersion(database); err != nil {
+		t.Fatalf("güncel şema reddedildi: %v", err)
+	}
+
+	migrations, _ := loadMigrations(DriverSQLite)
+	future := len(migrations) + 1
+	if _, err := database.db.Exec("INSERT INTO schema_migrations (version, name) VALUES ($1, 'future')", future); err != nil {
+		t.Fatalf("schema_migrations yazılamadı: %v", err)
+	}
+	if err := checkSchemaVersion(database); err == nil {
+		t.Error("daha yeni şema sürümü için hata bekleniyordu")
+	}
+	if _, err := MigrateUp(database, 0); err == nil {
+		t.Error("MigrateUp daha yeni şemada hata vermeliydi")
+	}
+	if _, err := MigrateDown(database, 1); err == nil {
+		t.Error("MigrateDown daha yeni şemada hata vermeliydi")
+	}
+}
@@ -0,0 +1,83 @@
+-- Restores the per-solver result columns and depth_results from the runs
+-- marked as results; the rest of the run history is lost
+ALTER TABLE matrix_records
+	ADD COLUMN smallest_xor INTEGER,
+	ADD COLUMN boyar_xor_count INTEGER,
+	ADD COLUMN boyar_depth INTEGER,
+	ADD COLUMN boyar_program TEXT,
+	ADD COLUMN boyar_seed BIGINT,
+	ADD COLUMN boyar_status VARCHAR(16),
+	ADD COLUMN boyar_origin VARCHAR(16),
+	ADD COLUMN boyar_verified BOOLEAN,
+	ADD COLUMN paar_xor_count INTEGER,
+	ADD COLUMN paar_program TEXT,
+	ADD COLUMN paar_status VARCHAR(16),
+	ADD COLUMN paar_origin VARCHAR(16),
+	ADD COLUMN paar_verified BOOLEAN,
+	ADD COLUMN paar2_xor_count INTEGER,
+	ADD COLUMN paar2_program TEXT,
+	ADD COLUMN paar2_status VARCHAR(16),
+	ADD COLUMN paar2_origin VARCHAR(16),
+	ADD COLUMN paar2_verified BOOLEAN,
+	ADD COLUMN slp_xor_count INTEGER,
+	ADD COLUMN slp_program TEXT,
+	ADD COLUMN slp_seed BIGINT,
+	ADD COLUMN slp_status VARCHAR(16),
+	ADD COLUMN slp_origin VARCHAR(16),
+	ADD COLUMN slp_verified BOOLEAN;
+
+UPDATE matrix_records m
+SET boyar_xor_count = r.xor_count, boyar_depth = r.depth, boyar_program = r.program, boyar_seed = r.seed,
+    boyar_status = r.status, boyar_origin = r.origin, boyar_verified = r.verified
+FROM algorithm_runs r WHERE r.matrix_id = m.id AND r.algorithm = 'boyar' AND r.best;
+
+UPDATE matrix_records m
+SET paar_xor_count = r.xor_count, paar_program = r.program,
+    paar_status = r.status, paar_origin = r.origin, paar_verified = r.verified
+FROM algorithm_runs r WHERE r.matrix_id = m.id AND r.algorithm = 'paar' AND r.best;
+
+UPDATE matrix_records m
+SET paar2_xor_count = r.xor_count, paar2_program = r.program,
+    paar2_status = r.status, paar2_origin = r.origin, paar2_verified = r.verified
+FROM algorithm_runs r WHERE r.matrix_id = m.id AND r.algorithm = 'paar2' AND r.best;
+
+UPDATE matrix_records m
+SET slp_xor_count = r.xor_count, slp_program = r.program, slp_seed = r.seed,
+    slp_status = r.status, slp_origin = r.origin, slp_verified = r.verified
+FROM algorithm_runs r WHERE r.matrix_id = m.id AND r.algorithm = 'slp' AND r.best;
+
+UPDATE matrix_records m
+SET smallest_xor = r.xor_count
+FROM algorithm_runs r WHERE r.id = m.best_run_id;
+
+CREATE INDEX idx_matrix_records_smallest_xor ON matrix_records(smallest_xor);
+CREATE INDEX idx_matrix_records_smallest_xor_created_at ON matrix_records(smallest_xor ASC, created_at DESC);
+CREATE INDEX idx_matrix_records_boyar_xor ON matrix_records(boyar_xor_count);
+CREATE INDEX idx_matrix_records_paar_xor ON matrix_records(paar_xor_count);
+CREATE INDEX idx_matrix_records_paar2_xor ON matrix_records(paar2_xor_count);
+CREATE INDEX idx_matrix_records_slp_xor ON matrix_records(slp_xor_count);
+
+CREATE TABLE depth_results (
+	matrix_id INTEGER NOT NULL REFERENCES matrix_records(id) ON DELETE CASCADE,
+	algorithm VARCHAR(32) NOT NULL,
+	depth_limit INTEGER NOT NULL,
+	xor_count INTEGER NOT NULL,
+	depth INTEGER NOT NULL,
+	program TEXT,
+	seed BIGINT,
+	status VARCHAR(16),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (matrix_id, algorithm, depth_limit)
+);
+
+-- Best non-aborted run with depth per depth limit
+INSERT INTO depth_results (matrix_id, algorithm, depth_limit, xor_count, depth, program, seed, status, updated_at)
+SELECT DISTINCT ON (matrix_id, algorithm, depth_limit)
+       matrix_id, algorithm, depth_limit, xor_count, depth, program, seed, status, created_at
+FROM algorithm_runs
+WHERE depth IS NOT NULL AND xor_count IS NOT NULL AND status <> 'aborted'
+ORDER BY matrix_id, algorithm, depth_limit, xor_count, depth, id DESC;
+
+ALTER TABLE matrix_records DROP COLUMN best_run_id;
+DROP TABLE algorithm_runs;
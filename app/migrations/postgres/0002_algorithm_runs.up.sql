@@ -0,0 +1,95 @@
+-- Every solver run; the best flag marks each solver's result on the matrix.
+-- The per-solver result columns of matrix_records and depth_results move
+-- here and are dropped.
+CREATE TABLE IF NOT EXISTS algorithm_runs (
+	id SERIAL PRIMARY KEY,
+	matrix_id INTEGER NOT NULL REFERENCES matrix_records(id) ON DELETE CASCADE,
+	algorithm VARCHAR(32) NOT NULL,
+	parameters TEXT,
+	depth_limit INTEGER NOT NULL DEFAULT 0,
+	transpose BOOLEAN NOT NULL DEFAULT FALSE,
+	xor_count INTEGER,
+	depth INTEGER,
+	program TEXT,
+	duration_ms BIGINT,
+	seed BIGINT,
+	solver_version VARCHAR(32) NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	origin VARCHAR(16),
+	verified BOOLEAN,
+	best BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_algorithm_runs_matrix ON algorithm_runs(matrix_id, algorithm, best);
+
+ALTER TABLE matrix_records ADD COLUMN IF NOT EXISTS best_run_id INTEGER;
+CREATE INDEX IF NOT EXISTS idx_matrix_records_best_run ON matrix_records(best_run_id);
+
+-- Stored results become "legacy" runs marked as the solver's result. Their
+-- depth limit is unknown and recorded as 0; origin was only set for runs
+-- with the transpose option.
+INSERT INTO algorithm_runs (matrix_id, algorithm, parameters, depth_limit, transpose, xor_count, depth, program,
+                            seed, solver_version, status, origin, verified, best, created_at)
+SELECT id, 'boyar', '{}', 0, boyar_origin IS NOT NULL, boyar_xor_count, boyar_depth, boyar_program,
+       boyar_seed, 'legacy', COALESCE(boyar_status, 'completed'), boyar_origin, boyar_verified, TRUE, updated_at
+FROM matrix_records WHERE boyar_xor_count IS NOT NULL OR boyar_status IS NOT NULL;
+
+INSERT INTO algorithm_runs (matrix_id, algorithm, parameters, depth_limit, transpose, xor_count, program,
+                            solver_version, status, origin, verified, best, created_at)
+SELECT id, 'paar', '{}', 0, paar_origin IS NOT NULL, paar_xor_count, paar_program,
+       'legacy', COALESCE(paar_status, 'completed'), paar_origin, paar_verified, TRUE, updated_at
+FROM matrix_records WHERE paar_xor_count IS NOT NULL OR paar_status IS NOT NULL;
+
+INSERT INTO algorithm_runs (matrix_id, algorithm, parameters, depth_limit, transpose, xor_count, program,
+                            solver_version, status, origin, verified, best, created_at)
+SELECT id, 'paar2', '{}', 0, paar2_origin IS NOT NULL, paar2_xor_count, paar2_program,
+       'legacy', COALESCE(paar2_status, 'completed'), paar2_origin, paar2_verified, TRUE, updated_at
+FROM matrix_records WHERE paar2_xor_count IS NOT NULL OR paar2_status IS NOT NULL;
+
+INSERT INTO algorithm_runs (matrix_id, algorithm, parameters, depth_limit, transpose, xor_count, program,
+                            seed, solver_version, status, origin, verified, best, created_at)
+SELECT id, 'slp', '{}', 0, slp_origin IS NOT NULL, slp_xor_count, slp_program,
+       slp_seed, 'legacy', COALESCE(slp_status, 'completed'), slp_origin, slp_verified, TRUE, updated_at
+FROM matrix_records WHERE slp_xor_count IS NOT NULL OR slp_status IS NOT NULL;
+
+INSERT INTO algorithm_runs (matrix_id, algorithm, parameters, depth_limit, xor_count, depth, program, seed,
+                            solver_version, status, created_at)
+SELECT matrix_id, algorithm, '{}', depth_limit, xor_count, depth, program, seed,
+       'legacy', COALESCE(status, 'completed'), updated_at
+FROM depth_results;
+
+-- The matrix's best result is its earliest non-aborted run with the fewest XORs
+UPDATE matrix_records m
+SET best_run_id = (
+	SELECT id FROM algorithm_runs r
+	WHERE r.matrix_id = m.id AND r.status <> 'aborted' AND r.xor_count IS NOT NULL
+	ORDER BY r.xor_count, r.id
+	LIMIT 1
+);
+
+DROP TABLE depth_results;
+ALTER TABLE matrix_records
+	DROP COLUMN smallest_xor,
+	DROP COLUMN boyar_xor_count,
+	DROP COLUMN boyar_depth,
+	DROP COLUMN boyar_program,
+	DROP COLUMN boyar_seed,
+	DROP COLUMN boyar_status,
+	DROP COLUMN boyar_origin,
+	DROP COLUMN boyar_verified,
+	DROP COLUMN paar_xor_count,
+	DROP COLUMN paar_program,
+	DROP COLUMN paar_status,
+	DROP COLUMN paar_origin,
+	DROP COLUMN paar_verified,
+	DROP COLUMN paar2_xor_count,
+	DROP COLUMN paar2_program,
+	DROP COLUMN paar2_status,
+	DROP COLUMN paar2_origin,
+	DROP COLUMN paar2_verified,
+	DROP COLUMN slp_xor_count,
+	DROP COLUMN slp_program,
+	DROP COLUMN slp_seed,
+	DROP COLUMN slp_status,
+	DROP COLUMN slp_origin,
+	DROP COLUMN slp_verified;
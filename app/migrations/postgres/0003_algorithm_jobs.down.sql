@@ -0,0 +1,2 @@
+-- Queued and dead algorithm jobs are lost
+DROP TABLE IF EXISTS algorithm_jobs;
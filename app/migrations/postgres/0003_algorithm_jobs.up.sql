@@ -0,0 +1,27 @@
+-- Durable queue of solver runs consumed by the algorithm worker pool. A worker
+-- leases a queued row and renews lease_expires_at while it runs; rows whose
+-- lease expired are queued again. Queue times are written by the application
+-- in UTC and only compared with each other.
+CREATE TABLE IF NOT EXISTS algorithm_jobs (
+	id SERIAL PRIMARY KEY,
+	matrix_id INTEGER NOT NULL REFERENCES matrix_records(id) ON DELETE CASCADE,
+	source VARCHAR(16) NOT NULL,
+	algorithms TEXT,
+	params TEXT,
+	reuse_equivalents BOOLEAN NOT NULL DEFAULT FALSE,
+	timeout_ms BIGINT NOT NULL DEFAULT 0,
+	priority INTEGER NOT NULL DEFAULT 0,
+	status VARCHAR(16) NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	max_attempts INTEGER NOT NULL,
+	run_after TIMESTAMP NOT NULL,
+	lease_owner VARCHAR(255),
+	lease_expires_at TIMESTAMP,
+	heartbeat_at TIMESTAMP,
+	last_error TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	finished_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_algorithm_jobs_ready ON algorithm_jobs(status, priority, run_after);
+CREATE INDEX IF NOT EXISTS idx_algorithm_jobs_matrix ON algorithm_jobs(matrix_id);
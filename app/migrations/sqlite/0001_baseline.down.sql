@@ -0,0 +1,5 @@
+-- Removes the whole schema, including every stored matrix
+DROP TABLE IF EXISTS generator_jobs;
+DROP TABLE IF EXISTS pareto_points;
+DROP TABLE IF EXISTS depth_results;
+DROP TABLE IF EXISTS matrix_records;
@@ -0,0 +1,135 @@
+-- SQLite version of the PostgreSQL baseline. SQLite databases start at this
+-- schema, so there are no columns to add for earlier releases.
+
+CREATE TABLE IF NOT EXISTS matrix_records (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title VARCHAR(255) NOT NULL,
+	group_name VARCHAR(255),
+	matrix_binary TEXT NOT NULL,
+	matrix_hex TEXT NOT NULL,
+	ham_xor_count INTEGER NOT NULL,
+	smallest_xor INTEGER,
+	boyar_xor_count INTEGER,
+	boyar_depth INTEGER,
+	boyar_program TEXT,
+	boyar_seed BIGINT,
+	boyar_status VARCHAR(16),
+	boyar_origin VARCHAR(16),
+	boyar_verified BOOLEAN,
+	paar_xor_count INTEGER,
+	paar_program TEXT,
+	paar_status VARCHAR(16),
+	paar_origin VARCHAR(16),
+	paar_verified BOOLEAN,
+	paar2_xor_count INTEGER,
+	paar2_program TEXT,
+	paar2_status VARCHAR(16),
+	paar2_origin VARCHAR(16),
+	paar2_verified BOOLEAN,
+	slp_xor_count INTEGER,
+	slp_program TEXT,
+	slp_seed BIGINT,
+	slp_status VARCHAR(16),
+	slp_origin VARCHAR(16),
+	slp_verified BOOLEAN,
+	matrix_hash VARCHAR(32) NOT NULL UNIQUE,
+	inverse_matrix_id INTEGER,
+	inverse_matrix_hash VARCHAR(32),
+	field_matrix TEXT,
+	field_polynomial VARCHAR(64),
+	field_degree INTEGER,
+	differential_branch INTEGER,
+	linear_branch INTEGER,
+	is_mds BOOLEAN,
+	is_near_mds BOOLEAN,
+	is_involutory BOOLEAN,
+	is_semi_involutory BOOLEAN,
+	is_orthogonal BOOLEAN,
+	is_circulant BOOLEAN,
+	analyzed_at TIMESTAMP,
+	canonical_hash VARCHAR(32),
+	optimal_xor INTEGER,
+	xor_lower_bound INTEGER,
+	sxor_count INTEGER,
+	sxor_program TEXT,
+	sxor_source VARCHAR(32),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_matrix_records_hash ON matrix_records(matrix_hash);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_canonical_hash ON matrix_records(canonical_hash);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_title ON matrix_records(title);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_title_lower ON matrix_records(LOWER(title));
+CREATE INDEX IF NOT EXISTS idx_matrix_records_group ON matrix_records(group_name);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_ham_xor ON matrix_records(ham_xor_count);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_smallest_xor ON matrix_records(smallest_xor);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_boyar_xor ON matrix_records(boyar_xor_count);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_paar_xor ON matrix_records(paar_xor_count);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_paar2_xor ON matrix_records(paar2_xor_count);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_slp_xor ON matrix_records(slp_xor_count);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_inverse_id ON matrix_records(inverse_matrix_id);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_inverse_hash ON matrix_records(inverse_matrix_hash);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_field ON matrix_records(field_degree, field_polynomial);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_mds ON matrix_records(is_mds, differential_branch);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_created_at ON matrix_records(created_at);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_smallest_xor_created_at ON matrix_records(smallest_xor ASC, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_matrix_records_ham_xor_created_at ON matrix_records(ham_xor_count ASC, created_at DESC);
+
+-- Keep updated_at current on every update that does not set it itself
+CREATE TRIGGER IF NOT EXISTS update_matrix_records_updated_at
+	AFTER UPDATE ON matrix_records
+	FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+	UPDATE matrix_records SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Best result of solvers with depth per depth limit
+CREATE TABLE IF NOT EXISTS depth_results (
+	matrix_id INTEGER NOT NULL REFERENCES matrix_records(id) ON DELETE CASCADE,
+	algorithm VARCHAR(32) NOT NULL,
+	depth_limit INTEGER NOT NULL,
+	xor_count INTEGER NOT NULL,
+	depth INTEGER NOT NULL,
+	program TEXT,
+	seed BIGINT,
+	status VARCHAR(16),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (matrix_id, algorithm, depth_limit)
+);
+
+-- Non-dominated (depth, XOR) pairs found by depth sweeps
+CREATE TABLE IF NOT EXISTS pareto_points (
+	matrix_id INTEGER NOT NULL REFERENCES matrix_records(id) ON DELETE CASCADE,
+	depth INTEGER NOT NULL,
+	xor_count INTEGER NOT NULL,
+	depth_limit INTEGER NOT NULL,
+	program TEXT,
+	seed BIGINT,
+	status VARCHAR(16),
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (matrix_id, depth)
+);
+
+-- Resumable enumerations of MDS candidate constructions
+CREATE TABLE IF NOT EXISTS generator_jobs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	family VARCHAR(32) NOT NULL,
+	field_polynomial VARCHAR(64) NOT NULL,
+	field_degree INTEGER NOT NULL,
+	dimension INTEGER NOT NULL,
+	algorithms TEXT,
+	max_results INTEGER NOT NULL,
+	next_candidate TEXT,
+	examined BIGINT NOT NULL DEFAULT 0,
+	candidates_total DOUBLE PRECISION NOT NULL DEFAULT 0,
+	found INTEGER NOT NULL DEFAULT 0,
+	inserted INTEGER NOT NULL DEFAULT 0,
+	duplicates INTEGER NOT NULL DEFAULT 0,
+	status VARCHAR(16) NOT NULL,
+	error TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_generator_jobs_status ON generator_jobs(status);
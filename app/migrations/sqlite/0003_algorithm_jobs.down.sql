@@ -0,0 +1,4 @@
+-- SQLite version of the PostgreSQL migration.
+
+-- Queued and dead algorithm jobs are lost
+DROP TABLE IF EXISTS algorithm_jobs;
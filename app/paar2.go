@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"log"
+)
+
+// defaultPaar2MaxNodes bounds the Paar2 search when no budget is requested
+const defaultPaar2MaxNodes = 100000
+
+// Paar2Algorithm implements Paar's second algorithm. Like Paar1 it is
+// cancellation-free, but instead of always taking the first pair of columns
+// with the largest shared Hamming weight it recursively tries every tied pair
+// and keeps the shortest program found (paar_aes_alg2 in the reference code).
+type Paar2Algorithm struct {
+	PaarAlgorithm
+	MaxNodes int // Search budget: maximum number of visited search nodes
+
+	ctx         context.Context
+	nodes       int
+	exhausted   bool
+	bestXor     int
+	bestPairs   [][2]int
+	bestColumns []BitVector
+}
+
+func NewPaar2Algorithm(maxNodes int) *Paar2Algorithm {
+	if maxNodes <= 0 {
+		maxNodes = defaultPaar2MaxNodes
+	}
+	return &Paar2Algorithm{MaxNodes: maxNodes}
+}
+
+// Name returns the registry name of the solver
+func (p *Paar2Algorithm) Name() string {
+	return "paar2"
+}
+
+// Parameters returns the effective solver parameters
+func (p *Paar2Algorithm) Parameters() SolverParams {
+	return SolverParams{"max_nodes": p.MaxNodes}
+}
+
+func (p *Paar2Algorithm) Solve(ctx context.Context, matrix Matrix) (AlgResult, error) {
+	err := p.ReadTargetMatrix(matrix)
+	if err != nil {
+		return AlgResult{}, err
+	}
+
+	err = p.InitBase()
+	if err != nil {
+		return AlgResult{}, err
+	}
+
+	p.ctx = ctx
+	p.nodes = 0
+	p.exhausted = false
+	p.bestXor = p.naiveXorCount()
+	p.bestPairs = nil
+	p.bestColumns = p.Columns
+
+	columns := make([]BitVector, p.NumInputs)
+	copy(columns, p.Columns)
+	p.search(columns, p.bestXor, nil)
+
+	// Any program found so far is complete, so a budget stop returns the best one
+	status := StatusCompleted
+	if err := ctx.Err(); err != nil {
+		if !deadlineExceeded(err) {
+			return AlgResult{}, err
+		}
+		status = StatusTimedOut
+		log.Printf("[PAAR2] Süre doldu (%d düğüm), bulunan en iyi sonuç döndürülüyor: %d XOR", p.nodes, p.bestXor)
+	} else if p.exhausted {
+		status = StatusTimedOut
+		log.Printf("[PAAR2] Arama bütçesi (%d düğüm) doldu, bulunan en iyi sonuç döndürülüyor: %d XOR", p.MaxNodes, p.bestXor)
+	}
+
+	return AlgResult{
+		XorCount: p.bestXor,
+		Program:  p.buildProgram(p.bestPairs, p.bestColumns),
+		Status:   status,
+	}, nil
+}
+
+// search expands one node of the Paar2 search tree. columns is owned by the
+// caller and never modified; every branch works on its own copy.
+func (p *Paar2Algorithm) search(columns []BitVector, xorCount int, pairs [][2]int) {
+	if p.nodes >= p.MaxNodes {
+		p.exhausted = true
+		return
+	}
+	if p.ctx.Err() != nil {
+		return
+	}
+	p.nodes++
+
+	// Collect every pair with the maximal shared weight
+	hwMax := 0
+	var ties [][2]int
+	for i := 0; i < len(columns); i++ {
+		for j := i + 1; j < len(columns); j++ {
+			hw := columns[i].AndPopCount(columns[j])
+			if hw > hwMax {
+				hwMax = hw
+				ties = append(ties[:0], [2]int{i, j})
+			} else if hw == hwMax && hw > 1 {
+				ties = append(ties, [2]int{i, j})
+			}
+		}
+	}
+
+	// Leaf: no pair shares more than one row, the program is complete
+	if hwMax <= 1 {
+		if xorCount < p.bestXor || p.bestPairs == nil {
+			p.bestXor = xorCount
+			p.bestPairs = append([][2]int(nil), pairs...)
+			p.bestColumns = append([]BitVector(nil), columns...)
+		}
+		return
+	}
+
+	for _, tie := range ties {
+		newColumn := columns[tie[0]].And(columns[tie[1]])
+
+		next := make([]BitVector, len(columns), len(columns)+1)
+		copy(next, columns)
+		next[tie[0]] = columns[tie[0]].AndNot(newColumn)
+		next[tie[1]] = columns[tie[1]].AndNot(newColumn)
+		next = append(next, newColumn)
+
+		nextPairs := append(append([][2]int(nil), pairs...), tie)
+		p.search(next, xorCount-(hwMax-1), nextPairs)
+
+		if p.exhausted || p.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func init() {
+	RegisterSolver(SolverInfo{
+		Name:      "paar2",
+		Label:     "PAAR2",
+		Default:   false, // Backtracking search, opt-in through algorithms or import.algorithms
+		Persisted: true,
+		Factory: func(params SolverParams) (Solver, error) {
+			return NewPaar2Algorithm(params.Int("max_nodes", defaultPaar2MaxNodes)), nil
+		},
+	})
+}
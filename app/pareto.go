@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// paretoAlgorithm is the solver the depth sweep runs
+const paretoAlgorithm = "boyar"
+
+// ParetoPoint is a (depth, XOR) trade-off no other stored result of the
+// matrix beats in both depth and XOR count
+type ParetoPoint struct {
+	Depth      int       `json:"depth"`
+	XorCount   int       `json:"xor_count"`
+	DepthLimit int       `json:"depth_limit"` // Depth limit of the run that found the program
+	Program    *Program  `json:"program,omitempty"`
+	Seed       *int64    `json:"seed,omitempty"`
+	Status     string    `json:"status"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ParetoFront is the response of GET /api/matrices/{id}/pareto
+type ParetoFront struct {
+	MatrixID int            `json:"matrix_id"`
+	MinDepth int            `json:"min_depth"` // Smallest depth any program for the matrix can have
+	Running  bool           `json:"running"`   // Whether a depth sweep is queued or in progress
+	Points   []*ParetoPoint `json:"points"`    // Ordered by increasing depth (and decreasing XOR count)
+}
+
+// paretoFront returns the non-dominated (depth, XOR) pairs among results,
+// ordered by depth. Of equal pairs the one with the smallest depth limit is kept.
+func paretoFront(results []*DepthResult) []*ParetoPoint {
+	sorted := make([]*DepthResult, 0, len(results))
+	for _, result := range results {
+		if result.Program != nil {
+			sorted = append(sorted, result)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Depth != b.Depth {
+			return a.Depth < b.Depth
+		}
+		if a.XorCount != b.XorCount {
+			return a.XorCount < b.XorCount
+		}
+		return a.DepthLimit < b.DepthLimit
+	})
+
+	points := []*ParetoPoint{}
+	for _, result := range sorted {
+		// Every kept point has less depth, so only fewer XORs can make this one non-dominated
+		if len(points) > 0 && result.XorCount >= points[len(points)-1].XorCount {
+			continue
+		}
+		points = append(points, &ParetoPoint{
+			Depth:      result.Depth,
+			XorCount:   result.XorCount,
+			DepthLimit: result.DepthLimit,
+			Program:    result.Program,
+			Seed:       result.Seed,
+			Status:     result.Status,
+			UpdatedAt:  result.UpdatedAt,
+		})
+	}
+	return points
+}
+
+// depthSweepLimits returns the depth limits a sweep runs after the unbounded
+// run: from the minimum depth of rows up to the depth the unbounded run
+// reached. Larger limits do not constrain the search any further in practice.
+func depthSweepLimits(rows []BitVector, unboundedDepth int) []int {
+	first := minimumDepth(rows)
+	if first < 1 {
+		first = 1
+	}
+	var limits []int
+	for limit := first; limit <= unboundedDepth && limit < maxBoyarDepthLimit; limit++ {
+		limits = append(limits, limit)
+	}
+	return limits
+}
+
+// runDepthSweep runs BoyarSLP on matrix without a depth bound (the largest
+// supported limit) and then for every limit returned by depthSweepLimits.
+// params are passed to every run except depth_limit; each run gets its own
+// solverTimeout deadline. save is called after every successful run.
+func runDepthSweep(ctx context.Context, matrix Matrix, params SolverParams, save func(*AlgResult) error) error {
+	rows, _, err := parseBinaryRows(matrix)
+	if err != nil {
+		return err
+	}
+
+	run := func(limit int) (*AlgResult, error) {
+		solverCtx, cancel := withSolverDeadline(ctx, 0)
+		defer cancel()
+		result, err := runSolver(solverCtx, paretoAlgorithm, sweepParams(params, limit), matrix)
+		if err != nil {
+			return nil, err
+		}
+		return result, save(result)
+	}
+
+	unbounded, err := run(maxBoyarDepthLimit)
+	if err != nil {
+		return fmt.Errorf("sınırsız derinlik çalışması başarısız: %v", err)
+	}
+
+	for _, limit := range depthSweepLimits(rows, unbounded.Depth) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result, err := run(limit)
+		if err != nil {
+			return fmt.Errorf("derinlik sınırı %d çalışması başarısız: %v", limit, err)
+		}
+		log.Printf("[PARETO] Derinlik sınırı %d: XOR %d, derinlik %d (%s)", limit, result.XorCount, result.Depth, result.Status)
+	}
+	return nil
+}
+
+// sweepParams returns params with the depth limit of one sweep run
+func sweepParams(params SolverParams, limit int) SolverParams {
+	runParams := SolverParams{}
+	for key, value := range params {
+		runParams[key] = value
+	}
+	runParams["depth_limit"] = limit
+	return runParams
+}
+
+// errDepthSweepRunning rejects a second sweep of a matrix
+var errDepthSweepRunning = errors.New("bu matris için derinlik taraması zaten çalışıyor")
+
+// maxMatrixJobs bounds the jobs of one matrix and status depthSweepRunning looks at
+const maxMatrixJobs = 1000
+
+// depthSweepsMu serializes the check and the enqueue of StartDepthSweep
+var depthSweepsMu sync.Mutex
+
+// depthSweepRunning reports whether a depth sweep of the matrix is queued or
+// running
+func depthSweepRunning(store MatrixStore, matrixID int) (bool, error) {
+	for _, status := range []string{AlgorithmJobQueued, AlgorithmJobRunning} {
+		jobs, err := store.GetAlgorithmJobs(status, matrixID, maxMatrixJobs)
+		if err != nil {
+			return false, err
+		}
+		for _, job := range jobs {
+			if job.Source == JobSourceDepthSweep {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// StartDepthSweep queues a depth sweep of record for the algorithm workers.
+// Every run is stored in algorithm_runs, where a sweep never replaces the
+// result of a regular run, and the Pareto front is refreshed after each of
+// them, so GET /pareto shows the progress. At most one sweep per matrix is
+// queued or running.
+func StartDepthSweep(store MatrixStore, record *MatrixRecord, params SolverParams) (*AlgorithmJob, error) {
+	depthSweepsMu.Lock()
+	defer depthSweepsMu.Unlock()
+	running, err := depthSweepRunning(store, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	if running {
+		return nil, errDepthSweepRunning
+	}
+
+	job := &AlgorithmJob{
+		MatrixID:   record.ID,
+		Source:     JobSourceDepthSweep,
+		Algorithms: []string{paretoAlgorithm},
+		Params:     map[string]SolverParams{paretoAlgorithm: params},
+		Priority:   jobPriorityUser,
+	}
+	if err := EnqueueAlgorithmJob(store, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Program is the typed gate list every solver produces. Signals are numbered
+// like the solvers' base elements: 0..NumInputs-1 are the inputs x0, x1, ...
+// and signal NumInputs+k is the output of gate k.
+type Program struct {
+	NumInputs int    `json:"num_inputs"`
+	Gates     []Gate `json:"gates"`
+	Outputs   []int  `json:"outputs"` // Signal computing matrix row i, -1 for a zero row
+}
+
+// Gate XORs two earlier signals
+type Gate struct {
+	A     int `json:"a"`
+	B     int `json:"b"`
+	Depth int `json:"depth"` // Longest path from the inputs, inputs have depth 0
+}
+
+// NewProgram returns an empty program over numInputs inputs with every output unassigned
+func NewProgram(numInputs, numOutputs int) *Program {
+	outputs := make([]int, numOutputs)
+	for i := range outputs {
+		outputs[i] = -1
+	}
+	return &Program{NumInputs: numInputs, Outputs: outputs}
+}
+
+// NumSignals returns the number of inputs and gates
+func (p *Program) NumSignals() int {
+	return p.NumInputs + len(p.Gates)
+}
+
+// SignalDepth returns the depth of signal s
+func (p *Program) SignalDepth(s int) int {
+	if s < p.NumInputs {
+		return 0
+	}
+	return p.Gates[s-p.NumInputs].Depth
+}
+
+// SignalName returns the name of signal s in the text form ("x3", "t7")
+func (p *Program) SignalName(s int) string {
+	if s < p.NumInputs {
+		return fmt.Sprintf("x%d", s)
+	}
+	return fmt.Sprintf("t%d", s-p.NumInputs)
+}
+
+// AddGate appends the gate a ^ b and returns its signal
+func (p *Program) AddGate(a, b int) int {
+	depth := p.SignalDepth(a)
+	if d := p.SignalDepth(b); d > depth {
+		depth = d
+	}
+	p.Gates = append(p.Gates, Gate{A: a, B: b, Depth: depth + 1})
+	return p.NumSignals() - 1
+}
+
+// AddXorTree XORs the signals with as little depth as possible, always
+// combining the two shallowest operands first. It returns the resulting
+// signal, or -1 if signals is empty.
+func (p *Program) AddXorTree(signals []int) int {
+	if len(signals) == 0 {
+		return -1
+	}
+	queue := append([]int(nil), signals...)
+	for len(queue) > 1 {
+		first, second := 0, 1
+		if p.SignalDepth(queue[second]) < p.SignalDepth(queue[first]) {
+			first, second = second, first
+		}
+		for i := 2; i < len(queue); i++ {
+			d := p.SignalDepth(queue[i])
+			if d < p.SignalDepth(queue[first]) {
+				first, second = i, first
+			} else if d < p.SignalDepth(queue[second]) {
+				second = i
+			}
+		}
+		if first > second {
+			first, second = second, first
+		}
+		merged := p.AddGate(queue[first], queue[second])
+
+		next := make([]int, 0, len(queue)-1)
+		for i, s := range queue {
+			if i != first && i != second {
+				next = append(next, s)
+			}
+		}
+		queue = append(next, merged)
+	}
+	return queue[0]
+}
+
+// XorCount returns the number of XOR gates
+func (p *Program) XorCount() int {
+	return len(p.Gates)
+}
+
+// Depth returns the largest gate depth
+func (p *Program) Depth() int {
+	depth := 0
+	for _, gate := range p.Gates {
+		if gate.Depth > depth {
+			depth = gate.Depth
+		}
+	}
+	return depth
+}
+
+// Evaluate returns the value of every signal as a combination of the inputs
+func (p *Program) Evaluate() []BitVector {
+	values := make([]BitVector, p.NumSignals())
+	for i := 0; i < p.NumInputs; i++ {
+		values[i] = UnitBitVector(p.NumInputs, i)
+	}
+	for k, gate := range p.Gates {
+		values[p.NumInputs+k] = values[gate.A].Xor(values[gate.B])
+	}
+	return values
+}
+
+// ResolveOutputs assigns every unassigned non-zero target to the first signal
+// that computes it; the solvers call it once their base covers all targets
+func (p *Program) ResolveOutputs(targets []BitVector) {
+	values := p.Evaluate()
+	for i, target := range targets {
+		if p.Outputs[i] >= 0 || target.IsZero() {
+			continue
+		}
+		for s, value := range values {
+			if value.Equal(target) {
+				p.Outputs[i] = s
+				break
+			}
+		}
+	}
+}
+
+// Lines returns the text form of the program: one "t<k> = a + b" line per
+// gate followed by one "y<i> = s" line per non-zero output
+func (p *Program) Lines() []string {
+	lines := make([]string, 0, len(p.Gates)+len(p.Outputs))
+	for k, gate := range p.Gates {
+		lines = append(lines, fmt.Sprintf("t%d = %s + %s", k, p.SignalName(gate.A), p.SignalName(gate.B)))
+	}
+	for i, s := range p.Outputs {
+		if s >= 0 {
+			lines = append(lines, fmt.Sprintf("y%d = %s", i, p.SignalName(s)))
+		}
+	}
+	return lines
+}
+
+// MarshalJSON adds the derived text form to the structured program
+func (p *Program) MarshalJSON() ([]byte, error) {
+	type program Program
+	return json.Marshal(struct {
+		*program
+		Lines []string `json:"lines"`
+	}{(*program)(p), p.Lines()})
+}
+
+// decodeStoredProgram reads a stored program column. Rows written before the
+// structured model hold the solver's text lines and are converted on read.
+func decodeStoredProgram(text, matrixBinary string) (*Program, error) {
+	if strings.HasPrefix(strings.TrimSpace(text), "[") {
+		var lines []string
+		if err := json.Unmarshal([]byte(text), &lines); err != nil {
+			return nil, err
+		}
+		matrix, err := parseMatrixFromBinary(matrixBinary)
+		if err != nil {
+			return nil, err
+		}
+		return programFromLines(matrix, lines)
+	}
+
+	var program Program
+	if err := json.Unmarshal([]byte(text), &program); err != nil {
+		return nil, err
+	}
+	return &program, nil
+}
+
+// programFromLines converts the text programs of the solvers into a Program:
+//
+//	t3 = x1 + t2 (2)        Boyar gate with depth annotation
+//	t5 = x0 + t4 * y7 (3)   Boyar gate that computes output y7
+//	y0 = x3                 output that is already a signal
+//	y4 = t0 + x5            SLP output gate
+//	x16 = x3 + x9           Paar column
+//	y0 = x16 + x2 + x7      Paar multi-term output
+//
+// Multi-term lines become balanced XOR trees. Rows without an output line are
+// assigned to the first signal that computes them.
+func programFromLines(matrix Matrix, lines []string) (*Program, error) {
+	rows, numInputs, err := parseBinaryRows(matrix)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewProgram(numInputs, len(rows))
+	names := make(map[string]int)
+	for j := 0; j < numInputs; j++ {
+		names[fmt.Sprintf("x%d", j)] = j
+	}
+
+	for n, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		// Boyar annotates the gate depth as a trailing "(d)"
+		if i := strings.LastIndex(line, "("); i >= 0 && strings.HasSuffix(line, ")") {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("satır %d çözümlenemedi: %q", n+1, raw)
+		}
+		lhs := strings.TrimSpace(parts[0])
+		rhs := parts[1]
+
+		// Boyar marks the output a gate computes with "* y<k>"
+		marker := -1
+		if i := strings.Index(rhs, "*"); i >= 0 {
+			index, ok := parseOutputName(strings.TrimSpace(rhs[i+1:]))
+			if !ok {
+				return nil, fmt.Errorf("satır %d: geçersiz çıkış işareti: %q", n+1, raw)
+			}
+			marker = index
+			rhs = rhs[:i]
+		}
+
+		if lhs == "" {
+			return nil, fmt.Errorf("satır %d: sol taraf boş: %q", n+1, raw)
+		}
+		if _, exists := names[lhs]; exists {
+			return nil, fmt.Errorf("satır %d: %s tekrar tanımlandı", n+1, lhs)
+		}
+
+		var operands []int
+		for _, term := range strings.Split(rhs, "+") {
+			term = strings.TrimSpace(term)
+			s, ok := names[term]
+			if !ok {
+				return nil, fmt.Errorf("satır %d: tanımsız değişken %q", n+1, term)
+			}
+			operands = append(operands, s)
+		}
+
+		signal := p.AddXorTree(operands)
+		names[lhs] = signal
+
+		for _, index := range []int{outputIndex(lhs), marker} {
+			if index < 0 {
+				continue
+			}
+			if index >= len(p.Outputs) {
+				return nil, fmt.Errorf("satır %d: y%d matriste yok (%d satır)", n+1, index, len(rows))
+			}
+			p.Outputs[index] = signal
+		}
+	}
+
+	p.ResolveOutputs(rows)
+	return p, nil
+}
+
+// parseOutputName returns k for an output name "y<k>"
+func parseOutputName(name string) (int, bool) {
+	if len(name) < 2 || name[0] != 'y' {
+		return 0, false
+	}
+	index, err := strconv.Atoi(name[1:])
+	if err != nil || index < 0 {
+		return 0, false
+	}
+	return index, true
+}
+
+// outputIndex returns k for an output name "y<k>" and -1 for other names
+func outputIndex(name string) int {
+	if index, ok := parseOutputName(name); ok {
+		return index
+	}
+	return -1
+}
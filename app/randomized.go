@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// Defaults of the randomized multi-start mode
+const (
+	defaultRandomIterations = 50
+
+	// Generated seeds stay below 2^53 so they survive JSON round trips through float64
+	maxGeneratedSeed = 1 << 53
+)
+
+// RandomizedSearch configures the randomized multi-start mode of the
+// Boyar–Peralta style solvers. Every start breaks ties in EasyMove and
+// PickNewBaseElement at random and the shortest program over all starts is
+// kept. Start i is seeded with Seed+i, so the winning program can be
+// reproduced by a single start with the seed stored in the result. The
+// starts share the deadline of the run, e.g. the "time_budget_ms" applied by
+// runSolver.
+type RandomizedSearch struct {
+	Seed       int64 // Seed of the first start
+	Iterations int   // Maximum number of starts
+}
+
+// randomizedSearchFromParams returns the randomized search requested by the
+// parameters, or nil if the deterministic solver should run. The mode is
+// enabled by "randomized": true or by passing a "seed" or "iterations".
+func randomizedSearchFromParams(params SolverParams) *RandomizedSearch {
+	if !params.Bool("randomized", false) && !params.Has("seed") && !params.Has("iterations") {
+		return nil
+	}
+
+	search := &RandomizedSearch{
+		Seed:       params.Int64("seed", time.Now().UnixNano()%maxGeneratedSeed),
+		Iterations: params.Int("iterations", defaultRandomIterations),
+	}
+	if search.Iterations <= 0 {
+		search.Iterations = 1
+	}
+	return search
+}
+
+// addParameters adds the randomized search settings to the solver parameters
+func (r *RandomizedSearch) addParameters(params SolverParams) SolverParams {
+	if r == nil {
+		return params
+	}
+	params["randomized"] = true
+	params["seed"] = r.Seed
+	params["iterations"] = r.Iterations
+	return params
+}
+
+// run executes the starts and returns the best result. start solves the
+// matrix once with the given random source; the first start always runs,
+// later ones only until the deadline of ctx. If the deadline ends the search
+// early the result is marked StatusTimedOut. Starts aborted by max_gates are
+// skipped; if every start was aborted the smallest aborted result is returned.
+func (r *RandomizedSearch) run(ctx context.Context, tag string, start func(rng *rand.Rand) (AlgResult, error)) (AlgResult, error) {
+	var best, aborted *AlgResult
+	var lastErr error
+	starts := 0
+	timedOut := false
+
+	for i := 0; i < r.Iterations; i++ {
+		if err := ctx.Err(); err != nil && i > 0 {
+			if !deadlineExceeded(err) {
+				return AlgResult{}, err
+			}
+			timedOut = true
+			break
+		}
+
+		seed := r.Seed + int64(i)
+		result, err := start(rand.New(rand.NewSource(seed)))
+		starts++
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if result.Status == StatusAborted {
+			if aborted == nil || result.XorCount < aborted.XorCount {
+				result.Seed = &seed
+				aborted = &result
+			}
+			continue
+		}
+
+		// An interrupted start depends on timing and cannot be reproduced from
+		// its seed, so it only counts if nothing else was found
+		if result.Status == StatusTimedOut {
+			timedOut = true
+			if best != nil {
+				continue
+			}
+		}
+
+		if best == nil || best.Status == StatusTimedOut || result.XorCount < best.XorCount ||
+			(result.XorCount == best.XorCount && result.Depth < best.Depth) {
+			result.Seed = &seed
+			best = &result
+		}
+	}
+
+	if best == nil {
+		if aborted != nil {
+			log.Printf("[%s] Rastgele arama: %d başlangıcın hepsi kapı bütçesini aştı", tag, starts)
+			return *aborted, nil
+		}
+		return AlgResult{}, lastErr
+	}
+	if timedOut {
+		best.Status = StatusTimedOut
+	}
+
+	log.Printf("[%s] Rastgele arama tamamlandı: %d başlangıç, en iyi %d XOR (seed %d)", tag, starts, best.XorCount, *best.Seed)
+	return *best, nil
+}
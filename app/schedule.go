@@ -0,0 +1,183 @@
+package main
+
+// Register pressure of a program is counted in temporaries: gate results that
+// have to be kept until their last use. Inputs are read from memory and
+// outputs are stored as soon as they are computed, so neither occupies a
+// temporary; a gate whose result is only an output still needs one for the
+// step that computes it.
+
+// operands returns the distinct signals the gate reads
+func (g Gate) operands() []int {
+	if g.A == g.B {
+		return []int{g.A}
+	}
+	return []int{g.A, g.B}
+}
+
+// lastUses returns, for every gate signal, the index of the last gate reading
+// it, or -1 if only outputs read it
+func (p *Program) lastUses() []int {
+	last := make([]int, len(p.Gates))
+	for k := range last {
+		last[k] = -1
+	}
+	for k, gate := range p.Gates {
+		for _, s := range gate.operands() {
+			if s >= p.NumInputs {
+				last[s-p.NumInputs] = k
+			}
+		}
+	}
+	return last
+}
+
+// AllocateRegisters assigns every gate a temporary, reusing the temporaries
+// of operands that die at the gate. It returns the temporary of each gate and
+// the number of temporaries used, which is the peak number of live values.
+func (p *Program) AllocateRegisters() (registers []int, count int) {
+	last := p.lastUses()
+	registers = make([]int, len(p.Gates))
+	var free []int
+
+	release := func(k int) {
+		// Keep the free list sorted so the lowest temporary is reused first
+		r := registers[k]
+		i := len(free)
+		free = append(free, r)
+		for i > 0 && free[i-1] > r {
+			free[i] = free[i-1]
+			i--
+		}
+		free[i] = r
+	}
+
+	for k, gate := range p.Gates {
+		for _, s := range gate.operands() {
+			if s >= p.NumInputs && last[s-p.NumInputs] == k {
+				release(s - p.NumInputs)
+			}
+		}
+		if len(free) > 0 {
+			registers[k] = free[0]
+			free = free[1:]
+		} else {
+			registers[k] = count
+			count++
+		}
+		if last[k] < 0 {
+			release(k)
+		}
+	}
+	return registers, count
+}
+
+// PeakLive returns the largest number of temporaries live at once when the
+// gates run in program order
+func (p *Program) PeakLive() int {
+	_, count := p.AllocateRegisters()
+	return count
+}
+
+// Schedule returns an equivalent program whose gate order keeps as few
+// temporaries live as possible. Ready gates are picked greedily by the change
+// in live temporaries they cause (operands they kill against the result they
+// keep), falling back to the original order on ties. The original program is
+// returned if the greedy order is not better.
+func (p *Program) Schedule() *Program {
+	numGates := len(p.Gates)
+	if numGates < 2 {
+		return p
+	}
+
+	remaining := make([]int, numGates) // Unscheduled gate reads of each gate result
+	pending := make([]int, numGates)   // Unscheduled gate operands of each gate
+	readers := make([][]int, numGates)
+	for k, gate := range p.Gates {
+		for _, s := range gate.operands() {
+			if s >= p.NumInputs {
+				remaining[s-p.NumInputs]++
+				pending[k]++
+				readers[s-p.NumInputs] = append(readers[s-p.NumInputs], k)
+			}
+		}
+	}
+
+	var ready []int
+	for k := range p.Gates {
+		if pending[k] == 0 {
+			ready = append(ready, k)
+		}
+	}
+
+	delta := func(k int) int {
+		gate := p.Gates[k]
+		change := 0
+		if remaining[k] > 0 {
+			change++
+		}
+		for _, s := range gate.operands() {
+			if s >= p.NumInputs && remaining[s-p.NumInputs] == 1 {
+				change--
+			}
+		}
+		return change
+	}
+
+	order := make([]int, 0, numGates)
+	for len(ready) > 0 {
+		best := 0
+		bestDelta := delta(ready[0])
+		for i := 1; i < len(ready); i++ {
+			d := delta(ready[i])
+			if d < bestDelta || (d == bestDelta && ready[i] < ready[best]) {
+				best, bestDelta = i, d
+			}
+		}
+		k := ready[best]
+		ready = append(ready[:best], ready[best+1:]...)
+		order = append(order, k)
+
+		gate := p.Gates[k]
+		for _, s := range gate.operands() {
+			if s >= p.NumInputs {
+				remaining[s-p.NumInputs]--
+			}
+		}
+		for _, reader := range readers[k] {
+			pending[reader]--
+			if pending[reader] == 0 {
+				ready = append(ready, reader)
+			}
+		}
+	}
+
+	scheduled := p.reorder(order)
+	if scheduled.PeakLive() >= p.PeakLive() {
+		return p
+	}
+	return scheduled
+}
+
+// reorder returns the program with its gates in the given topological order
+func (p *Program) reorder(order []int) *Program {
+	signal := make([]int, p.NumSignals())
+	for i := 0; i < p.NumInputs; i++ {
+		signal[i] = i
+	}
+	for position, k := range order {
+		signal[p.NumInputs+k] = p.NumInputs + position
+	}
+
+	q := &Program{NumInputs: p.NumInputs, Gates: make([]Gate, len(order)), Outputs: make([]int, len(p.Outputs))}
+	for position, k := range order {
+		gate := p.Gates[k]
+		q.Gates[position] = Gate{A: signal[gate.A], B: signal[gate.B], Depth: gate.Depth}
+	}
+	for i, s := range p.Outputs {
+		q.Outputs[i] = s
+		if s >= 0 {
+			q.Outputs[i] = signal[s]
+		}
+	}
+	return q
+}
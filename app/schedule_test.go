@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// wideProgram computes y0 = x0 + ... + x7 by first XOR-ing all four input
+// pairs, which keeps four temporaries live; pairing them as soon as possible
+// needs only three
+func wideProgram() *Program {
+	p := NewProgram(8, 1)
+	a := p.AddGate(0, 1)
+	b := p.AddGate(2, 3)
+	c := p.AddGate(4, 5)
+	d := p.AddGate(6, 7)
+	p.Outputs[0] = p.AddGate(p.AddGate(a, b), p.AddGate(c, d))
+	return p
+}
+
+func TestAllocateRegisters(t *testing.T) {
+	tests := []struct {
+		name      string
+		build     func(p *Program)
+		registers []int
+		peak      int
+	}{
+		{
+			// Every gate kills its predecessor
+			name: "chain",
+			build: func(p *Program) {
+				p.Outputs[0] = p.AddGate(p.AddGate(p.AddGate(0, 1), 2), 3)
+			},
+			registers: []int{0, 0, 0},
+			peak:      1,
+		},
+		{
+			name: "balanced",
+			build: func(p *Program) {
+				p.Outputs[0] = p.AddGate(p.AddGate(0, 1), p.AddGate(2, 3))
+			},
+			registers: []int{0, 1, 0},
+			peak:      2,
+		},
+		{
+			// A gate read only by outputs still needs a temporary while it is stored
+			name: "outputs only",
+			build: func(p *Program) {
+				p.Outputs[0] = p.AddGate(0, 1)
+				p.Outputs[1] = p.AddGate(2, 3)
+			},
+			registers: []int{0, 0},
+			peak:      1,
+		},
+		{
+			// t0 stays live until its second reader
+			name: "shared",
+			build: func(p *Program) {
+				t0 := p.AddGate(0, 1)
+				t1 := p.AddGate(t0, 2)
+				t2 := p.AddGate(t0, 3)
+				p.Outputs[0] = p.AddGate(t1, t2)
+			},
+			registers: []int{0, 1, 0, 0},
+			peak:      2,
+		},
+	}
+
+	for _, tt := range tests {
+		p := NewProgram(4, 2)
+		tt.build(p)
+		registers, count := p.AllocateRegisters()
+		if !reflect.DeepEqual(registers, tt.registers) || count != tt.peak {
+			t.Errorf("%s: kayıtlar %v (%d), beklenen %v (%d)", tt.name, registers, count, tt.registers, tt.peak)
+		}
+		if p.PeakLive() != tt.peak {
+			t.Errorf("%s: PeakLive %d, beklenen %d", tt.name, p.PeakLive(), tt.peak)
+		}
+	}
+}
+
+func TestScheduleReducesPeakLive(t *testing.T) {
+	p := wideProgram()
+	if p.PeakLive() != 4 {
+		t.Fatalf("başlangıç PeakLive %d, beklenen 4", p.PeakLive())
+	}
+
+	s := p.Schedule()
+	if s.PeakLive() != 3 {
+		t.Errorf("zamanlanmış PeakLive %d, beklenen 3 (%v)", s.PeakLive(), s.Lines())
+	}
+	if s.XorCount() != p.XorCount() || s.Depth() != p.Depth() {
+		t.Errorf("zamanlama XOR/derinliği değiştirdi: %d/%d, beklenen %d/%d", s.XorCount(), s.Depth(), p.XorCount(), p.Depth())
+	}
+	matrix := Matrix{{"1", "1", "1", "1", "1", "1", "1", "1"}}
+	if v := VerifyProgram(matrix, s); !v.Valid {
+		t.Errorf("zamanlanmış program doğrulanamadı: %v", v.Errors)
+	}
+
+	// A program that is already optimal is returned unchanged
+	chain := NewProgram(4, 1)
+	chain.Outputs[0] = chain.AddGate(chain.AddGate(chain.AddGate(0, 1), 2), 3)
+	if chain.Schedule() != chain {
+		t.Error("en iyi sıradaki program yeniden düzenlendi")
+	}
+}
+
+func TestBitslicedRegisterReuse(t *testing.T) {
+	p := wideProgram()
+	// The second output is already an input and the third is a zero row
+	p.Outputs = append(p.Outputs, 5, -1)
+	matrix := Matrix{
+		{"1", "1", "1", "1", "1", "1", "1", "1"},
+		{"0", "0", "0", "0", "0", "1", "0", "0"},
+		{"0", "0", "0", "0", "0", "0", "0", "0"},
+	}
+	record := &MatrixRecord{
+		ID:           7,
+		MatrixBinary: matrixToBinary(matrix),
+		Results:      map[string]*SolverResult{"paar": {Program: p}},
+	}
+
+	code, err := GenerateBitsliced(record, "c", 64, "best")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.PeakLive != 3 {
+		t.Errorf("PeakLive %d, beklenen 3", code.PeakLive)
+	}
+	if source := code.Files[0].Content; !strings.Contains(source, "uint64_t r0, r1, r2;") || strings.Contains(source, "r3") {
+		t.Errorf("geçici değişkenler yeniden kullanılmadı:\n%s", source)
+	}
+
+	// Run the body over random lanes; a temporary overwritten while still
+	// live would corrupt the outputs
+	unit, err := newCodegenUnit(record, "best")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unit.Program = unit.Program.Schedule()
+	body, temporaries, _ := unit.bitslicedBody()
+	if temporaries != 3 {
+		t.Errorf("%d geçici değişken, beklenen 3", temporaries)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 16; round++ {
+		words := map[string]uint64{"0": 0}
+		var want [3]uint64
+		for j := 0; j < 8; j++ {
+			x := rng.Uint64()
+			words[fmt.Sprintf("x[%d]", j)] = x
+			want[0] ^= x
+			if j == 5 {
+				want[1] = x
+			}
+		}
+		for _, st := range body {
+			value, ok := words[st.A]
+			if !ok {
+				t.Fatalf("tanımsız işlenen %q", st.A)
+			}
+			if st.B != "" {
+				operand, ok := words[st.B]
+				if !ok {
+					t.Fatalf("tanımsız işlenen %q", st.B)
+				}
+				value ^= operand
+			}
+			words[st.Dest] = value
+		}
+		for i, w := range want {
+			if got := words[fmt.Sprintf("y[%d]", i)]; got != w {
+				t.Fatalf("tur %d: y[%d] = %x, beklenen %x", round, i, got, w)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/gorilla/mux"
+)
+
+// Server serves the solver and matrix API from a MatrixStore. Handlers only
+// reach storage through store, so the API runs on any backend, including a
+// MemoryStore under httptest.
+type Server struct {
+	store MatrixStore
+}
+
+// NewServer creates a server storing into store
+func NewServer(store MatrixStore) *Server {
+	return &Server{store: store}
+}
+
+// RegisterRoutes adds the solver, matrix, job and generator endpoints to r
+func (s *Server) RegisterRoutes(r *mux.Router) {
+	// Algorithm endpoints, one per registered solver
+	for _, info := range RegisteredSolvers() {
+		r.HandleFunc("/"+info.Name, solverHandler(info)).Methods("POST", "OPTIONS")
+	}
+	r.HandleFunc("/api/algorithms", algorithmsHandler).Methods("GET")
+
+	// Database API endpoints
+	r.HandleFunc("/api/matrices", s.getMatricesHandler).Methods("GET")
+	r.HandleFunc("/api/matrices", s.saveMatrixHandler).Methods("POST")
+	r.HandleFunc("/api/matrices/{id:[0-9]+}", s.getMatrixHandler).Methods("GET")
+	r.HandleFunc("/api/matrices/{id:[0-9]+}/inverse", s.calculateInverseHandler).Methods("POST")
+	r.HandleFunc("/api/matrices/{id:[0-9]+}/verify", s.verifyMatrixHandler).Methods("POST")
+	r.HandleFunc("/api/matrices/{id:[0-9]+}/codegen", s.codegenHandler).Methods("GET")
+	r.HandleFunc("/api/matrices/{id:[0-9]+}/bitslice", s.bitsliceHandler).Methods("GET")
+	r.HandleFunc("/api/matrices/{id:[0-9]+}/pareto", s.paretoHandler).Methods("GET")
+	r.HandleFunc("/api/matrices/{id:[0-9]+}/pareto", s.depthSweepHandler).Methods("POST")
+	r.HandleFunc("/api/matrices/{id:[0-9]+}/analyze", s.analyzeMatrixHandler).Methods("POST")
+	r.HandleFunc("/api/matrices/{id:[0-9]+}/sxor", s.sxorHandler).Methods("POST")
+	r.HandleFunc("/api/matrices/{id:[0-9]+}/runs", s.algorithmRunsHandler).Methods("GET")
+	r.HandleFunc("/api/matrices/process", s.processAndSaveMatrixHandler).Methods("POST")
+	r.HandleFunc("/api/matrices/recalculate", s.recalculateHandler).Methods("POST")
+	r.HandleFunc("/api/matrices/bulk-recalculate", s.bulkRecalculateHandler).Methods("POST")
+	r.HandleFunc("/api/jobs", s.getAlgorithmJobsHandler).Methods("GET")
+	r.HandleFunc("/api/jobs/{id:[0-9]+}", s.getAlgorithmJobHandler).Methods("GET")
+	r.HandleFunc("/api/jobs/{id:[0-9]+}/retry", s.retryAlgorithmJobHandler).Methods("POST")
+	r.HandleFunc("/api/generator/families", generatorFamiliesHandler).Methods("GET")
+	r.HandleFunc("/api/generator/jobs", s.getGeneratorJobsHandler).Methods("GET")
+	r.HandleFunc("/api/generator/jobs", s.createGeneratorJobHandler).Methods("POST")
+	r.HandleFunc("/api/generator/jobs/{id:[0-9]+}", s.getGeneratorJobHandler).Methods("GET")
+	r.HandleFunc("/api/generator/jobs/{id:[0-9]+}/pause", s.pauseGeneratorJobHandler).Methods("POST")
+	r.HandleFunc("/api/generator/jobs/{id:[0-9]+}/resume", s.resumeGeneratorJobHandler).Methods("POST")
+}
@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestServer serves the API of store like main does
+func newTestServer(t *testing.T, store MatrixStore) *httptest.Server {
+	t.Helper()
+	r := mux.NewRouter()
+	NewServer(store).RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// startTestWorkers runs the algorithm worker pool on store until the test ends
+func startTestWorkers(t *testing.T, store MatrixStore) {
+	t.Helper()
+	InitAlgorithmWorkerPool(store, DefaultAlgorithms())
+	t.Cleanup(func() {
+		algorithmWorkerPool.Stop()
+		algorithmWorkerPool = nil
+	})
+}
+
+// getJSON decodes the response of a GET into v and returns its status code
+func getJSON(t *testing.T, url string, v interface{}) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK && v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("GET %s: %v", url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// postJSON posts body as JSON, decodes the response into v and returns its
+// status code
+func postJSON(t *testing.T, url string, body interface{}, v interface{}) int {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK && v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("POST %s: %v", url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// waitForJob polls GET /api/jobs/{id} until the job leaves the queue
+func waitForJob(t *testing.T, baseURL string, id int) *AlgorithmJob {
+	t.Helper()
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		var job AlgorithmJob
+		if status := getJSON(t, fmt.Sprintf("%s/api/jobs/%d", baseURL, id), &job); status != http.StatusOK {
+			t.Fatalf("iş %d: HTTP %d", id, status)
+		}
+		if job.Status == AlgorithmJobCompleted || job.Status == AlgorithmJobDead {
+			return &job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("iş %d bitmedi: %s", id, job.Status)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// solverFields holds the flattened solver results of a matrix response
+type solverFields struct {
+	BoyarXorCount *int `json:"boyar_xor_count"`
+	BoyarDepth    *int `json:"boyar_depth"`
+	PaarXorCount  *int `json:"paar_xor_count"`
+}
+
+// randomTestMatrix returns a rows x cols binary matrix without zero rows
+func randomTestMatrix(rng *rand.Rand, rows, cols int) Matrix {
+	matrix := make(Matrix, rows)
+	for i := range matrix {
+		matrix[i] = make([]string, cols)
+		for j := range matrix[i] {
+			matrix[i][j] = "0"
+		}
+		for _, j := range rng.Perm(cols)[:1+rng.Intn(cols)] {
+			matrix[i][j] = "1"
+		}
+	}
+	return matrix
+}
+
+// seedTestMatrices stores binary matrices, some with solver results, and a
+// field matrix
+func seedTestMatrices(t *testing.T, store MatrixStore) {
+	t.Helper()
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 16; i++ {
+		title := fmt.Sprintf("alpha %02d", i)
+		if i%3 == 0 {
+			title = fmt.Sprintf("beta %02d", i)
+		}
+		matrix := randomTestMatrix(rng, 3+i%3, 3+i%3)
+		record, err := SaveMatrix(store, title, matrix, "seed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 0 {
+			results, errs := runSolvers(context.Background(), DefaultAlgorithms(), nil, matrix)
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+			if err := UpdateMatrixResults(store, record.ID, results); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	field, err := NewGF2m("x^4+x+1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	elements, err := field.ParseMatrix([][]string{{"1", "2"}, {"2", "1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SaveFieldMatrix(store, "field 2x2", field, elements, "seed"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func matrixIDs(records []*MatrixRecord) []int {
+	ids := make([]int, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return ids
+}
+
+func TestGetMatricesHandlerMatchesStore(t *testing.T) {
+	store := NewMemoryStore()
+	seedTestMatrices(t, store)
+	server := newTestServer(t, store)
+
+	tests := []struct {
+		query  string
+		page   int
+		limit  int
+		filter MatrixFilter
+	}{
+		{"", 1, 10, MatrixFilter{}},
+		{"page=2&limit=4", 2, 4, MatrixFilter{}},
+		{"page=0&limit=500", 1, 10, MatrixFilter{}},
+		{"title=BETA", 1, 10, MatrixFilter{Title: "BETA"}},
+		{"ham_xor_min=3&ham_xor_max=6&limit=100", 1, 100, MatrixFilter{HamXorMin: intPtr(3), HamXorMax: intPtr(6)}},
+		{"boyar_xor_max=4&limit=100", 1, 100, MatrixFilter{SolverXor: map[string]XorRange{"boyar": {Max: intPtr(4)}}}},
+		{"paar_xor_min=3&slp_xor_max=9&limit=100", 1, 100, MatrixFilter{SolverXor: map[string]XorRange{"paar": {Min: intPtr(3)}, "slp": {Max: intPtr(9)}}}},
+		{"gxor_min=2&limit=100", 1, 100, MatrixFilter{GXorMin: intPtr(2)}},
+		{"field_degree=4", 1, 10, MatrixFilter{FieldDegree: intPtr(4)}},
+		{"ham_xor_min=abc&title=alpha&page=3&limit=3", 3, 3, MatrixFilter{Title: "alpha"}},
+		{"group_by=canonical&limit=100", 1, 100, MatrixFilter{GroupCanonical: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			want, total, err := store.GetMatrices(tt.page, tt.limit, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got GetMatricesResponse
+			if status := getJSON(t, server.URL+"/api/matrices?"+tt.query, &got); status != http.StatusOK {
+				t.Fatalf("HTTP %d", status)
+			}
+			if got.Total != total || got.Page != tt.page || got.Limit != tt.limit {
+				t.Errorf("total/page/limit = %d/%d/%d, beklenen %d/%d/%d", got.Total, got.Page, got.Limit, total, tt.page, tt.limit)
+			}
+			if wantPages := (total + tt.limit - 1) / tt.limit; got.TotalPages != wantPages {
+				t.Errorf("total_pages = %d, beklenen %d", got.TotalPages, wantPages)
+			}
+			if fmt.Sprint(matrixIDs(got.Matrices)) != fmt.Sprint(matrixIDs(want)) {
+				t.Errorf("matrisler = %v, beklenen %v", matrixIDs(got.Matrices), matrixIDs(want))
+			}
+		})
+	}
+}
+
+func TestGetMatricesHandlerPagination(t *testing.T) {
+	store := NewMemoryStore()
+	seedTestMatrices(t, store)
+	server := newTestServer(t, store)
+
+	all, total, err := store.GetMatrices(1, 1000, MatrixFilter{Title: "alpha"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total == 0 || total != len(all) {
+		t.Fatalf("total = %d, %d kayıt", total, len(all))
+	}
+
+	// Walking the pages lists every matching matrix once, in order
+	var paged []*MatrixRecord
+	for page := 1; ; page++ {
+		var got GetMatricesResponse
+		getJSON(t, fmt.Sprintf("%s/api/matrices?title=alpha&limit=3&page=%d", server.URL, page), &got)
+		if got.Total != total {
+			t.Fatalf("sayfa %d: total = %d, beklenen %d", page, got.Total, total)
+		}
+		if len(got.Matrices) == 0 {
+			if page != got.TotalPages+1 {
+				t.Errorf("boş sayfa %d, total_pages = %d", page, got.TotalPages)
+			}
+			break
+		}
+		paged = append(paged, got.Matrices...)
+	}
+	if fmt.Sprint(matrixIDs(paged)) != fmt.Sprint(matrixIDs(all)) {
+		t.Errorf("sayfalar = %v, beklenen %v", matrixIDs(paged), matrixIDs(all))
+	}
+}
+
+func TestRecalculateHandler(t *testing.T) {
+	store := NewMemoryStore()
+	server := newTestServer(t, store)
+	record, err := SaveMatrix(store, "recalc", Matrix{{"1", "1", "0", "1"}, {"0", "1", "1", "1"}, {"1", "0", "1", "1"}, {"1", "1", "1", "0"}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Invalid requests are rejected before anything is queued
+	if status := postJSON(t, server.URL+"/api/matrices/recalculate", RecalculateRequest{MatrixID: record.ID, Algorithms: []string{"nope"}}, nil); status != http.StatusBadRequest {
+		t.Errorf("bilinmeyen algoritma: HTTP %d", status)
+	}
+	if status := postJSON(t, server.URL+"/api/matrices/recalculate", RecalculateRequest{MatrixID: record.ID + 100}, nil); status != http.StatusNotFound {
+		t.Errorf("olmayan matris: HTTP %d", status)
+	}
+
+	request := RecalculateRequest{
+		MatrixID:   record.ID,
+		Algorithms: []string{"boyar", "paar"},
+		Params:     map[string]SolverParams{"boyar": {"depth_limit": 3}},
+	}
+	if status := postJSON(t, server.URL+"/api/matrices/recalculate", request, nil); status != http.StatusOK {
+		t.Fatalf("HTTP %d", status)
+	}
+
+	var jobs []*AlgorithmJob
+	getJSON(t, fmt.Sprintf("%s/api/jobs?matrix_id=%d", server.URL, record.ID), &jobs)
+	if len(jobs) != 1 {
+		t.Fatalf("%d iş kuyrukta, beklenen 1", len(jobs))
+	}
+	if job := jobs[0]; job.Status != AlgorithmJobQueued || job.Source != JobSourceRecalculate || job.Priority != jobPriorityUser {
+		t.Fatalf("iş = %s/%s/%d", job.Status, job.Source, job.Priority)
+	}
+
+	startTestWorkers(t, store)
+	if job := waitForJob(t, server.URL, jobs[0].ID); job.Status != AlgorithmJobCompleted {
+		t.Fatalf("iş %s: %v", job.Status, job.LastError)
+	}
+
+	var updated solverFields
+	getJSON(t, fmt.Sprintf("%s/api/matrices/%d", server.URL, record.ID), &updated)
+	if updated.BoyarXorCount == nil || updated.PaarXorCount == nil {
+		t.Fatalf("sonuçlar kaydedilmedi: boyar=%v paar=%v", updated.BoyarXorCount, updated.PaarXorCount)
+	}
+	if updated.BoyarDepth == nil || *updated.BoyarDepth > 3 {
+		t.Errorf("boyar derinliği = %v, depth_limit 3", updated.BoyarDepth)
+	}
+
+	var runs []*AlgorithmRun
+	getJSON(t, fmt.Sprintf("%s/api/matrices/%d/runs?algorithm=boyar", server.URL, record.ID), &runs)
+	if len(runs) != 1 || !runs[0].Best || runs[0].Parameters.Int("depth_limit", 0) != 3 {
+		t.Errorf("boyar çalıştırmaları = %+v", runs)
+	}
+	if status := getJSON(t, fmt.Sprintf("%s/api/matrices/%d/runs?algorithm=nope", server.URL, record.ID), nil); status != http.StatusBadRequest {
+		t.Errorf("bilinmeyen algoritmanın çalıştırmaları: HTTP %d", status)
+	}
+
+	// Invalid parameters fail the same way on every attempt
+	request.Params = map[string]SolverParams{"boyar": {"depth_limit": 99}}
+	if status := postJSON(t, server.URL+"/api/matrices/recalculate", request, nil); status != http.StatusOK {
+		t.Fatalf("HTTP %d", status)
+	}
+	getJSON(t, fmt.Sprintf("%s/api/jobs?matrix_id=%d", server.URL, record.ID), &jobs)
+	if len(jobs) != 2 {
+		t.Fatalf("%d iş, beklenen 2", len(jobs))
+	}
+	if job := waitForJob(t, server.URL, jobs[0].ID); job.Status != AlgorithmJobDead || job.Attempts != 1 {
+		t.Errorf("geçersiz depth_limit: iş %s, %d deneme", job.Status, job.Attempts)
+	}
+
+	// Only dead jobs can be retried
+	if status := postJSON(t, fmt.Sprintf("%s/api/jobs/%d/retry", server.URL, jobs[0].ID), nil, nil); status != http.StatusAccepted {
+		t.Errorf("retry: HTTP %d", status)
+	}
+	if job := waitForJob(t, server.URL, jobs[0].ID); job.Status != AlgorithmJobDead || job.Attempts != 1 {
+		t.Errorf("tekrar denenen iş %s, %d deneme", job.Status, job.Attempts)
+	}
+	if status := postJSON(t, fmt.Sprintf("%s/api/jobs/%d/retry", server.URL, jobs[1].ID), nil, nil); status != http.StatusConflict {
+		t.Errorf("tamamlanmış işin tekrarı: HTTP %d", status)
+	}
+}
+
+func TestDepthSweepHandler(t *testing.T) {
+	store := NewMemoryStore()
+	server := newTestServer(t, store)
+	record, err := SaveMatrix(store, "sweep", chainMatrix, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := fmt.Sprintf("%s/api/matrices/%d/pareto", server.URL, record.ID)
+
+	if status := postJSON(t, url, DepthSweepRequest{Params: SolverParams{"max_gates": -1}}, nil); status != http.StatusBadRequest {
+		t.Errorf("geçersiz parametre: HTTP %d", status)
+	}
+
+	// The sweep is queued as a job; a second one is rejected until it finishes
+	resp, err := http.Post(url, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var started DepthSweepResponse
+	err = json.NewDecoder(resp.Body).Decode(&started)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted || err != nil || started.JobID == 0 {
+		t.Fatalf("HTTP %d, %+v, %v", resp.StatusCode, started, err)
+	}
+	if status := postJSON(t, url, nil, nil); status != http.StatusConflict {
+		t.Errorf("ikinci tarama: HTTP %d", status)
+	}
+	var front ParetoFront
+	getJSON(t, url, &front)
+	if !front.Running || len(front.Points) != 0 {
+		t.Errorf("kuyruktaki tarama: running=%v, %d nokta", front.Running, len(front.Points))
+	}
+
+	startTestWorkers(t, store)
+	if job := waitForJob(t, server.URL, started.JobID); job.Status != AlgorithmJobCompleted || job.Source != JobSourceDepthSweep {
+		t.Fatalf("tarama işi %s/%s: %v", job.Status, job.Source, job.LastError)
+	}
+	getJSON(t, url, &front)
+	if front.Running || len(front.Points) == 0 || front.Points[0].Depth < started.MinDepth {
+		t.Errorf("biten tarama: running=%v, noktalar %+v, en küçük derinlik %d", front.Running, front.Points, started.MinDepth)
+	}
+}
+
+func TestImportMatricesFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	data := strings.Join([]string{
+		"A matrisi:",
+		"[1 1 0]",
+		"[0 1 1]",
+		"[0 0 1]",
+		"HamXOR Sayisi: 2",
+		"------------------------------",
+		"B matrisi:",
+		"[1 1 1]",
+		"[0 1 1]",
+		"[1 0 1]",
+		"------------------------------",
+		"A tekrar matrisi:",
+		"[1 1 0]",
+		"[0 1 1]",
+		"[0 0 1]",
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(dir, "imported.txt"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewMemoryStore()
+	server := newTestServer(t, store)
+	if err := ImportMatricesFromFiles(store, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Duplicates are skipped, the file name becomes the group
+	var listed GetMatricesResponse
+	getJSON(t, server.URL+"/api/matrices?limit=100", &listed)
+	if listed.Total != 2 {
+		t.Fatalf("%d matris import edildi, beklenen 2", listed.Total)
+	}
+	for _, record := range listed.Matrices {
+		if record.Group != "imported" {
+			t.Errorf("%s grubu = %q", record.Title, record.Group)
+		}
+	}
+
+	// Every imported matrix queues one job that fills in the default solvers
+	var jobs []*AlgorithmJob
+	getJSON(t, server.URL+"/api/jobs", &jobs)
+	if len(jobs) != 2 {
+		t.Fatalf("%d iş kuyrukta, beklenen 2", len(jobs))
+	}
+	startTestWorkers(t, store)
+	for _, job := range jobs {
+		if job.Source != JobSourceImport {
+			t.Errorf("iş %d kaynağı = %s", job.ID, job.Source)
+		}
+		if done := waitForJob(t, server.URL, job.ID); done.Status != AlgorithmJobCompleted {
+			t.Fatalf("iş %d: %s", job.ID, done.Status)
+		}
+	}
+	if missing, err := store.GetMatricesWithoutAlgorithms(100); err != nil || len(missing) != 0 {
+		t.Errorf("%d matriste eksik algoritma kaldı: %v", len(missing), err)
+	}
+	var imported struct {
+		Matrices []solverFields `json:"matrices"`
+	}
+	getJSON(t, server.URL+"/api/matrices?title=A+matrisi&limit=100", &imported)
+	if len(imported.Matrices) != 1 || imported.Matrices[0].BoyarXorCount == nil || *imported.Matrices[0].BoyarXorCount != 2 {
+		t.Fatalf("A matrisi = %+v", imported.Matrices)
+	}
+}
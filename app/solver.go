@@ -0,0 +1,366 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result statuses reported in AlgResult.Status
+const (
+	StatusCompleted = "completed" // The solver ran to the end
+	StatusTimedOut  = "timed_out" // A time or iteration budget ran out, the program is the best found so far
+	StatusAborted   = "aborted"   // The program grew past the max_gates budget; XorCount is the size reached, there is no program
+)
+
+// Solver is the common interface implemented by every XOR optimization algorithm
+type Solver interface {
+	// Name returns the registry name of the solver ("boyar", "paar", ...)
+	Name() string
+	// Parameters returns the effective parameters the solver runs with
+	Parameters() SolverParams
+	// Solve computes a linear straight-line program for the matrix. When ctx
+	// hits its deadline or an iteration budget runs out, Solve returns the best
+	// complete program found so far with StatusTimedOut; a cancelled ctx is
+	// returned as an error.
+	Solve(ctx context.Context, matrix Matrix) (AlgResult, error)
+}
+
+// SolverParams holds algorithm specific parameters such as depth_limit
+type SolverParams map[string]interface{}
+
+// Int returns the integer parameter for key, or def if it is missing or invalid
+func (p SolverParams) Int(key string, def int) int {
+	return int(p.Int64(key, int64(def)))
+}
+
+// Int64 returns the 64-bit integer parameter for key, or def if it is missing or invalid
+func (p SolverParams) Int64(key string, def int64) int64 {
+	val, ok := p[key]
+	if !ok || val == nil {
+		return def
+	}
+	switch v := val.(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+	case string:
+		if i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+			return i
+		}
+	}
+	return def
+}
+
+// Bool returns the boolean parameter for key, or def if it is missing or invalid
+func (p SolverParams) Bool(key string, def bool) bool {
+	val, ok := p[key]
+	if !ok || val == nil {
+		return def
+	}
+	switch v := val.(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// Has reports whether the parameter is present
+func (p SolverParams) Has(key string) bool {
+	val, ok := p[key]
+	return ok && val != nil
+}
+
+// SolverFactory creates a solver instance configured with the given parameters
+type SolverFactory func(params SolverParams) (Solver, error)
+
+// SolverInfo describes a registered solver
+type SolverInfo struct {
+	Name       string        `json:"name"`       // Name used in API requests and config
+	Label      string        `json:"label"`      // Name reported in algorithm responses
+	HasDepth   bool          `json:"has_depth"`  // Whether the solver reports circuit depth
+	Default    bool          `json:"default"`    // Whether the solver runs when no algorithm is requested
+	Randomized bool          `json:"randomized"` // Whether the solver has a seeded randomized mode
+	Persisted  bool          `json:"persisted"`  // Whether matrix records show the solver's best run (Results, list columns, filters)
+	Version    string        `json:"version"`    // Stored with every run; bump when the solver finds different programs. Defaults to "1"
+	Factory    SolverFactory `json:"-"`
+}
+
+var (
+	solverRegistryMu sync.RWMutex
+	solverRegistry   = make(map[string]*SolverInfo)
+)
+
+// RegisterSolver adds a solver to the registry; it panics on duplicate names
+func RegisterSolver(info SolverInfo) {
+	solverRegistryMu.Lock()
+	defer solverRegistryMu.Unlock()
+
+	name := strings.ToLower(info.Name)
+	if name == "" || info.Factory == nil {
+		panic("solver kaydı için isim ve factory gerekli")
+	}
+	if _, exists := solverRegistry[name]; exists {
+		panic(fmt.Sprintf("solver zaten kayıtlı: %s", name))
+	}
+	info.Name = name
+	if info.Version == "" {
+		info.Version = "1"
+	}
+	solverRegistry[name] = &info
+}
+
+// GetSolverInfo returns the registry entry for the given algorithm name
+func GetSolverInfo(name string) (*SolverInfo, bool) {
+	solverRegistryMu.RLock()
+	defer solverRegistryMu.RUnlock()
+
+	info, ok := solverRegistry[strings.ToLower(strings.TrimSpace(name))]
+	return info, ok
+}
+
+// RegisteredSolvers returns all registered solvers sorted by name, so the
+// order does not depend on which file registered a solver first
+func RegisteredSolvers() []*SolverInfo {
+	solverRegistryMu.RLock()
+	defer solverRegistryMu.RUnlock()
+
+	infos := make([]*SolverInfo, 0, len(solverRegistry))
+	for _, info := range solverRegistry {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// persistedSolvers returns the registered solvers whose best run matrix
+// records show as a result
+func persistedSolvers() []*SolverInfo {
+	var infos []*SolverInfo
+	for _, info := range RegisteredSolvers() {
+		if info.Persisted {
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+// DefaultAlgorithms returns the names of the solvers that run by default
+func DefaultAlgorithms() []string {
+	var names []string
+	for _, info := range RegisteredSolvers() {
+		if info.Default {
+			names = append(names, info.Name)
+		}
+	}
+	return names
+}
+
+// NewSolver creates a solver by name
+func NewSolver(name string, params SolverParams) (Solver, error) {
+	info, ok := GetSolverInfo(name)
+	if !ok {
+		return nil, fmt.Errorf("desteklenmeyen algoritma: %s", name)
+	}
+	if params == nil {
+		params = SolverParams{}
+	}
+	return info.Factory(params)
+}
+
+// normalizeAlgorithms lower-cases the requested names, falls back to the
+// default algorithms and rejects names that are not registered
+func normalizeAlgorithms(algorithms []string) ([]string, error) {
+	if len(algorithms) == 0 {
+		return DefaultAlgorithms(), nil
+	}
+
+	names := make([]string, 0, len(algorithms))
+	for _, algorithm := range algorithms {
+		info, ok := GetSolverInfo(algorithm)
+		if !ok {
+			return nil, fmt.Errorf("desteklenmeyen algoritma: %s", algorithm)
+		}
+		names = append(names, info.Name)
+	}
+	return names, nil
+}
+
+// deadlineExceeded reports whether err means the solver ran out of time. Solvers
+// then return their best program with StatusTimedOut instead of failing, while
+// plain cancellation (e.g. a closed HTTP request) is still returned as an error.
+func deadlineExceeded(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// runSolver creates the named solver and runs it on the matrix. The optional
+// "time_budget_ms" parameter adds a wall-clock deadline to ctx, "transpose"
+// also solves M^T and keeps the better program (see runSolverWithTranspose).
+func runSolver(ctx context.Context, name string, params SolverParams, matrix Matrix) (*AlgResult, error) {
+	start := time.Now()
+	if params.Bool("transpose", false) {
+		result, err := runSolverWithTranspose(ctx, name, params, matrix)
+		if err != nil {
+			return nil, err
+		}
+		parameters := SolverParams{"transpose": true}
+		for key, val := range result.Parameters {
+			parameters[key] = val
+		}
+		result.Parameters = parameters
+		result.DurationMs = time.Since(start).Milliseconds()
+		return result, nil
+	}
+
+	solver, err := NewSolver(name, params)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := params.Int("time_budget_ms", 0)
+	if budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(budget)*time.Millisecond)
+		defer cancel()
+	}
+
+	result, err := solver.Solve(ctx, matrix)
+	if err != nil {
+		return nil, err
+	}
+	if result.Status == "" {
+		result.Status = StatusCompleted
+	}
+	if result.Program != nil {
+		result.PeakLive = result.Program.Schedule().PeakLive()
+	}
+	result.Parameters = solver.Parameters()
+	if budget > 0 {
+		result.Parameters["time_budget_ms"] = budget
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	return &result, nil
+}
+
+// maxGatesFromParams returns the optional "max_gates" budget: a run stops
+// with StatusAborted as soon as its program has more gates. 0 means no bound.
+func maxGatesFromParams(params SolverParams) (int, error) {
+	maxGates := params.Int("max_gates", 0)
+	if maxGates < 0 {
+		return 0, fmt.Errorf("max_gates negatif olamaz: %d", maxGates)
+	}
+	return maxGates, nil
+}
+
+// solverTimeout is the default deadline of a single solver run outside the
+// per-algorithm endpoints, set from import.solver_timeout_seconds; 0 disables it
+var solverTimeout time.Duration
+
+// withSolverDeadline derives the context of one solver run; timeout 0 falls
+// back to solverTimeout
+func withSolverDeadline(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		timeout = solverTimeout
+	}
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// runSolvers runs every named solver on the matrix and collects the results;
+// params is keyed by solver name and may be nil. Each solver gets its own
+// solverTimeout deadline. Failing solvers are reported in the returned error map
+func runSolvers(ctx context.Context, names []string, params map[string]SolverParams, matrix Matrix) (map[string]*AlgResult, map[string]error) {
+	results := make(map[string]*AlgResult)
+	errs := make(map[string]error)
+
+	for _, name := range names {
+		solverCtx, cancel := withSolverDeadline(ctx, 0)
+		result, err := runSolver(solverCtx, name, params[name], matrix)
+		cancel()
+		if err != nil {
+			errs[name] = err
+			continue
+		}
+		results[name] = result
+	}
+
+	return results, errs
+}
+
+// Built-in solvers
+func init() {
+	RegisterSolver(SolverInfo{
+		Name:       "boyar",
+		Label:      "BoyarSLP",
+		HasDepth:   true,
+		Default:    true,
+		Randomized: true,
+		Persisted:  true,
+		Factory: func(params SolverParams) (Solver, error) {
+			depthLimit := params.Int("depth_limit", boyarDepthLimit)
+			if depthLimit < 1 || depthLimit > maxBoyarDepthLimit {
+				return nil, fmt.Errorf("depth_limit 1 ile %d arasında olmalı: %d", maxBoyarDepthLimit, depthLimit)
+			}
+			solver := NewBoyarSLP(depthLimit)
+			solver.MaxIterations = params.Int("max_iterations", MAX_ITERATIONS)
+			maxGates, err := maxGatesFromParams(params)
+			if err != nil {
+				return nil, err
+			}
+			solver.MaxGates = maxGates
+			solver.Random = randomizedSearchFromParams(params)
+			return solver, nil
+		},
+	})
+	RegisterSolver(SolverInfo{
+		Name:      "paar",
+		Label:     "PAAR",
+		Default:   true,
+		Persisted: true,
+		Factory: func(params SolverParams) (Solver, error) {
+			solver := NewPaarAlgorithm()
+			solver.MaxIterations = params.Int("max_iterations", 0)
+			return solver, nil
+		},
+	})
+	RegisterSolver(SolverInfo{
+		Name:       "slp",
+		Label:      "SLP_Heuristic",
+		Default:    true,
+		Randomized: true,
+		Persisted:  true,
+		Factory: func(params SolverParams) (Solver, error) {
+			solver := NewSLPHeuristic()
+			solver.MaxIterations = params.Int("max_iterations", MAX_ITERATIONS)
+			maxGates, err := maxGatesFromParams(params)
+			if err != nil {
+				return nil, err
+			}
+			solver.MaxGates = maxGates
+			solver.Random = randomizedSearchFromParams(params)
+			return solver, nil
+		},
+	})
+}
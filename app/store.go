@@ -0,0 +1,57 @@
+package main
+
+import "time"
+
+// MatrixStore is the storage the handlers and the algorithm workers use.
+// Database implements it on PostgreSQL and on SQLite, MemoryStore in process
+// memory; the driver is chosen by database.driver in the config (see
+// openStore). Its methods are storage primitives: logic shared by the
+// backends, such as hashing a new matrix or recomputing the s-XOR program,
+// lives in functions over a MatrixStore (SaveMatrix, UpdateMatrixResults,
+// UpdateInPlaceResult, ...).
+type MatrixStore interface {
+	// Matrices; lookups return nil for a missing matrix
+	InsertMatrix(record *MatrixRecord) (int, error)
+	GetMatrixByID(id int) (*MatrixRecord, error)
+	GetMatrixByHash(hash string) (*MatrixRecord, error)
+	GetEquivalentMatrices(record *MatrixRecord) ([]*MatrixRecord, error)
+	GetMatrices(page, limit int, filter MatrixFilter) ([]*MatrixRecord, int, error)
+	GetMatricesWithoutAlgorithms(limit int) ([]*MatrixRecord, error)
+	GetAllMatrixHashes() (map[string]bool, error)
+	TitleExists(title string) (bool, error)
+	UpdateHamXorCount(id int, hamXor int) error
+	SetFieldRepresentation(id int, field *GF2m, elements FieldMatrix) (bool, error)
+	SaveAnalysis(id int, analysis *MatrixAnalysis) error
+	SetInverseReference(originalID, inverseID int, inverseHash string) error
+
+	// Solver runs and the results derived from them
+	SaveAlgorithmRun(matrixID int, algorithm string, result *AlgResult, asResult bool) (int, error)
+	GetAlgorithmRuns(record *MatrixRecord, algorithm string) ([]*AlgorithmRun, error)
+	GetDepthResults(record *MatrixRecord) ([]*DepthResult, error)
+	UpdateVerification(id int, verdicts map[int]bool) error
+	SaveOptimalResult(matrixID int, result *AlgResult) error
+	SaveInPlaceResult(id int, program *InPlaceProgram, source string) error
+	ReplaceParetoFront(matrixID int, points []*ParetoPoint) error
+	GetParetoFront(record *MatrixRecord) ([]*ParetoPoint, error)
+
+	// Generator jobs
+	CreateGeneratorJob(job *GeneratorJob) error
+	GetGeneratorJob(id int) (*GeneratorJob, error)
+	GetGeneratorJobs(status string) ([]*GeneratorJob, error)
+	SaveGeneratorProgress(job *GeneratorJob) error
+
+	// Algorithm jobs; a job is only changed by the worker holding its lease
+	EnqueueAlgorithmJob(job *AlgorithmJob) error
+	LeaseAlgorithmJob(owner string, lease time.Duration) (*AlgorithmJob, error)
+	RenewAlgorithmJobLease(job *AlgorithmJob, lease time.Duration) (bool, error)
+	FinishAlgorithmJob(job *AlgorithmJob) (bool, error)
+	ReleaseAlgorithmJob(job *AlgorithmJob) (bool, error)
+	RequeueExpiredAlgorithmJobs() (int, error)
+	RetryAlgorithmJob(id int) (bool, error)
+	GetAlgorithmJob(id int) (*AlgorithmJob, error)
+	GetAlgorithmJobs(status string, matrixID int, limit int) ([]*AlgorithmJob, error)
+
+	Close() error
+}
+
+var _ MatrixStore = (*Database)(nil)
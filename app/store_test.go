@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// solve runs the named solver and fails the test on error
+func solve(t *testing.T, name string, params SolverParams, matrix Matrix) *AlgResult {
+	t.Helper()
+	result, err := runSolver(context.Background(), name, params, matrix)
+	if err != nil {
+		t.Fatalf("%s: beklenmeyen hata: %v", name, err)
+	}
+	return result
+}
+
+// forEachStore runs test on a migrated SQLite database and on a MemoryStore,
+// so both follow the same storage semantics
+func forEachStore(t *testing.T, test func(t *testing.T, store MatrixStore)) {
+	t.Run("sqlite", func(t *testing.T) { test(t, newTestDatabase(t)) })
+	t.Run("memory", func(t *testing.T) { test(t, NewMemoryStore()) })
+}
+
+// listedIDs returns the IDs of the matrices GetMatrices lists for filter
+func listedIDs(t *testing.T, store MatrixStore, filter MatrixFilter) []int {
+	t.Helper()
+	records, total, err := store.GetMatrices(1, 50, filter)
+	if err != nil {
+		t.Fatalf("GetMatrices(%+v): %v", filter, err)
+	}
+	ids := make([]int, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	if total != len(ids) {
+		t.Errorf("GetMatrices(%+v): toplam %d, listelenen %d", filter, total, len(ids))
+	}
+	return ids
+}
+
+func TestStoreResults(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store MatrixStore) {
+		rng := rand.New(rand.NewSource(31))
+		matrices := invertibleMatrices(t, rng, 6, 6)
+
+		first, err := SaveMatrix(store, "birinci", matrices[0], "test")
+		if err != nil {
+			t.Fatalf("SaveMatrix: %v", err)
+		}
+		second, err := SaveMatrix(store, "ikinci", matrices[1], "test")
+		if err != nil {
+			t.Fatalf("SaveMatrix: %v", err)
+		}
+		if again, err := SaveMatrix(store, "kopya", matrices[0], "test"); err != nil || again.ID != first.ID {
+			t.Fatalf("aynı matris yeni kayıt oldu: %v, %v", again, err)
+		}
+		if pending, err := store.GetMatricesWithoutAlgorithms(10); err != nil || len(pending) != 2 {
+			t.Fatalf("GetMatricesWithoutAlgorithms = %d kayıt, %v; beklenen 2", len(pending), err)
+		}
+
+		paar := solve(t, "paar", nil, matrices[0])
+		boyar := solve(t, "boyar", SolverParams{"depth_limit": 3}, matrices[0])
+		if err := UpdateMatrixResults(store, first.ID, map[string]*AlgResult{"paar": paar, "boyar": boyar}); err != nil {
+			t.Fatalf("UpdateMatrixResults: %v", err)
+		}
+
+		record, err := store.GetMatrixByID(first.ID)
+		if err != nil || record == nil {
+			t.Fatalf("GetMatrixByID: %v", err)
+		}
+		for name, want := range map[string]*AlgResult{"paar": paar, "boyar": boyar} {
+			got := record.Result(name)
+			if got == nil || got.XorCount == nil || *got.XorCount != want.XorCount || got.Program == nil {
+				t.Errorf("%s sonucu %+v, beklenen %d XOR ve program", name, got, want.XorCount)
+			}
+		}
+		smallest := paar.XorCount
+		if boyar.XorCount < smallest {
+			smallest = boyar.XorCount
+		}
+		if record.SmallestXor == nil || *record.SmallestXor != smallest {
+			t.Errorf("smallest_xor %v, beklenen %d", record.SmallestXor, smallest)
+		}
+		if record.SXorCount == nil {
+			t.Error("s-XOR sayısı hesaplanmadı")
+		}
+
+		// A worse run is recorded but does not replace the result
+		worse := *paar
+		worse.XorCount = paar.XorCount + 5
+		if _, err := store.SaveAlgorithmRun(first.ID, "paar", &worse, true); err != nil {
+			t.Fatalf("SaveAlgorithmRun: %v", err)
+		}
+		runs, err := store.GetAlgorithmRuns(record, "paar")
+		if err != nil || len(runs) != 2 {
+			t.Fatalf("GetAlgorithmRuns = %d çalıştırma, %v; beklenen 2", len(runs), err)
+		}
+		if runs[0].Best || !runs[1].Best {
+			t.Errorf("en iyi işaretleri %v, %v; beklenen yalnızca ilk çalıştırma", runs[0].Best, runs[1].Best)
+		}
+
+		// Depth sweep runs show up per depth limit without touching the result
+		sweep := solve(t, "boyar", SolverParams{"depth_limit": 5}, matrices[0])
+		if _, err := store.SaveAlgorithmRun(first.ID, "boyar", sweep, false); err != nil {
+			t.Fatalf("SaveAlgorithmRun (tarama): %v", err)
+		}
+		depthResults, err := store.GetDepthResults(record)
+		if err != nil || len(depthResults) != 2 {
+			t.Fatalf("GetDepthResults = %d sonuç, %v; beklenen 2", len(depthResults), err)
+		}
+		if depthResults[0].DepthLimit != 3 || depthResults[1].DepthLimit != 5 {
+			t.Errorf("derinlik sınırları %d, %d; beklenen 3, 5", depthResults[0].DepthLimit, depthResults[1].DepthLimit)
+		}
+		if err := RefreshParetoFront(store, record); err != nil {
+			t.Fatalf("RefreshParetoFront: %v", err)
+		}
+		if points, err := store.GetParetoFront(record); err != nil || len(points) == 0 {
+			t.Errorf("GetParetoFront = %d nokta, %v", len(points), err)
+		}
+
+		record, _ = store.GetMatrixByID(first.ID)
+		verdict := record.Result("paar")
+		if err := store.UpdateVerification(first.ID, map[int]bool{verdict.RunID: true}); err != nil {
+			t.Fatalf("UpdateVerification: %v", err)
+		}
+		record, _ = store.GetMatrixByID(first.ID)
+		if verified := record.Result("paar").Verified; verified == nil || !*verified {
+			t.Errorf("paar doğrulaması %v, beklenen true", verified)
+		}
+
+		// Listing filters read the best runs
+		paarXor := paar.XorCount
+		if ids := listedIDs(t, store, MatrixFilter{Title: "IKIN"}); len(ids) != 1 || ids[0] != second.ID {
+			t.Errorf("başlık filtresi %v, beklenen [%d]", ids, second.ID)
+		}
+		filter := MatrixFilter{SolverXor: map[string]XorRange{"paar": {Min: &paarXor, Max: &paarXor}}}
+		if ids := listedIDs(t, store, filter); len(ids) != 1 || ids[0] != first.ID {
+			t.Errorf("paar filtresi %v, beklenen [%d]", ids, first.ID)
+		}
+		if ids := listedIDs(t, store, MatrixFilter{GXorMax: &smallest}); len(ids) != 1 || ids[0] != first.ID {
+			t.Errorf("g-XOR filtresi %v, beklenen [%d]", ids, first.ID)
+		}
+		if ids := listedIDs(t, store, MatrixFilter{}); len(ids) != 2 {
+			t.Errorf("filtresiz liste %v, beklenen 2 kayıt", ids)
+		}
+
+		// Once every default solver has a result the matrix is no longer pending
+		missing := make(map[string]*AlgResult)
+		for _, name := range DefaultAlgorithms() {
+			if record.Result(name) == nil {
+				missing[name] = solve(t, name, nil, matrices[0])
+			}
+		}
+		if err := UpdateMatrixResults(store, first.ID, missing); err != nil {
+			t.Fatalf("UpdateMatrixResults: %v", err)
+		}
+		if pending, err := store.GetMatricesWithoutAlgorithms(10); err != nil || len(pending) != 1 || pending[0].ID != second.ID {
+			t.Errorf("GetMatricesWithoutAlgorithms = %v, %v; beklenen [%d]", pending, err, second.ID)
+		}
+	})
+}
+
+func TestStoreEquivalentsAndInverse(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store MatrixStore) {
+		matrix := invertibleMatrices(t, rand.New(rand.NewSource(37)), 5)[0]
+		permuted := Matrix{matrix[4], matrix[0], matrix[3], matrix[1], matrix[2]}
+
+		original, err := SaveMatrix(store, "orijinal", matrix, "test")
+		if err != nil {
+			t.Fatalf("SaveMatrix: %v", err)
+		}
+		if err := UpdateMatrixResults(store, original.ID, map[string]*AlgResult{"slp": solve(t, "slp", nil, matrix)}); err != nil {
+			t.Fatalf("UpdateMatrixResults: %v", err)
+		}
+		equivalent, err := SaveMatrix(store, "permütasyon", permuted, "test")
+		if err != nil {
+			t.Fatalf("SaveMatrix: %v", err)
+		}
+		equivalents, err := store.GetEquivalentMatrices(equivalent)
+		if err != nil || len(equivalents) != 1 || equivalents[0].ID != original.ID {
+			t.Fatalf("GetEquivalentMatrices = %v, %v; beklenen [%d]", equivalents, err, original.ID)
+		}
+		if reused := reuseEquivalentResults(store, equivalent.ID, permuted, []string{"slp"}); reused["slp"] == nil {
+			t.Error("slp sonucu eşdeğer matristen yeniden kullanılmadı")
+		}
+		if ids := listedIDs(t, store, MatrixFilter{GroupCanonical: true}); len(ids) != 1 {
+			t.Errorf("kanonik gruplama %v, beklenen tek kayıt", ids)
+		}
+
+		inverse, err := SaveMatrixInverse(store, original.ID)
+		if err != nil {
+			t.Fatalf("SaveMatrixInverse: %v", err)
+		}
+		record, _ := store.GetMatrixByID(original.ID)
+		if record.InverseMatrixID == nil || *record.InverseMatrixID != inverse.ID {
+			t.Errorf("inverse_matrix_id %v, beklenen %d", record.InverseMatrixID, inverse.ID)
+		}
+	})
+}
+
+func TestStoreGeneratorJobs(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store MatrixStore) {
+		job := &GeneratorJob{
+			Family:      "circulant",
+			Polynomial:  "x^4+x+1",
+			FieldDegree: 4,
+			Dimension:   4,
+			Algorithms:  []string{"paar"},
+			MaxResults:  3,
+			Cursor:      []uint32{1, 2},
+			Status:      GeneratorRunning,
+		}
+		if err := store.CreateGeneratorJob(job); err != nil {
+			t.Fatalf("CreateGeneratorJob: %v", err)
+		}
+		job.Examined, job.Found, job.Cursor = 7, 1, []uint32{3, 4}
+		if err := store.SaveGeneratorProgress(job); err != nil {
+			t.Fatalf("SaveGeneratorProgress: %v", err)
+		}
+		stored, err := store.GetGeneratorJob(job.ID)
+		if err != nil || stored == nil {
+			t.Fatalf("GetGeneratorJob: %v", err)
+		}
+		if stored.Examined != 7 || stored.Found != 1 || len(stored.Cursor) != 2 || stored.Cursor[0] != 3 {
+			t.Errorf("saklanan iş %+v", stored)
+		}
+		if jobs, err := store.GetGeneratorJobs(GeneratorRunning); err != nil || len(jobs) != 1 {
+			t.Errorf("GetGeneratorJobs = %d iş, %v; beklenen 1", len(jobs), err)
+		}
+	})
+}
+
+func TestStoreFieldMatrix(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store MatrixStore) {
+		field, err := NewGF2m("x^4+x+1")
+		if err != nil {
+			t.Fatalf("NewGF2m: %v", err)
+		}
+		elements, err := field.ParseMatrix([][]string{{"1", "2"}, {"2", "1"}})
+		if err != nil {
+			t.Fatalf("ParseMatrix: %v", err)
+		}
+		record, err := SaveFieldMatrix(store, "mds", field, elements, "test")
+		if err != nil {
+			t.Fatalf("SaveFieldMatrix: %v", err)
+		}
+		if record.FieldDegree == nil || *record.FieldDegree != 4 {
+			t.Fatalf("field_degree %v, beklenen 4", record.FieldDegree)
+		}
+		if _, err := SaveMatrix(store, "binary", chainMatrix, "test"); err != nil {
+			t.Fatalf("SaveMatrix: %v", err)
+		}
+
+		degree := 4
+		if ids := listedIDs(t, store, MatrixFilter{FieldDegree: &degree, Flags: map[string]bool{"is_mds": true}}); len(ids) != 1 || ids[0] != record.ID {
+			t.Errorf("alan ve MDS filtresi %v, beklenen [%d]", ids, record.ID)
+		}
+
+		exact := solve(t, "exact", nil, chainMatrix)
+		binary, _ := store.GetMatrixByHash(calculateMatrixHash(chainMatrix))
+		if err := UpdateMatrixResults(store, binary.ID, map[string]*AlgResult{"exact": exact}); err != nil {
+			t.Fatalf("UpdateMatrixResults: %v", err)
+		}
+		proven := true
+		if ids := listedIDs(t, store, MatrixFilter{OptimalProven: &proven}); len(ids) != 1 || ids[0] != binary.ID {
+			t.Errorf("kanıtlanmış optimum filtresi %v, beklenen [%d]", ids, binary.ID)
+		}
+	})
+}
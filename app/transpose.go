@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"log"
+)
+
+// Result origins reported in AlgResult.Origin
+const (
+	OriginDirect    = "direct"        // The solver ran on the matrix itself
+	OriginTranspose = "via_transpose" // The solver ran on the transpose and its program was transposed back
+)
+
+// transposeMatrix returns M^T
+func transposeMatrix(matrix Matrix) Matrix {
+	if len(matrix) == 0 {
+		return Matrix{}
+	}
+	transposed := make(Matrix, len(matrix[0]))
+	for j := range transposed {
+		transposed[j] = make([]string, len(matrix))
+		for i, row := range matrix {
+			transposed[j][i] = row[j]
+		}
+	}
+	return transposed
+}
+
+// transposeProgram applies the transposition principle: given a program for
+// an r×c matrix M it returns a program for M^T. Every signal of the original
+// becomes the sum of the signals it feeds (gates and outputs), so the edges
+// are reversed; output i of the original is input i of the result and input
+// j of the original is output j. A signal feeding k consumers costs k-1 XORs,
+// which gives XorCount + r - c gates when every signal is used. Sums are
+// built with AddXorTree to keep the depth low.
+func transposeProgram(program *Program) *Program {
+	numSignals := program.NumSignals()
+	consumers := make([][]int, numSignals) // Gates each signal feeds, as gate indexes
+	outputs := make([][]int, numSignals)   // Outputs each signal computes
+	for k, gate := range program.Gates {
+		consumers[gate.A] = append(consumers[gate.A], k)
+		consumers[gate.B] = append(consumers[gate.B], k)
+	}
+	for i, s := range program.Outputs {
+		if s >= 0 {
+			outputs[s] = append(outputs[s], i)
+		}
+	}
+
+	// Signals of the result in reverse topological order of the original:
+	// a signal's consumers are all later gates
+	transposed := NewProgram(len(program.Outputs), program.NumInputs)
+	value := make([]int, numSignals)
+	for s := numSignals - 1; s >= 0; s-- {
+		var terms []int
+		terms = append(terms, outputs[s]...)
+		for _, k := range consumers[s] {
+			if v := value[program.NumInputs+k]; v >= 0 {
+				terms = append(terms, v)
+			}
+		}
+		value[s] = transposed.AddXorTree(terms)
+	}
+	for j := 0; j < program.NumInputs; j++ {
+		transposed.Outputs[j] = value[j]
+	}
+	return transposed
+}
+
+// runSolverWithTranspose runs the named solver on the matrix and on its
+// transpose, transposes the second program back and returns the result with
+// fewer XORs. Ties keep the direct result. A transposed-back program that is
+// invalid or deeper than the run's depth limit is dropped.
+func runSolverWithTranspose(ctx context.Context, name string, params SolverParams, matrix Matrix) (*AlgResult, error) {
+	direct := SolverParams{}
+	for key, val := range params {
+		direct[key] = val
+	}
+	delete(direct, "transpose")
+
+	result, err := runSolver(ctx, name, direct, matrix)
+	if err != nil {
+		return nil, err
+	}
+	result.Origin = OriginDirect
+
+	viaTranspose, err := runSolver(ctx, name, direct, transposeMatrix(matrix))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	if viaTranspose.Status == StatusAborted || viaTranspose.Program == nil {
+		return result, nil
+	}
+
+	program := transposeProgram(viaTranspose.Program)
+	if verification := VerifyProgram(matrix, program); !verification.Valid {
+		log.Printf("⚠️  [TRANSPOSE] %s transpoz programı geçersiz: %v", name, verification.Errors)
+		return result, nil
+	}
+	if result.Status != StatusAborted && program.XorCount() >= result.XorCount {
+		return result, nil
+	}
+	if maxGates := direct.Int("max_gates", 0); maxGates > 0 && program.XorCount() > maxGates {
+		return result, nil
+	}
+	info, _ := GetSolverInfo(name)
+	if info != nil && info.HasDepth && viaTranspose.DepthLimit > 0 && program.Depth() > viaTranspose.DepthLimit {
+		return result, nil
+	}
+
+	converted := &AlgResult{
+		MatrixIndex: result.MatrixIndex,
+		XorCount:    program.XorCount(),
+		Program:     program,
+		Seed:        viaTranspose.Seed,
+		Status:      viaTranspose.Status,
+		DepthLimit:  viaTranspose.DepthLimit,
+		PeakLive:    program.Schedule().PeakLive(),
+		Origin:      OriginTranspose,
+		LowerBound:  result.LowerBound, // Proven for M by the direct run; the transposed program proves nothing
+		Parameters:  result.Parameters,
+	}
+	if info != nil && info.HasDepth {
+		converted.Depth = program.Depth()
+	}
+	return converted, nil
+}
+
+// withTranspose returns a copy of params with "transpose" set for every
+// named solver; it is how the transpose option of recalculation requests
+// reaches runSolver
+func withTranspose(params map[string]SolverParams, names []string) map[string]SolverParams {
+	transposed := make(map[string]SolverParams, len(names))
+	for _, name := range names {
+		solverParams := SolverParams{}
+		for key, val := range params[name] {
+			solverParams[key] = val
+		}
+		solverParams["transpose"] = true
+		transposed[name] = solverParams
+	}
+	return transposed
+}
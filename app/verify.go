@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Verification is the verdict of re-executing a stored program against its matrix
+type Verification struct {
+	Algorithm    string   `json:"algorithm"`
+	RunID        int      `json:"run_id,omitempty"` // algorithm_runs row of the verified program
+	Valid        bool     `json:"valid"`
+	XorCount     int      `json:"xor_count"` // XOR gates counted from the program itself
+	Depth        int      `json:"depth"`     // Circuit depth recomputed from the program itself
+	ClaimedXor   *int     `json:"claimed_xor_count,omitempty"`
+	ClaimedDepth *int     `json:"claimed_depth,omitempty"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
+func (v *Verification) fail(format string, args ...interface{}) {
+	v.Valid = false
+	v.Errors = append(v.Errors, fmt.Sprintf(format, args...))
+}
+
+// VerifyProgram evaluates program over GF(2) and checks that it computes every
+// row of matrix. Gate operands must refer to earlier signals and the recorded
+// gate depths must match the ones recomputed from the operands.
+func VerifyProgram(matrix Matrix, program *Program) *Verification {
+	v := &Verification{Valid: true}
+
+	rows, numInputs, err := parseBinaryRows(matrix)
+	if err != nil {
+		v.fail("matris okunamadı: %v", err)
+		return v
+	}
+	if program == nil {
+		v.fail("program yok")
+		return v
+	}
+	if program.NumInputs != numInputs {
+		v.fail("giriş sayısı uyuşmuyor: matris %d, program %d", numInputs, program.NumInputs)
+		return v
+	}
+	if len(program.Outputs) != len(rows) {
+		v.fail("çıkış sayısı uyuşmuyor: matris %d, program %d", len(rows), len(program.Outputs))
+		return v
+	}
+
+	depths := make([]int, program.NumSignals())
+	for k, gate := range program.Gates {
+		signal := numInputs + k
+		if gate.A < 0 || gate.A >= signal || gate.B < 0 || gate.B >= signal {
+			v.fail("t%d önceden tanımlanmamış bir sinyal kullanıyor", k)
+			return v
+		}
+		depth := depths[gate.A]
+		if depths[gate.B] > depth {
+			depth = depths[gate.B]
+		}
+		depths[signal] = depth + 1
+		if gate.Depth != depths[signal] {
+			v.fail("t%d derinliği hatalı: kayıtlı %d, hesaplanan %d", k, gate.Depth, depths[signal])
+		}
+		if depths[signal] > v.Depth {
+			v.Depth = depths[signal]
+		}
+	}
+	v.XorCount = len(program.Gates)
+
+	values := program.Evaluate()
+	for i, row := range rows {
+		s := program.Outputs[i]
+		switch {
+		case s < 0:
+			if !row.IsZero() {
+				v.fail("y%d hesaplanmadı", i)
+			}
+		case s >= len(values):
+			v.fail("y%d tanımsız sinyale bağlı", i)
+		case !values[s].Equal(row):
+			v.fail("y%d yanlış hesaplandı", i)
+		}
+	}
+
+	return v
+}
+
+// CheckClaims compares the recounted XOR count and depth with the stored ones
+func (v *Verification) CheckClaims(xorCount, depth *int) {
+	v.ClaimedXor = xorCount
+	v.ClaimedDepth = depth
+	if xorCount != nil && *xorCount != v.XorCount {
+		v.fail("XOR sayısı uyuşmuyor: kayıtlı %d, programdan %d", *xorCount, v.XorCount)
+	}
+	if depth != nil && *depth != v.Depth {
+		v.fail("derinlik uyuşmuyor: kayıtlı %d, programdan %d", *depth, v.Depth)
+	}
+}
+
+// VerifyStoredResults re-executes the stored programs of the named solvers
+// against the matrix of record. With no names every persisted solver that has
+// a stored program is verified.
+func VerifyStoredResults(record *MatrixRecord, names []string) ([]*Verification, error) {
+	matrix, err := parseMatrixFromBinary(record.MatrixBinary)
+	if err != nil {
+		return nil, fmt.Errorf("matris parse edilemedi: %v", err)
+	}
+
+	explicit := len(names) > 0
+	var infos []*SolverInfo
+	if explicit {
+		for _, name := range names {
+			info, ok := GetSolverInfo(name)
+			if !ok {
+				return nil, fmt.Errorf("desteklenmeyen algoritma: %s", name)
+			}
+			infos = append(infos, info)
+		}
+	} else {
+		infos = RegisteredSolvers()
+	}
+
+	var verifications []*Verification
+	for _, info := range infos {
+		result := record.Result(info.Name)
+		if result == nil || result.Program == nil {
+			if explicit {
+				v := &Verification{Algorithm: info.Name, Valid: true}
+				v.fail("kayıtlı program yok")
+				verifications = append(verifications, v)
+			}
+			continue
+		}
+
+		v := VerifyProgram(matrix, result.Program)
+		v.Algorithm = info.Name
+		v.RunID = result.RunID
+		depth := result.Depth
+		if !info.HasDepth {
+			depth = nil
+		}
+		v.CheckClaims(result.XorCount, depth)
+		verifications = append(verifications, v)
+	}
+
+	return verifications, nil
+}
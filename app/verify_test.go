@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// verifyMatrix is y0 = x0 + x1, y1 = x1 + x2, y2 = x0 + x1 + x2
+var verifyMatrix = Matrix{{"1", "1", "0"}, {"0", "1", "1"}, {"1", "1", "1"}}
+
+func intPtr(v int) *int { return &v }
+
+// verifyProgram builds y0 = x0 + x1, y1 = x1 + x2, y2 = y0 + x2 directly
+func verifyProgram() *Program {
+	p := NewProgram(3, 3)
+	p.Outputs[0] = p.AddGate(0, 1)
+	p.Outputs[1] = p.AddGate(1, 2)
+	p.Outputs[2] = p.AddGate(3, 2)
+	return p
+}
+
+func TestProgramFromLinesFormats(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		xor   int
+		depth int
+	}{
+		{
+			name:  "slp",
+			lines: []string{"y0 = x0 + x1", "y1 = x1 + x2", "y2 = y0 + x2"},
+			xor:   3,
+			depth: 2,
+		},
+		{
+			name:  "boyar",
+			lines: []string{"t0 = x0 + x1 * y0 (1)", "t1 = x1 + x2 * y1 (1)", "t2 = t0 + x2 * y2 (2)"},
+			xor:   3,
+			depth: 2,
+		},
+		{
+			// Three operands become a balanced tree of two gates
+			name:  "paar",
+			lines: []string{"x3 = x0 + x1", "y0 = x3", "y1 = x1 + x2", "y2 = x0 + x1 + x2"},
+			xor:   4,
+			depth: 2,
+		},
+		{
+			// Rows without an output line are resolved to the first matching signal
+			name:  "implicit outputs",
+			lines: []string{"t0 = x0 + x1", "t1 = x1 + x2", "t2 = t0 + x2"},
+			xor:   3,
+			depth: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		program, err := programFromLines(verifyMatrix, tt.lines)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		v := VerifyProgram(verifyMatrix, program)
+		if !v.Valid {
+			t.Errorf("%s: geçerli program reddedildi: %v", tt.name, v.Errors)
+		}
+		if v.XorCount != tt.xor || v.Depth != tt.depth {
+			t.Errorf("%s: %d XOR derinlik %d, beklenen %d/%d", tt.name, v.XorCount, v.Depth, tt.xor, tt.depth)
+		}
+		v.CheckClaims(intPtr(tt.xor), intPtr(tt.depth))
+		if !v.Valid {
+			t.Errorf("%s: doğru iddialar reddedildi: %v", tt.name, v.Errors)
+		}
+	}
+}
+
+func TestProgramFromLinesRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		err   string
+	}{
+		{"undefined variable", []string{"y0 = x0 + t9"}, "tanımsız değişken"},
+		{"redefinition", []string{"y0 = x0 + x1", "y0 = x1 + x2"}, "tekrar tanımlandı"},
+		{"unknown output", []string{"y5 = x0 + x2"}, "y5 matriste yok"},
+		{"syntax", []string{"y0 x0 + x1"}, "çözümlenemedi"},
+	}
+
+	for _, tt := range tests {
+		_, err := programFromLines(verifyMatrix, tt.lines)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: hata %v, beklenen %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestVerifyProgramRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *Program) *Program
+		err    string // Expected part of the errors
+	}{
+		{"wrong row", func(p *Program) *Program { p.Outputs[1] = 3; return p }, "y1 yanlış hesaplandı"},
+		{"missing row", func(p *Program) *Program { p.Outputs[1] = -1; return p }, "y1 hesaplanmadı"},
+		{"undefined output", func(p *Program) *Program { p.Outputs[2] = 9; return p }, "y2 tanımsız sinyale bağlı"},
+		{"forward reference", func(p *Program) *Program { p.Gates[0].B = 4; return p }, "önceden tanımlanmamış"},
+		{"wrong gate depth", func(p *Program) *Program { p.Gates[2].Depth = 1; return p }, "t2 derinliği hatalı"},
+		{"outputs", func(p *Program) *Program { p.Outputs = p.Outputs[:2]; return p }, "çıkış sayısı uyuşmuyor"},
+		{"inputs", func(p *Program) *Program { p.NumInputs = 4; return p }, "giriş sayısı uyuşmuyor"},
+		{"nil", func(p *Program) *Program { return nil }, "program yok"},
+	}
+
+	for _, tt := range tests {
+		v := VerifyProgram(verifyMatrix, tt.modify(verifyProgram()))
+		if v.Valid || len(v.Errors) == 0 {
+			t.Errorf("%s: geçersiz program kabul edildi", tt.name)
+			continue
+		}
+		if !strings.Contains(strings.Join(v.Errors, "; "), tt.err) {
+			t.Errorf("%s: hatalar %v, beklenen %q", tt.name, v.Errors, tt.err)
+		}
+	}
+}
+
+func TestCheckClaimsMismatch(t *testing.T) {
+	program := verifyProgram()
+
+	v := VerifyProgram(verifyMatrix, program)
+	v.CheckClaims(intPtr(2), nil)
+	if v.Valid || !strings.Contains(strings.Join(v.Errors, "; "), "XOR sayısı uyuşmuyor") {
+		t.Errorf("yanlış XOR sayısı kabul edildi: %v", v.Errors)
+	}
+
+	v = VerifyProgram(verifyMatrix, program)
+	v.CheckClaims(intPtr(3), intPtr(1))
+	if v.Valid || !strings.Contains(strings.Join(v.Errors, "; "), "derinlik uyuşmuyor") {
+		t.Errorf("yanlış derinlik kabul edildi: %v", v.Errors)
+	}
+
+	// Without claims only the program itself is checked
+	v = VerifyProgram(verifyMatrix, program)
+	v.CheckClaims(nil, nil)
+	if !v.Valid {
+		t.Errorf("iddiasız doğrulama başarısız: %v", v.Errors)
+	}
+}
+
+func TestVerifySolverPrograms(t *testing.T) {
+	for _, info := range RegisteredSolvers() {
+		result, err := runSolver(context.Background(), info.Name, nil, verifyMatrix)
+		if err != nil {
+			t.Fatalf("%s: %v", info.Name, err)
+		}
+		v := VerifyProgram(verifyMatrix, result.Program)
+		var depth *int
+		if info.HasDepth {
+			depth = &result.Depth
+		}
+		v.CheckClaims(&result.XorCount, depth)
+		if !v.Valid {
+			t.Errorf("%s programı doğrulanamadı: %v (%v)", info.Name, v.Errors, result.Program)
+		}
+	}
+}
+
+func TestVerifyStoredResults(t *testing.T) {
+	record := &MatrixRecord{
+		MatrixBinary: "[1 1 0]\n[0 1 1]\n[1 1 1]",
+		Results: map[string]*SolverResult{
+			"slp":  {XorCount: intPtr(3), Program: verifyProgram()},
+			"paar": {XorCount: intPtr(4), Program: verifyProgram()}, // Miscounted
+		},
+	}
+
+	verifications, err := VerifyStoredResults(record, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verdicts := make(map[string]bool)
+	for _, v := range verifications {
+		verdicts[v.Algorithm] = v.Valid
+	}
+	if len(verdicts) != 2 || !verdicts["slp"] || verdicts["paar"] {
+		t.Errorf("kararlar %v, beklenen slp geçerli, paar geçersiz", verdicts)
+	}
+
+	// Explicitly requested solvers without a program are reported as invalid
+	verifications, err = VerifyStoredResults(record, []string{"boyar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verifications) != 1 || verifications[0].Valid {
+		t.Errorf("programı olmayan boyar geçerli sayıldı: %+v", verifications)
+	}
+
+	if _, err := VerifyStoredResults(record, []string{"unknown"}); err == nil {
+		t.Error("bilinmeyen algoritma kabul edildi")
+	}
+}
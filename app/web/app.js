@@ -3,19 +3,16 @@ let currentPage = 1;
 let currentLimit = 10;
 let currentFilter = '';
 let currentMatrixId = null;
-let currentFilters = {
-    hamXorMin: null,
-    hamXorMax: null,
-    boyarXorMin: null,
-    boyarXorMax: null,
-    paarXorMin: null,
-    paarXorMax: null,
-    slpXorMin: null,
-    slpXorMax: null
-};
+let currentFilters = {}; // XOR count bounds keyed by filterKey()
+let algorithms = []; // Registered solvers from /api/algorithms
+let defaultAlgorithms = []; // Solvers run when none are named
 
 // Initialize the application
-document.addEventListener('DOMContentLoaded', function() {
+document.addEventListener('DOMContentLoaded', async function() {
+    // Filters and results are built from the registered solvers
+    await loadAlgorithms();
+    renderAlgorithmFilters();
+
     // Load URL parameters
     loadFromURL();
     loadMatrices();
@@ -28,6 +25,53 @@ document.addEventListener('DOMContentLoaded', function() {
     });
 });
 
+// Load the registered solvers
+async function loadAlgorithms() {
+    try {
+        const response = await fetch('/api/algorithms');
+        const data = await response.json();
+        algorithms = data.algorithms || [];
+        defaultAlgorithms = data.defaults || [];
+    } catch (error) {
+        console.error('Error loading algorithms:', error);
+    }
+}
+
+// Solvers whose results are stored with the matrix
+function storedAlgorithms() {
+    return algorithms.filter(algorithm => algorithm.persisted);
+}
+
+// Prefixes of the XOR count filters: the raw count and every stored solver
+function filterNames() {
+    return ['ham'].concat(storedAlgorithms().map(algorithm => algorithm.name));
+}
+
+// Key of a filter bound in currentFilters, the URL and the input ids, e.g. boyarXorMin
+function filterKey(name, bound) {
+    return `${name}Xor${bound}`;
+}
+
+// Add min/max inputs for every stored solver next to the Ham XOR filter
+function renderAlgorithmFilters() {
+    const row = document.getElementById('xorFilters');
+    storedAlgorithms().forEach(algorithm => {
+        row.insertAdjacentHTML('beforeend', `
+            <div class="col-md-3">
+                <label class="form-label">${algorithm.label} XOR Sayısı</label>
+                <div class="row">
+                    <div class="col-6">
+                        <input type="number" class="form-control form-control-sm" id="${filterKey(algorithm.name, 'Min')}" placeholder="Min">
+                    </div>
+                    <div class="col-6">
+                        <input type="number" class="form-control form-control-sm" id="${filterKey(algorithm.name, 'Max')}" placeholder="Max">
+                    </div>
+                </div>
+            </div>
+        `);
+    });
+}
+
 // Load parameters from URL
 function loadFromURL() {
     const urlParams = new URLSearchParams(window.location.search);
@@ -47,47 +91,16 @@ function loadFromURL() {
     }
     
     // Load range filters
-    const hamXorMin = urlParams.get('hamXorMin');
-    const hamXorMax = urlParams.get('hamXorMax');
-    const boyarXorMin = urlParams.get('boyarXorMin');
-    const boyarXorMax = urlParams.get('boyarXorMax');
-    const paarXorMin = urlParams.get('paarXorMin');
-    const paarXorMax = urlParams.get('paarXorMax');
-    const slpXorMin = urlParams.get('slpXorMin');
-    const slpXorMax = urlParams.get('slpXorMax');
-    
-    if (hamXorMin) {
-        currentFilters.hamXorMin = parseInt(hamXorMin);
-        document.getElementById('hamXorMin').value = hamXorMin;
-    }
-    if (hamXorMax) {
-        currentFilters.hamXorMax = parseInt(hamXorMax);
-        document.getElementById('hamXorMax').value = hamXorMax;
-    }
-    if (boyarXorMin) {
-        currentFilters.boyarXorMin = parseInt(boyarXorMin);
-        document.getElementById('boyarXorMin').value = boyarXorMin;
-    }
-    if (boyarXorMax) {
-        currentFilters.boyarXorMax = parseInt(boyarXorMax);
-        document.getElementById('boyarXorMax').value = boyarXorMax;
-    }
-    if (paarXorMin) {
-        currentFilters.paarXorMin = parseInt(paarXorMin);
-        document.getElementById('paarXorMin').value = paarXorMin;
-    }
-    if (paarXorMax) {
-        currentFilters.paarXorMax = parseInt(paarXorMax);
-        document.getElementById('paarXorMax').value = paarXorMax;
-    }
-    if (slpXorMin) {
-        currentFilters.slpXorMin = parseInt(slpXorMin);
-        document.getElementById('slpXorMin').value = slpXorMin;
-    }
-    if (slpXorMax) {
-        currentFilters.slpXorMax = parseInt(slpXorMax);
-        document.getElementById('slpXorMax').value = slpXorMax;
-    }
+    filterNames().forEach(name => {
+        ['Min', 'Max'].forEach(bound => {
+            const key = filterKey(name, bound);
+            const value = urlParams.get(key);
+            if (value) {
+                currentFilters[key] = parseInt(value);
+                document.getElementById(key).value = value;
+            }
+        });
+    });
 }
 
 // Update URL with current parameters
@@ -105,30 +118,14 @@ function updateURL() {
     }
     
     // Add range filters
-    if (currentFilters.hamXorMin !== null) {
-        params.set('hamXorMin', currentFilters.hamXorMin);
-    }
-    if (currentFilters.hamXorMax !== null) {
-        params.set('hamXorMax', currentFilters.hamXorMax);
-    }
-    if (currentFilters.boyarXorMin !== null) {
-        params.set('boyarXorMin', currentFilters.boyarXorMin);
-    }
-    if (currentFilters.boyarXorMax !== null) {
-        params.set('boyarXorMax', currentFilters.boyarXorMax);
-    }
-    if (currentFilters.paarXorMin !== null) {
-        params.set('paarXorMin', currentFilters.paarXorMin);
-    }
-    if (currentFilters.paarXorMax !== null) {
-        params.set('paarXorMax', currentFilters.paarXorMax);
-    }
-    if (currentFilters.slpXorMin !== null) {
-        params.set('slpXorMin', currentFilters.slpXorMin);
-    }
-    if (currentFilters.slpXorMax !== null) {
-        params.set('slpXorMax', currentFilters.slpXorMax);
-    }
+    filterNames().forEach(name => {
+        ['Min', 'Max'].forEach(bound => {
+            const key = filterKey(name, bound);
+            if (currentFilters[key] != null) {
+                params.set(key, currentFilters[key]);
+            }
+        });
+    });
     
     const newURL = window.location.pathname + (params.toString() ? '?' + params.toString() : '');
     window.history.pushState({}, '', newURL);
@@ -172,6 +169,11 @@ function setupEventListeners() {
         recalculateMatrix();
     });
 
+    // Verify button
+    document.getElementById('verifyBtn').addEventListener('click', function() {
+        verifyMatrix();
+    });
+
     // Calculate inverse button
     document.getElementById('calculateInverseBtn').addEventListener('click', function() {
         console.log('Calculate inverse button clicked!');
@@ -213,30 +215,14 @@ async function loadMatrices() {
         }
 
         // Add range filters
-        if (currentFilters.hamXorMin !== null) {
-            params.append('ham_xor_min', currentFilters.hamXorMin);
-        }
-        if (currentFilters.hamXorMax !== null) {
-            params.append('ham_xor_max', currentFilters.hamXorMax);
-        }
-        if (currentFilters.boyarXorMin !== null) {
-            params.append('boyar_xor_min', currentFilters.boyarXorMin);
-        }
-        if (currentFilters.boyarXorMax !== null) {
-            params.append('boyar_xor_max', currentFilters.boyarXorMax);
-        }
-        if (currentFilters.paarXorMin !== null) {
-            params.append('paar_xor_min', currentFilters.paarXorMin);
-        }
-        if (currentFilters.paarXorMax !== null) {
-            params.append('paar_xor_max', currentFilters.paarXorMax);
-        }
-        if (currentFilters.slpXorMin !== null) {
-            params.append('slp_xor_min', currentFilters.slpXorMin);
-        }
-        if (currentFilters.slpXorMax !== null) {
-            params.append('slp_xor_max', currentFilters.slpXorMax);
-        }
+        filterNames().forEach(name => {
+            ['Min', 'Max'].forEach(bound => {
+                const value = currentFilters[filterKey(name, bound)];
+                if (value != null) {
+                    params.append(`${name}_xor_${bound.toLowerCase()}`, value);
+                }
+            });
+        });
 
         // Add cache busting parameter every 30 seconds
         const cacheKey = Math.floor(Date.now() / 30000);
@@ -347,22 +333,14 @@ function displayMatrices(matrices) {
                             </div>
                         </div>
                         <div class="row mt-2">
-                            <div class="col-4">
-                                <div class="algorithm-result result-boyar">
-                                    <strong>Boyar:</strong> ${matrix.boyar_xor_count || 'N/A'}
-                                    ${matrix.boyar_depth ? `(D:${matrix.boyar_depth})` : ''}
-                                </div>
-                            </div>
-                            <div class="col-4">
-                                <div class="algorithm-result result-paar">
-                                    <strong>Paar:</strong> ${matrix.paar_xor_count || 'N/A'}
-                                </div>
-                            </div>
-                            <div class="col-4">
-                                <div class="algorithm-result result-slp">
-                                    <strong>SLP:</strong> ${matrix.slp_xor_count || 'N/A'}
+                            ${storedAlgorithms().map(algorithm => `
+                            <div class="col">
+                                <div class="algorithm-result result-${algorithm.name}">
+                                    <strong>${algorithm.label}:</strong> ${matrix[algorithm.name + '_xor_count'] || 'N/A'}
+                                    ${algorithm.has_depth && matrix[algorithm.name + '_depth'] ? `(D:${matrix[algorithm.name + '_depth']})` : ''}
                                 </div>
                             </div>
+                            `).join('')}
                         </div>
                         <div class="row mt-2">
                             <div class="col-12">
@@ -655,24 +633,7 @@ function displayMatrixDetails(matrix) {
                     <strong>Grup:</strong> ${matrix.group || 'Belirtilmemiş'}
                 </div>
                 
-                <div class="algorithm-result result-boyar mb-3">
-                    <strong>Boyar SLP:</strong><br>
-                    XOR: ${matrix.boyar_xor_count || 'Hesaplanmamış'}<br>
-                    Derinlik: ${matrix.boyar_depth || 'N/A'}<br>
-                    ${matrix.boyar_program ? `<details><summary>Program</summary><pre>${JSON.stringify(JSON.parse(matrix.boyar_program), null, 2)}</pre></details>` : ''}
-                </div>
-                
-                <div class="algorithm-result result-paar mb-3">
-                    <strong>Paar Algoritması:</strong><br>
-                    XOR: ${matrix.paar_xor_count || 'Hesaplanmamış'}<br>
-                    ${matrix.paar_program ? `<details><summary>Program</summary><pre>${JSON.stringify(JSON.parse(matrix.paar_program), null, 2)}</pre></details>` : ''}
-                </div>
-                
-                <div class="algorithm-result result-slp mb-3">
-                    <strong>SLP Heuristic:</strong><br>
-                    XOR: ${matrix.slp_xor_count || 'Hesaplanmamış'}<br>
-                    ${matrix.slp_program ? `<details><summary>Program</summary><pre>${JSON.stringify(JSON.parse(matrix.slp_program), null, 2)}</pre></details>` : ''}
-                </div>
+                ${storedAlgorithms().map(algorithm => algorithmResultDetails(matrix, algorithm)).join('')}
                 
                 <div class="mt-3">
                     <small class="text-muted">
@@ -687,6 +648,22 @@ function displayMatrixDetails(matrix) {
     modalBody.innerHTML = html;
 }
 
+// Stored result of one solver in the matrix details
+function algorithmResultDetails(matrix, algorithm) {
+    const field = suffix => matrix[`${algorithm.name}_${suffix}`];
+    return `
+                <div class="algorithm-result result-${algorithm.name} mb-3">
+                    <strong>${algorithm.label}:</strong><br>
+                    XOR: ${field('xor_count') || 'Hesaplanmamış'}<br>
+                    ${field('status') === 'timed_out' ? `<span class="badge bg-warning text-dark">Süre sınırı</span><br>` : ''}
+                    ${verificationBadge(field('verified'))}
+                    ${algorithm.has_depth ? `Derinlik: ${field('depth') || 'N/A'}<br>` : ''}
+                    ${field('seed') != null ? `Seed: ${field('seed')}<br>` : ''}
+                    ${field('program') ? `<details><summary>Program</summary><pre>${escapeHtml(field('program').lines.join('\n'))}</pre></details>` : ''}
+                </div>
+    `;
+}
+
 // Recalculate matrix algorithms
 async function recalculateMatrix() {
     if (!currentMatrixId) return;
@@ -701,7 +678,7 @@ async function recalculateMatrix() {
             },
             body: JSON.stringify({
                 matrix_id: currentMatrixId,
-                algorithms: ['boyar', 'paar', 'slp']
+                algorithms: defaultAlgorithms
             })
         });
         
@@ -727,6 +704,60 @@ async function recalculateMatrix() {
     }
 }
 
+// Badge showing the stored verifier verdict of an algorithm result
+function verificationBadge(verified) {
+    if (verified === true) {
+        return `<span class="badge bg-success">Doğrulandı</span><br>`;
+    }
+    if (verified === false) {
+        return `<span class="badge bg-danger">Doğrulanamadı</span><br>`;
+    }
+    return '';
+}
+
+// Verify the stored programs of the current matrix
+async function verifyMatrix() {
+    if (!currentMatrixId) return;
+    
+    try {
+        showLoading('Programlar doğrulanıyor...');
+        
+        const response = await fetch(`/api/matrices/${currentMatrixId}/verify`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json'
+            }
+        });
+        
+        if (!response.ok) {
+            throw new Error(await response.text() || 'Doğrulama başarısız');
+        }
+        
+        const data = await response.json();
+        
+        if (data.valid) {
+            showAlert('Tüm programlar doğrulandı!', 'success');
+        } else {
+            const failed = data.results
+                .filter(result => !result.valid)
+                .map(result => `${result.algorithm}: ${(result.errors || []).join(', ')}`);
+            showAlert('Doğrulanamayan programlar: ' + escapeHtml(failed.join('; ')), 'danger');
+        }
+        
+        // Show the stored verdicts
+        const matrixResponse = await fetch(`/api/matrices/${currentMatrixId}`);
+        if (matrixResponse.ok) {
+            displayMatrixDetails(await matrixResponse.json());
+        }
+        
+    } catch (error) {
+        console.error('Error verifying matrix:', error);
+        showAlert('Doğrulama sırasında hata oluştu: ' + error.message, 'danger');
+    } finally {
+        hideLoading();
+    }
+}
+
 // Toggle input method between text and file
 function toggleInputMethod(method) {
     const textSection = document.getElementById('textInputSection');
@@ -1156,25 +1187,14 @@ window.checkModalState = function() {
 
 // Filter functions
 function applyFilters() {
-    // Get filter values
-    const hamXorMin = document.getElementById('hamXorMin').value;
-    const hamXorMax = document.getElementById('hamXorMax').value;
-    const boyarXorMin = document.getElementById('boyarXorMin').value;
-    const boyarXorMax = document.getElementById('boyarXorMax').value;
-    const paarXorMin = document.getElementById('paarXorMin').value;
-    const paarXorMax = document.getElementById('paarXorMax').value;
-    const slpXorMin = document.getElementById('slpXorMin').value;
-    const slpXorMax = document.getElementById('slpXorMax').value;
-
-    // Update current filters
-    currentFilters.hamXorMin = hamXorMin ? parseInt(hamXorMin) : null;
-    currentFilters.hamXorMax = hamXorMax ? parseInt(hamXorMax) : null;
-    currentFilters.boyarXorMin = boyarXorMin ? parseInt(boyarXorMin) : null;
-    currentFilters.boyarXorMax = boyarXorMax ? parseInt(boyarXorMax) : null;
-    currentFilters.paarXorMin = paarXorMin ? parseInt(paarXorMin) : null;
-    currentFilters.paarXorMax = paarXorMax ? parseInt(paarXorMax) : null;
-    currentFilters.slpXorMin = slpXorMin ? parseInt(slpXorMin) : null;
-    currentFilters.slpXorMax = slpXorMax ? parseInt(slpXorMax) : null;
+    // Update current filters from the inputs
+    filterNames().forEach(name => {
+        ['Min', 'Max'].forEach(bound => {
+            const key = filterKey(name, bound);
+            const value = document.getElementById(key).value;
+            currentFilters[key] = value ? parseInt(value) : null;
+        });
+    });
 
     // Reset to first page and reload
     currentPage = 1;
@@ -1190,26 +1210,14 @@ function applyFilters() {
 
 function clearFilters() {
     // Clear all filter inputs
-    document.getElementById('hamXorMin').value = '';
-    document.getElementById('hamXorMax').value = '';
-    document.getElementById('boyarXorMin').value = '';
-    document.getElementById('boyarXorMax').value = '';
-    document.getElementById('paarXorMin').value = '';
-    document.getElementById('paarXorMax').value = '';
-    document.getElementById('slpXorMin').value = '';
-    document.getElementById('slpXorMax').value = '';
+    filterNames().forEach(name => {
+        ['Min', 'Max'].forEach(bound => {
+            document.getElementById(filterKey(name, bound)).value = '';
+        });
+    });
 
     // Clear current filters
-    currentFilters = {
-        hamXorMin: null,
-        hamXorMax: null,
-        boyarXorMin: null,
-        boyarXorMax: null,
-        paarXorMin: null,
-        paarXorMax: null,
-        slpXorMin: null,
-        slpXorMax: null
-    };
+    currentFilters = {};
 
     // Reset to first page and reload
     currentPage = 1;
@@ -1231,7 +1239,7 @@ async function bulkRecalculate() {
                 'Content-Type': 'application/json'
             },
             body: JSON.stringify({
-                algorithms: ['boyar', 'paar', 'slp'],
+                algorithms: defaultAlgorithms,
                 limit: 100
             })
         });